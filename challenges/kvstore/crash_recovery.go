@@ -8,12 +8,20 @@ import (
 	. "github.com/st3v3nmw/lsfr/internal/attest"
 )
 
+// CrashRecovery checks that acknowledged writes survive SIGKILL.
+//
+// Servers may implement POST /digest to speed up checking large datasets.
+// Its body is a list of keys, one per line. It responds with the hex SHA-256
+// of the keys and their values in the order given, each key and each value
+// followed by a NUL byte, leaving out missing keys. Without it, every key is
+// read back with GET /kv/{key}.
 func CrashRecovery() *Suite {
 	return New().
 		// 0
 		Setup(func(do *Do) {
 			do.Start("node")
 		}).
+		Dataset(DatasetSpec{Path: "/kv/%s", Digest: "/digest"}).
 
 		// 1
 		Test("Basic WAL Durability", func(do *Do) {
@@ -100,45 +108,40 @@ func CrashRecovery() *Suite {
 				"cycle:crash_4": "crash_data_4",
 			}
 
-			for key, expectedValue := range allHistoricalData {
-				do.HTTP("node", "GET", fmt.Sprintf("/kv/%s", key)).T().
-					Status(Is(200)).
-					Body(Is(expectedValue)).
-					Assert("Your server should preserve all historical data across multiple crashes.\n" +
-						"Ensure the WAL is never truncated until after a successful checkpoint.\n" +
-						"Recovery should load the latest snapshot (if any) and replay all subsequent WAL operations.")
-			}
+			do.VerifyDataset("node", allHistoricalData,
+				"Your server should preserve all historical data across multiple crashes.\n"+
+					"Ensure the WAL is never truncated until after a successful checkpoint.\n"+
+					"Recovery should load the latest snapshot (if any) and replay all subsequent WAL operations.")
 		}).
 
 		// 3
 		Test("Rapid Write Burst Before Crash", func(do *Do) {
 			// Write many operations rapidly in sequence
+			burst := map[string]string{}
 			for i := 1; i <= 500; i++ {
-				do.HTTP("node", "PUT", fmt.Sprintf("/kv/burst:%d", i), strings.Repeat("data", 250)).T().
+				key, value := fmt.Sprintf("burst:%d", i), strings.Repeat("data", 250)
+				do.HTTP("node", "PUT", "/kv/"+key, value).T().
 					Status(Is(200)).
 					Assert("Your server should accept PUT requests.\n" +
 						"Ensure your HTTP handler processes PUT requests correctly.")
+				burst[key] = value
 			}
 
 			// Crash immediately
 			do.Restart("node", syscall.SIGKILL)
 
 			// Verify all acknowledged writes survived
-			for i := 1; i <= 500; i++ {
-				do.HTTP("node", "GET", fmt.Sprintf("/kv/burst:%d", i)).T().
-					Status(Is(200)).
-					Body(Is(strings.Repeat("data", 250))).
-					Assert("Your server acknowledged the PUT but lost the data after crashing.\n" +
-						"Ensure writes are durably stored before acknowledging them to the client.\n" +
-						"Call fsync/flush after writing to WAL, or batch operations and sync before responding.")
-			}
+			do.VerifyDataset("node", burst,
+				"Your server acknowledged the PUT but lost the data after crashing.\n"+
+					"Ensure writes are durably stored before acknowledging them to the client.\n"+
+					"Call fsync/flush after writing to WAL, or batch operations and sync before responding.")
 		}).
 
 		// 4
 		Test("Test Recovery When Under Concurrent Load", func(do *Do) {
 			// Generate concurrent load
-			putFn := func(key, value string) func() {
-				return func() {
+			putFn := func(key, value string) func(*Do) {
+				return func(do *Do) {
 					do.HTTP("node", "PUT", "/kv/large:"+key, value).T().
 						Status(Is(200)).
 						Assert("Your server should handle concurrent PUT requests.\n" +
@@ -146,9 +149,12 @@ func CrashRecovery() *Suite {
 				}
 			}
 
-			fns := []func(){}
+			fns := []func(*Do){}
+			written := map[string]string{}
 			for i := 1; i <= 10_000; i++ {
-				fns = append(fns, putFn(fmt.Sprintf("key%d", i), strings.Repeat("x", 100)))
+				key, value := fmt.Sprintf("key%d", i), strings.Repeat("x", 100)
+				fns = append(fns, putFn(key, value))
+				written["large:"+key] = value
 			}
 
 			do.Concurrently(fns...)
@@ -157,13 +163,9 @@ func CrashRecovery() *Suite {
 			do.Restart("node", syscall.SIGKILL)
 
 			// Verify all acknowledged writes survived
-			for i := 1; i <= 10_000; i++ {
-				do.HTTP("node", "GET", fmt.Sprintf("/kv/large:key%d", i)).T().
-					Status(Is(200)).
-					Body(Is(strings.Repeat("x", 100))).
-					Assert("Your server should preserve all acknowledged writes after crash.\n" +
-						"Ensure your WAL writes are thread-safe and durably stored before acknowledging.\n" +
-						"If recovery is slow, consider implementing checkpointing to reduce replay time.")
-			}
+			do.VerifyDataset("node", written,
+				"Your server should preserve all acknowledged writes after crash.\n"+
+					"Ensure your WAL writes are thread-safe and durably stored before acknowledging.\n"+
+					"If recovery is slow, consider implementing checkpointing to reduce replay time.")
 		})
 }
@@ -13,6 +13,12 @@ func HTTPAPI() *Suite {
 		Setup(func(do *Do) {
 			do.Start("node")
 		}).
+		Preflight("node",
+			Endpoint{Method: "PUT", Path: "/kv/preflight:key", Body: "value"},
+			Endpoint{Method: "GET", Path: "/kv/preflight:key"},
+			Endpoint{Method: "DELETE", Path: "/kv/preflight:key"},
+			Endpoint{Method: "DELETE", Path: "/clear"},
+		).
 
 		// 1
 		Test("PUT Basic Operations", func(do *Do) {
@@ -256,8 +262,8 @@ func HTTPAPI() *Suite {
 		// 8
 		Test("Concurrent Operations - Different Keys", func(do *Do) {
 			// Test concurrent writes to different keys
-			putFn := func(key, value string) func() {
-				return func() {
+			putFn := func(key, value string) func(*Do) {
+				return func(do *Do) {
 					do.HTTP("node", "PUT", "/kv/concurrent:"+key, value).T().
 						Status(Is(200)).
 						Assert("Your server should handle concurrent PUT requests.\n" +
@@ -265,7 +271,7 @@ func HTTPAPI() *Suite {
 				}
 			}
 
-			fns := []func(){}
+			fns := []func(*Do){}
 			for i := 1; i <= 100; i++ {
 				fns = append(fns, putFn(fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i)))
 			}
@@ -286,8 +292,8 @@ func HTTPAPI() *Suite {
 		Test("Concurrent Operations - Same Key", func(do *Do) {
 			// Test concurrent writes to the SAME key
 			// Last write should win, but no crashes or data corruption
-			putFn := func(key, value string) func() {
-				return func() {
+			putFn := func(key, value string) func(*Do) {
+				return func(do *Do) {
 					do.HTTP("node", "PUT", "/kv/concurrent:"+key, value).T().
 						Status(Is(200)).
 						Assert("Your server should handle concurrent PUT requests.\n" +
@@ -295,7 +301,7 @@ func HTTPAPI() *Suite {
 				}
 			}
 
-			raceFns := []func(){}
+			raceFns := []func(*Do){}
 			expectedValues := []string{}
 			for i := 1; i <= 100; i++ {
 				raceFns = append(raceFns, putFn("racekey", fmt.Sprintf("value%d", i)))
@@ -6,6 +6,7 @@ func init() {
 	challenge := &registry.Challenge{
 		Name:    "Distributed Key-Value Store",
 		Summary: "Build a distributed key-value store from scratch using the Raft consensus algorithm.",
+		Oracle:  Model,
 	}
 
 	challenge.AddStage("http-api", "Store and Retrieve Data", HTTPAPI)
@@ -17,5 +18,7 @@ func init() {
 	challenge.AddStage("fault-tolerance", "Cluster Survives Failures and Partitions", FaultTolerance)
 	challenge.AddStage("log-compaction", "System Manages Log Growth", LogCompaction)
 
+	challenge.AddBonusStage("protocol-fuzzing", "Server Survives Malformed Input", ProtocolFuzzing)
+
 	registry.RegisterChallenge("kv-store", challenge)
 }
@@ -11,8 +11,11 @@ package kvstore
 // Observability (black-box testing via APIs):
 //   - GET /cluster/info: role, term, leader, votedFor
 //   - GET/PUT/DELETE /kv/*: 307 redirect to leader, 503 if no leader
-//   - POST /cluster/partition: isolate nodes (persists across restarts)
-//   - POST /cluster/heal: restore connectivity
+//
+// Cluster:
+//   - Each node is started with --id=<name> and --peers=<name>=<addr>,...
+//   - Peer addresses are harness proxies, so do.Partition and do.Heal can
+//     cut and restore links for any implementation (persists across restarts)
 //
 // Scenarios:
 //   1. Leader Election Completes
@@ -24,18 +27,12 @@ package kvstore
 //   7. Majority Partition Elects Leader
 //   8. Healing After Partition
 
-import (
-	"fmt"
-
-	. "github.com/st3v3nmw/lsfr/internal/attest"
-)
+import . "github.com/st3v3nmw/lsfr/internal/attest"
 
 func LeaderElection() *Suite {
 	return New().
 		// 0
 		Setup(func(do *Do) {
-			for i := range 5 {
-				do.Start(fmt.Sprintf("node-%d", i+1))
-			}
+			do.StartCluster("node", 5, PeerTemplate("--id={{.Name}}"), PeerTemplate("--peers={{.Peers}}"))
 		})
 }
@@ -0,0 +1,156 @@
+package kvstore
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// possibleValues holds the states a key may be in. Usually there's only one,
+// but the order of concurrent writes isn't known until the key is read.
+type possibleValues struct {
+	values []string
+	absent bool
+}
+
+// model is a known-good in-memory key-value store that the HTTP API is
+// checked against in oracle mode.
+type model struct {
+	keys map[string]*possibleValues
+}
+
+// Model returns a fresh model of the key-value store for oracle mode.
+func Model() Oracle {
+	return &model{keys: make(map[string]*possibleValues)}
+}
+
+func (m *model) Check(op Operation) string {
+	if op.Method == "DELETE" && op.Path == "/clear" {
+		if op.Status == 200 {
+			clear(m.keys)
+		}
+
+		return ""
+	}
+
+	key, ok := strings.CutPrefix(op.Path, "/kv/")
+	if !ok {
+		return ""
+	}
+
+	// Redirects and unavailable errors come from clusters without a leader,
+	// so the operation may or may not have been applied
+	unavailable := (op.Status >= 300 && op.Status < 400) || op.Status >= 500
+	uncertain := op.Concurrent || unavailable
+
+	switch {
+	case key == "":
+		return expectResponse(op, 400, "key cannot be empty\n")
+	case op.Method == "PUT" && op.Body == "":
+		return expectResponse(op, 400, "value cannot be empty\n")
+	case op.Method == "PUT":
+		m.write(key, &possibleValues{values: []string{op.Body}}, uncertain)
+		if uncertain {
+			return ""
+		}
+
+		return expectResponse(op, 200, "")
+	case op.Method == "DELETE":
+		m.write(key, &possibleValues{absent: true}, uncertain)
+		if uncertain {
+			return ""
+		}
+
+		return expectResponse(op, 200, "")
+	case op.Method == "GET":
+		if unavailable {
+			return ""
+		}
+
+		return m.read(key, op)
+	default:
+		return ""
+	}
+}
+
+// write records the key's new state, or adds it to the possible states if
+// it's uncertain whether or when the write was applied.
+func (m *model) write(key string, state *possibleValues, uncertain bool) {
+	if !uncertain {
+		m.keys[key] = state
+		return
+	}
+
+	current := m.possible(key)
+	current.absent = current.absent || state.absent
+	for _, value := range state.values {
+		if !slices.Contains(current.values, value) {
+			current.values = append(current.values, value)
+		}
+	}
+}
+
+// read checks a GET against the key's possible states. Once a sequential
+// read has seen one, the others are ruled out.
+func (m *model) read(key string, op Operation) string {
+	current := m.possible(key)
+
+	var seen *possibleValues
+	switch {
+	case op.Status == 200 && slices.Contains(current.values, op.Response):
+		seen = &possibleValues{values: []string{op.Response}}
+	case op.Status == 404 && op.Response == "key not found\n" && current.absent:
+		seen = &possibleValues{absent: true}
+	}
+
+	if seen == nil {
+		return fmt.Sprintf("Expected response: %s\n  Actual response: %d %q",
+			describe(current), op.Status, op.Response)
+	}
+
+	if !op.Concurrent {
+		m.keys[key] = seen
+	}
+
+	return ""
+}
+
+// possible returns the key's possible states, which start out absent.
+func (m *model) possible(key string) *possibleValues {
+	current, ok := m.keys[key]
+	if !ok {
+		current = &possibleValues{absent: true}
+		m.keys[key] = current
+	}
+
+	return current
+}
+
+// describe lists the responses a GET may get.
+func describe(current *possibleValues) string {
+	var responses []string
+	for _, value := range current.values {
+		responses = append(responses, fmt.Sprintf("200 %q", value))
+	}
+
+	if current.absent {
+		responses = append(responses, fmt.Sprintf("404 %q", "key not found\n"))
+	}
+
+	return strings.Join(responses, " or ")
+}
+
+// expectResponse compares the status, and the body unless it's empty.
+func expectResponse(op Operation, status int, body string) string {
+	if op.Status == status && (body == "" || op.Response == body) {
+		return ""
+	}
+
+	if body == "" {
+		return fmt.Sprintf("Expected status: %d\n  Actual status: %d", status, op.Status)
+	}
+
+	return fmt.Sprintf("Expected response: %d %q\n  Actual response: %d %q", status, body, op.Status, op.Response)
+}
@@ -3,6 +3,8 @@ package kvstore
 import (
 	"fmt"
 	"strings"
+	"syscall"
+	"time"
 
 	. "github.com/st3v3nmw/lsfr/internal/attest"
 )
@@ -40,6 +42,11 @@ func Persistence() *Suite {
 						"Ensure basic storage functionality works correctly.")
 			}
 
+			do.Signal("node", syscall.SIGTERM).T().
+				ExitAfter(LessThan(do.Scaled(2 * time.Second))).
+				Assert("Your server should shut down promptly on SIGTERM.\n" +
+					"Handle SIGTERM by flushing pending writes and exiting, rather than waiting to be killed.")
+
 			do.Restart("node")
 
 			// Verify data survived the restart
@@ -101,8 +108,8 @@ func Persistence() *Suite {
 		// 3
 		Test("Test Persistence When Under Concurrent Load", func(do *Do) {
 			// Generate concurrent load
-			putFn := func(key, value string) func() {
-				return func() {
+			putFn := func(key, value string) func(*Do) {
+				return func(do *Do) {
 					do.HTTP("node", "PUT", "/kv/load:"+key, value).T().
 						Status(Is(200)).
 						Assert("Your server should handle concurrent PUT requests under load.\n" +
@@ -110,7 +117,7 @@ func Persistence() *Suite {
 				}
 			}
 
-			fns := []func(){}
+			fns := []func(*Do){}
 			for i := 1; i <= 10_000; i++ {
 				fns = append(fns, putFn(fmt.Sprintf("concurrent%d", i), fmt.Sprintf("value%d", i)))
 			}
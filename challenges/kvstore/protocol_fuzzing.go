@@ -0,0 +1,43 @@
+package kvstore
+
+import (
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// ProtocolFuzzing checks that the server survives malformed input. Any
+// response, errors included, passes as long as the server keeps accepting
+// connections and serving requests afterwards.
+func ProtocolFuzzing() *Suite {
+	return New().
+		// 0
+		Setup(func(do *Do) {
+			do.Start("node")
+		}).
+
+		// 1
+		Test("Malformed Requests Don't Crash Server", func(do *Do) {
+			do.Fuzz("node", MalformedHTTPRequests("/kv/fuzz:key")...).T().
+				Assert("Your server should survive malformed HTTP requests.\n" +
+					"Ensure errors from parsing bad request lines, headers and bodies are returned, not panicked on.")
+
+			do.HTTP("node", "PUT", "/kv/fuzz:alive", "still here").T().
+				Status(Is(200)).
+				Assert("Your server should keep serving requests after receiving malformed input.\n" +
+					"Ensure a bad request only affects its own connection.")
+		}).
+
+		// 2
+		Test("Invalid UTF-8 Keys Don't Crash Server", func(do *Do) {
+			for _, method := range []string{"PUT", "GET", "DELETE"} {
+				do.Fuzz("node", InvalidUTF8Requests(method, "/kv/")...).T().
+					Assert("Your server should survive keys that are not valid UTF-8.\n" +
+						"Either store them as raw bytes or reject them with an error response.")
+			}
+
+			do.HTTP("node", "GET", "/kv/fuzz:alive").T().
+				Status(Is(200)).
+				Body(Is("still here")).
+				Assert("Your server should keep its data intact after receiving invalid keys.\n" +
+					"Ensure invalid input doesn't corrupt your storage.")
+		})
+}
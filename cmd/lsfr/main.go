@@ -18,19 +18,71 @@ func main() {
 		Name:  "lsfr",
 		Usage: "Build complex systems from scratch",
 		Commands: []*commands.Command{
+			{
+				Name:   "start",
+				Usage:  "Set up your first challenge step by step",
+				Action: cli.StartChallenge,
+			},
 			{
 				Name:      "init",
 				Aliases:   []string{"i"},
 				Usage:     "Initialize a challenge",
 				ArgsUsage: "<challenge> [path]",
-				Action:    cli.InitChallenge,
+				Flags: []commands.Flag{
+					&commands.BoolFlag{
+						Name:  "state-dir",
+						Usage: "Keep progress in your state directory instead of lsfr.yaml, so it's shared by clones of the challenge",
+					},
+				},
+				Action: cli.InitChallenge,
 			},
 			{
 				Name:      "test",
 				Aliases:   []string{"t"},
 				Usage:     "Test current or specific stage",
-				ArgsUsage: "[stage]",
-				Action:    cli.TestStage,
+				ArgsUsage: "[stage] [-- <args for run.sh>]",
+				Flags: []commands.Flag{
+					&commands.BoolFlag{
+						Name:  "manual-start",
+						Usage: "Print process arguments and wait for you to start them, e.g. under a debugger",
+					},
+					&commands.BoolFlag{
+						Name:  "experimental",
+						Usage: "Allow running stages that are still under development",
+					},
+					&commands.StringFlag{
+						Name:  "record",
+						Usage: "Save the run's HTTP exchanges to `FILE`, e.g. when testing a reference implementation",
+					},
+					&commands.StringFlag{
+						Name:  "replay",
+						Usage: "Dry-run the stage against HTTP exchanges saved with --record, without starting processes",
+					},
+					&commands.BoolFlag{
+						Name:  "oracle",
+						Usage: "Check every operation against a reference model and report where your server diverges",
+					},
+					&commands.DurationFlag{
+						Name:  "jitter",
+						Usage: "Delay each request by a random duration of up to `DURATION` to flush out timing assumptions",
+					},
+					&commands.Uint64Flag{
+						Name:  "seed",
+						Usage: "Seed for --jitter delays, to reproduce a run (default: random)",
+					},
+					&commands.IntFlag{
+						Name:  "max-rps",
+						Usage: "Cap requests per second at `N`, or -1 to never throttle (default: only near the local port limit)",
+					},
+				},
+				Action: cli.TestStage,
+			},
+			{
+				Name:      "verify",
+				Aliases:   []string{"v"},
+				Usage:     "Re-run a completed stage",
+				ArgsUsage: "<stage> [-- <args for run.sh>]",
+				Action:    cli.VerifyStage,
 			},
 			{
 				Name:    "next",
@@ -44,6 +96,29 @@ func main() {
 				Usage:   "Show current progress",
 				Action:  cli.ShowStatus,
 			},
+			{
+				Name:    "explain",
+				Aliases: []string{"e"},
+				Usage:   "Explain the first failure of the latest run in more detail",
+				Action:  cli.ExplainFailure,
+			},
+			{
+				Name:      "certificate",
+				Usage:     "Show what a completion certificate records and check it wasn't edited",
+				ArgsUsage: "[file]",
+				Action:    cli.ShowCertificate,
+			},
+			{
+				Name:  "ps",
+				Usage: "Show processes started by lsfr that are still running",
+				Flags: []commands.Flag{
+					&commands.BoolFlag{
+						Name:  "kill",
+						Usage: "Kill the processes and their children",
+					},
+				},
+				Action: cli.ListProcesses,
+			},
 			{
 				Name:    "list",
 				Aliases: []string{"l", "ls"},
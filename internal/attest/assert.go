@@ -1,15 +1,30 @@
 package attest
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml"
+	"github.com/tidwall/gjson"
 )
 
 // eventually checks that the condition becomes true within the given period.
@@ -30,22 +45,31 @@ func eventually(ctx context.Context, condition func() bool, timeout, pollInterva
 	return false
 }
 
-// consistently checks that the condition is always true for the given period.
-func consistently(ctx context.Context, condition func() bool, timeout, pollInterval time.Duration) bool {
+// consistently checks that the condition is always true for the given period,
+// polling often enough to take at least minSamples samples and carrying on
+// past the period if the condition is too slow to check that many times.
+// It returns the number of samples taken.
+func consistently(ctx context.Context, condition func() bool, timeout, pollInterval time.Duration, minSamples int) (int, bool) {
+	if minSamples > 0 {
+		pollInterval = min(pollInterval, timeout/time.Duration(minSamples))
+	}
+
 	deadline := time.Now().Add(timeout)
 
-	for time.Now().Before(deadline) {
+	samples := 0
+	for samples < minSamples || time.Now().Before(deadline) {
 		select {
 		case <-ctx.Done():
-			return false
+			return samples, false
 		case <-time.After(pollInterval):
+			samples++
 			if !condition() {
-				return false
+				return samples, false
 			}
 		}
 	}
 
-	return true
+	return samples, true
 }
 
 // Assert defines the interface for executing and validating test assertions.
@@ -63,16 +87,160 @@ type Assert interface {
 
 var _ Assert = (*HTTPAssert)(nil)
 var _ Assert = (*CLIAssert)(nil)
+var _ Assert = (*FuzzAssert)(nil)
+var _ Assert = (*LogsAssert)(nil)
+var _ Assert = (*TCPAssert)(nil)
+var _ Assert = (*HTTPRawAssert)(nil)
+var _ Assert = (*HTTPConnAssert)(nil)
+var _ Assert = (*UDPAssert)(nil)
+var _ Assert = (*DNSAssert)(nil)
+var _ Assert = (*WSAssert)(nil)
+var _ Assert = (*GRPCAssert)(nil)
+var _ Assert = (*RESPAssert)(nil)
+var _ Assert = (*SSEAssert)(nil)
+var _ Assert = (*FDAssert)(nil)
 
 // AssertBase provides common assertion functionality.
 type AssertBase struct {
 	help string
+	// sampling describes the samples taken by a failed Consistently check
+	sampling string
 
 	config *Config
 }
 
 func (a *AssertBase) formatHelp() string {
-	return "\n\n  " + strings.ReplaceAll(a.help, "\n", "\n  ")
+	help := "\n\n  " + strings.ReplaceAll(a.help, "\n", "\n  ")
+	if a.sampling != "" {
+		help = "\n\n  " + a.sampling + help
+	}
+
+	return help
+}
+
+// mismatch describes every checker in a group that fails for value, followed
+// by the actual line (if any), or returns "" if they all pass.
+func mismatch[T any](value T, checkers []Checker[T], label, actual string) string {
+	var lines []string
+	for _, checker := range checkers {
+		if !checker.Check(value) {
+			lines = append(lines, fmt.Sprintf("Expected %s: %s", checkerLabel(checker, label), checker.Expected()))
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	if actual != "" {
+		lines = append(lines, actual)
+	}
+
+	return strings.Join(lines, "\n  ")
+}
+
+// checkerLabel returns what a checker's value is called in failure
+// messages, its description if it has one or label otherwise.
+func checkerLabel(checker any, label string) string {
+	if d, ok := checker.(described); ok {
+		return d.description()
+	}
+
+	return label
+}
+
+// jsonMismatch describes failing JSON field checkers. Several checkers on one
+// response are rendered as a table of every checked field so they can be
+// compared at a glance.
+func jsonMismatch(body string, checkers []Checker[string], actual string) string {
+	return fieldMismatch(body, checkers, "JSON", actual)
+}
+
+// yamlMismatch describes failing YAML field checkers like jsonMismatch,
+// reading the fields from the document converted to JSON.
+func yamlMismatch(doc string, checkers []Checker[string], actual string) string {
+	if checkAll(doc, checkers, nil) {
+		return ""
+	}
+
+	data, err := yaml.YAMLToJSON([]byte(doc))
+	if err != nil {
+		return joinMismatches(fmt.Sprintf("Expected YAML: a valid document\n  Actual error: %v", err), actual)
+	}
+
+	fields := make([]Checker[string], len(checkers))
+	for i, checker := range checkers {
+		fields[i] = checker.(YAMLFieldChecker).field
+	}
+
+	return fieldMismatch(string(data), fields, "YAML", actual)
+}
+
+// fieldMismatch describes failing field checkers of a JSON body, with label
+// naming the document's format.
+func fieldMismatch(body string, checkers []Checker[string], label, actual string) string {
+	if len(checkers) < 2 || checkAll(body, checkers, nil) {
+		return mismatch(body, checkers, label, actual)
+	}
+
+	type row struct{ mark, field, expected, actual string }
+
+	rows := []row{{" ", "FIELD", "EXPECTED", "ACTUAL"}}
+	for _, checker := range checkers {
+		r := row{mark: checkMark, expected: checker.Expected()}
+		if !checker.Check(body) {
+			r.mark = crossMark
+		}
+
+		if field, ok := checker.(JSONFieldChecker); ok {
+			r.field = field.field()
+			r.expected = field.checker.Expected()
+
+			result := gjson.Get(body, field.path)
+			r.actual = "(missing)"
+			if result.Exists() {
+				r.actual = truncateValue(result.Raw, 60)
+			}
+		}
+
+		rows = append(rows, r)
+	}
+
+	fieldWidth, expectedWidth := 0, 0
+	for _, r := range rows {
+		fieldWidth = max(fieldWidth, utf8.RuneCountInString(r.field))
+		expectedWidth = max(expectedWidth, utf8.RuneCountInString(r.expected))
+	}
+
+	lines := []string{fmt.Sprintf("Expected %s fields:", label)}
+	for _, r := range rows {
+		lines = append(lines, strings.TrimRight(fmt.Sprintf("  %s %-*s  %-*s  %s",
+			r.mark, fieldWidth, r.field, expectedWidth, r.expected, r.actual), " "))
+	}
+
+	return strings.Join(lines, "\n  ")
+}
+
+// truncateValue shortens s to at most n runes for display.
+func truncateValue(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	return string(runes[:n-1]) + Marks.Ellipsis
+}
+
+// joinMismatches joins the non-empty mismatch descriptions, in priority order.
+func joinMismatches(mismatches ...string) string {
+	var nonEmpty []string
+	for _, m := range mismatches {
+		if m != "" {
+			nonEmpty = append(nonEmpty, m)
+		}
+	}
+
+	return strings.Join(nonEmpty, "\n  ")
 }
 
 // HTTPAssert provides assertions for HTTP response validation.
@@ -80,12 +248,33 @@ type HTTPAssert struct {
 	AssertBase
 
 	promise        *HTTPPromise
+	traceID        string
 	responseBody   string
 	responseStatus int
+	responseHeader http.Header
+	tlsState       *tls.ConnectionState
+	proto          string
+	location       string
+	duration       time.Duration
 
-	statusCheckers []Checker[int]
-	bodyCheckers   []Checker[string]
-	jsonCheckers   []Checker[string]
+	statusCheckers    []Checker[int]
+	headerCheckers    map[string][]Checker[string]
+	cookieCheckers    map[string][]Checker[string]
+	setCookieCheckers map[string][]Checker[string]
+	bodyCheckers      []Checker[string]
+	jsonCheckers      []Checker[string]
+	yamlCheckers      []Checker[string]
+	protoCheckers     []Checker[string]
+	locationCheckers  []Checker[string]
+	durationCheckers  []Checker[time.Duration]
+
+	cnCheckers         []Checker[string]
+	sanCheckers        []Checker[string]
+	tlsVersionCheckers []Checker[string]
+	alpnCheckers       []Checker[string]
+
+	// Groups of alternatives, at least one of each must match
+	alternatives [][]*HTTPAssert
 }
 
 // Status adds expected HTTP response status code checkers.
@@ -95,6 +284,31 @@ func (a *HTTPAssert) Status(checkers ...Checker[int]) *HTTPAssert {
 	return a
 }
 
+// Header adds expected checkers for a response header's value. Repeated
+// headers are joined with ", ". All checkers must pass.
+func (a *HTTPAssert) Header(name string, checkers ...Checker[string]) *HTTPAssert {
+	name = http.CanonicalHeaderKey(name)
+	a.headerCheckers[name] = append(a.headerCheckers[name], checkers...)
+	return a
+}
+
+// Cookie adds expected checkers for the value of a cookie the response sets,
+// which must be set. All checkers must pass.
+func (a *HTTPAssert) Cookie(name string, checkers ...Checker[string]) *HTTPAssert {
+	a.cookieCheckers[name] = append(a.cookieCheckers[name], checkers...)
+	return a
+}
+
+// SetCookie adds expected checkers for the Set-Cookie line of a cookie the
+// response sets, which must be set. The line is normalized so attributes
+// have a canonical spelling and order, e.g.
+// "session=abc; Path=/; Max-Age=3600; HttpOnly; Secure; SameSite=Strict".
+// All checkers must pass.
+func (a *HTTPAssert) SetCookie(name string, checkers ...Checker[string]) *HTTPAssert {
+	a.setCookieCheckers[name] = append(a.setCookieCheckers[name], checkers...)
+	return a
+}
+
 // Body adds expected HTTP response body checkers.
 // All checkers must pass.
 func (a *HTTPAssert) Body(checkers ...Checker[string]) *HTTPAssert {
@@ -103,6 +317,8 @@ func (a *HTTPAssert) Body(checkers ...Checker[string]) *HTTPAssert {
 }
 
 // JSON adds expected checkers for a JSON field at the given gjson path.
+// To check a field as a number or boolean, pass the JSON checker to Body,
+// e.g. Body(JSON("term", GreaterThan(1))).
 // All checkers must pass.
 func (a *HTTPAssert) JSON(path string, checkers ...Checker[string]) *HTTPAssert {
 	for _, checker := range checkers {
@@ -112,75 +328,409 @@ func (a *HTTPAssert) JSON(path string, checkers ...Checker[string]) *HTTPAssert
 	return a
 }
 
+// YAML adds expected checkers for a field of a YAML response at the given
+// gjson path. To check a field as a number or boolean, pass the YAML checker
+// to Body, e.g. Body(YAML("replicas", Is(3))).
+// All checkers must pass.
+func (a *HTTPAssert) YAML(path string, checkers ...Checker[string]) *HTTPAssert {
+	for _, checker := range checkers {
+		a.yamlCheckers = append(a.yamlCheckers, YAML(path, checker))
+	}
+
+	return a
+}
+
+// Or adds alternatives, each a set of checkers, at least one of which must
+// pass on top of the assertion's other checkers. Unlike AnyOf, which
+// combines checkers for a single value, each alternative may check several,
+// e.g. either 200 with a body or a redirect:
+//
+//	Or(
+//		func(a *HTTPAssert) { a.Status(Is(200)).Body(Is("v1")) },
+//		func(a *HTTPAssert) { a.Status(Is(307)).Location(HasSuffix("/kv/a")) },
+//	)
+func (a *HTTPAssert) Or(alternatives ...func(*HTTPAssert)) *HTTPAssert {
+	if len(alternatives) < 2 {
+		panic("Or() requires at least two alternatives")
+	}
+
+	group := make([]*HTTPAssert, len(alternatives))
+	for i, build := range alternatives {
+		group[i] = a.promise.T()
+		build(group[i])
+	}
+	a.alternatives = append(a.alternatives, group)
+
+	return a
+}
+
+// Proto adds checkers for the protocol the response was served over,
+// "HTTP/1.1" or "HTTP/2.0". All checkers must pass.
+func (a *HTTPAssert) Proto(checkers ...Checker[string]) *HTTPAssert {
+	a.protoCheckers = append(a.protoCheckers, checkers...)
+	return a
+}
+
+// Location adds checkers for the redirect target in the Location header,
+// resolved against the request URL, e.g. "http://127.0.0.1:8001/kv/key".
+// Use with NoFollowRedirects. All checkers must pass.
+func (a *HTTPAssert) Location(checkers ...Checker[string]) *HTTPAssert {
+	a.locationCheckers = append(a.locationCheckers, checkers...)
+	return a
+}
+
+// Duration adds checkers for how long the request took, from sending it
+// to reading the whole response. All checkers must pass.
+func (a *HTTPAssert) Duration(checkers ...Checker[time.Duration]) *HTTPAssert {
+	a.durationCheckers = append(a.durationCheckers, checkers...)
+	return a
+}
+
+// CertificateCN adds checkers for the common name of the server's certificate.
+// All checkers must pass.
+func (a *HTTPAssert) CertificateCN(checkers ...Checker[string]) *HTTPAssert {
+	a.cnCheckers = append(a.cnCheckers, checkers...)
+	return a
+}
+
+// CertificateSANs adds checkers for the subject alternative names of the
+// server's certificate, listed as e.g. "localhost, 127.0.0.1".
+// All checkers must pass.
+func (a *HTTPAssert) CertificateSANs(checkers ...Checker[string]) *HTTPAssert {
+	a.sanCheckers = append(a.sanCheckers, checkers...)
+	return a
+}
+
+// TLSVersion adds checkers for the negotiated protocol version, e.g. "TLS 1.3".
+// All checkers must pass.
+func (a *HTTPAssert) TLSVersion(checkers ...Checker[string]) *HTTPAssert {
+	a.tlsVersionCheckers = append(a.tlsVersionCheckers, checkers...)
+	return a
+}
+
+// ALPN adds checkers for the application protocol negotiated during the
+// handshake, e.g. "h2" or "http/1.1". The client offers both.
+// All checkers must pass.
+func (a *HTTPAssert) ALPN(checkers ...Checker[string]) *HTTPAssert {
+	a.alpnCheckers = append(a.alpnCheckers, checkers...)
+	return a
+}
+
+// tlsCheck pairs a property of the TLS connection with its checkers.
+type tlsCheck struct {
+	label    string
+	value    string
+	checkers []Checker[string]
+}
+
+func (a *HTTPAssert) tlsChecks() []tlsCheck {
+	var cn, sans, version, alpn string
+	if state := a.tlsState; state != nil {
+		version = tls.VersionName(state.Version)
+		alpn = state.NegotiatedProtocol
+		if len(state.PeerCertificates) > 0 {
+			cn = state.PeerCertificates[0].Subject.CommonName
+			sans = certificateSANs(state.PeerCertificates[0])
+		}
+	}
+
+	return []tlsCheck{
+		{"certificate CN", cn, a.cnCheckers},
+		{"certificate SANs", sans, a.sanCheckers},
+		{"TLS version", version, a.tlsVersionCheckers},
+		{"ALPN", alpn, a.alpnCheckers},
+	}
+}
+
 func (a *HTTPAssert) Assert(help string) {
 	a.help = help
 
 	p := a.promise
-	switch p.timing {
-	case TimingEventually:
-		eventually(p.ctx, a.execute, p.timeout, a.config.RetryPollInterval)
-	case TimingConsistently:
-		consistently(p.ctx, a.execute, p.timeout, a.config.RetryPollInterval)
-	default:
-		a.execute()
-	}
+	a.sampling = p.run(a.execute)
+
+	p.breadcrumbs.add("%s %s → %d", p.method, p.path, a.responseStatus)
 
 	a.check()
+	a.mirror()
+}
+
+// mirror checks the operation against the oracle, if any, once its own
+// checkers have passed, so retries and samples are only mirrored once.
+func (a *HTTPAssert) mirror() {
+	p := a.promise
+	if p.oracle == nil || p.bodyReader != nil || p.bodyFile != "" {
+		return
+	}
+
+	divergence := p.oracle.mirror(Operation{
+		Method:     p.method,
+		Path:       p.path,
+		Body:       string(p.body),
+		Status:     a.responseStatus,
+		Response:   a.responseBody,
+		Concurrent: p.concurrent,
+	})
+	if divergence != "" {
+		panic(fmt.Sprintf("%s\n  Trace: %s", divergence, a.traceID))
+	}
 }
 
 func (a *HTTPAssert) execute() bool {
-	client := &http.Client{Timeout: a.config.ExecuteTimeout}
 	p := a.promise
+	p.watchdog.check()
+
+	client := newHTTPClient(p.attemptTimeout(), p.socketPath, p.tls, p.http2)
+	client.Jar = p.jar
+	if p.noRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	if p.exchanges != nil {
+		client.Transport = p.exchanges.transport(p.process, client.Transport)
+	}
+	client.Transport = p.metrics.transport(client.Transport)
+
+	body, length, err := p.requestBody()
+	if err != nil {
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
 
-	req, err := http.NewRequestWithContext(p.ctx, p.method, p.url, bytes.NewReader(p.body))
+	req, err := http.NewRequestWithContext(p.ctx, p.method, p.url, body)
 	if err != nil {
 		panic(fmt.Sprintf("An error occurred: %v", err))
 	}
+	if length >= 0 {
+		req.ContentLength = length
+	}
 
 	for key, value := range p.headers {
 		req.Header.Set(key, value)
 	}
+	req.Header.Set(TraceHeader, a.traceID)
+
+	p.issue()
+
+	exchange := &HTTPExchange{
+		Trace:         a.traceID,
+		Method:        p.method,
+		URL:           p.url,
+		RequestHeader: req.Header.Clone(),
+	}
+	if p.bodyReader == nil && p.bodyFile == "" {
+		exchange.RequestBody = string(p.body)
+	}
+	if p.last != nil {
+		p.last.set(exchange)
+	}
 
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
-		panic(fmt.Sprintf("An error occurred: %v", err))
+		exchange.Error = err.Error()
+		p.trace.record("trace=%s %s %s -> error: %v", a.traceID, p.method, p.url, err)
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v\n  Trace: %s", err, a.traceID))
 	}
 	defer resp.Body.Close()
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		panic(fmt.Sprintf("An error occurred: %v", err))
+		exchange.Error = err.Error()
+		p.trace.record("trace=%s %s %s -> error: %v", a.traceID, p.method, p.url, err)
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v\n  Trace: %s", err, a.traceID))
 	}
+	a.duration = time.Since(start)
+
+	p.trace.record("trace=%s %s %s -> %d", a.traceID, p.method, p.url, resp.StatusCode)
+
+	exchange.Proto = resp.Proto
+	exchange.Status = resp.StatusCode
+	exchange.ResponseHeader = resp.Header
+	exchange.ResponseBody = string(responseBody)
 
 	a.responseBody = string(responseBody)
 	a.responseStatus = resp.StatusCode
+	a.responseHeader = resp.Header
+	a.tlsState = resp.TLS
+	a.proto = resp.Proto
+
+	a.location = ""
+	if location, err := resp.Location(); err == nil {
+		a.location = location.String()
+	}
+
+	return a.passes()
+}
+
+// passes reports whether every checker passes for the response.
+func (a *HTTPAssert) passes() bool {
+	for _, c := range a.tlsChecks() {
+		if len(c.checkers) > 0 && (a.tlsState == nil || !checkAll(c.value, c.checkers, nil)) {
+			return false
+		}
+	}
+
+	for _, group := range a.alternatives {
+		if !slices.ContainsFunc(group, func(alt *HTTPAssert) bool {
+			alt.setResponse(a)
+			return alt.passes()
+		}) {
+			return false
+		}
+	}
 
 	return checkAll(a.responseStatus, a.statusCheckers, nil) &&
+		checkAll(a.proto, a.protoCheckers, nil) &&
+		checkAll(a.location, a.locationCheckers, nil) &&
+		headersPass(a.responseHeader, a.headerCheckers) &&
+		cookiesPass(a.responseCookies(), a.cookieCheckers, cookieValue) &&
+		cookiesPass(a.responseCookies(), a.setCookieCheckers, (*http.Cookie).String) &&
 		checkAll(a.responseBody, a.bodyCheckers, nil) &&
-		checkAll(a.responseBody, a.jsonCheckers, nil)
+		checkAll(a.responseBody, a.jsonCheckers, nil) &&
+		checkAll(a.responseBody, a.yamlCheckers, nil) &&
+		checkAll(a.duration, a.durationCheckers, nil)
+}
+
+// setResponse checks the response from.
+func (a *HTTPAssert) setResponse(from *HTTPAssert) {
+	a.responseBody = from.responseBody
+	a.responseStatus = from.responseStatus
+	a.responseHeader = from.responseHeader
+	a.tlsState = from.tlsState
+	a.proto = from.proto
+	a.location = from.location
+	a.duration = from.duration
+}
+
+// alternativesMismatch describes the groups of alternatives none of which
+// match the response, each with why.
+func (a *HTTPAssert) alternativesMismatch() string {
+	var mismatches []string
+	for _, group := range a.alternatives {
+		lines := []string{"Expected one of these alternatives to match:"}
+		for i, alt := range group {
+			alt.setResponse(a)
+			reasons := alt.mismatches()
+			if reasons == "" {
+				lines = nil
+				break
+			}
+
+			lines = append(lines, fmt.Sprintf("  Alternative %d:\n      %s", i+1, strings.ReplaceAll(reasons, "\n  ", "\n      ")))
+		}
+
+		if lines != nil {
+			mismatches = append(mismatches, strings.Join(lines, "\n  "))
+		}
+	}
+
+	return joinMismatches(mismatches...)
+}
+
+// locationActual describes the redirect target for failure messages.
+func (a *HTTPAssert) locationActual() string {
+	if a.location == "" {
+		return "Actual Location: (missing)"
+	}
+
+	return fmt.Sprintf("Actual Location: %s", a.location)
+}
+
+// responseCookies parses the cookies set by the response.
+func (a *HTTPAssert) responseCookies() []*http.Cookie {
+	return (&http.Response{Header: a.responseHeader}).Cookies()
+}
+
+// headerValue returns every value of the named header, joined with ", ".
+func headerValue(header http.Header, name string) string {
+	return strings.Join(header.Values(name), ", ")
+}
+
+// headersPass reports whether every header checker passes.
+func headersPass(header http.Header, checkers map[string][]Checker[string]) bool {
+	for name, headerCheckers := range checkers {
+		if !checkAll(headerValue(header, name), headerCheckers, nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// headerMismatch describes failing header checkers, in header name order.
+func headerMismatch(header http.Header, checkers map[string][]Checker[string]) string {
+	var mismatches []string
+	for _, name := range slices.Sorted(maps.Keys(checkers)) {
+		value := headerValue(header, name)
+		actual := fmt.Sprintf("Actual %s header: %q", name, value)
+		if len(header.Values(name)) == 0 {
+			actual = fmt.Sprintf("Actual %s header: (missing)", name)
+		}
+
+		mismatches = append(mismatches, mismatch(value, checkers[name], fmt.Sprintf("%s header", name), actual))
+	}
+
+	return joinMismatches(mismatches...)
 }
 
 func (a *HTTPAssert) check() {
 	p := a.promise
 
-	checkAll(a.responseStatus, a.statusCheckers, func(m Checker[int], actual int) {
-		msg := fmt.Sprintf("%s %s\n  Expected status: %s\n  Actual status: %d %s%s",
-			p.method, p.url, m.Expected(), actual,
-			http.StatusText(actual), a.formatHelp())
+	mismatches := a.mismatches()
+	if mismatches != "" {
+		msg := fmt.Sprintf("%s %s\n  Trace: %s\n  %s%s", p.method, p.url, a.traceID, mismatches, a.formatHelp())
 		panic(msg)
-	})
+	}
+}
 
-	checkAll(a.responseBody, a.bodyCheckers, func(m Checker[string], actual string) {
-		msg := fmt.Sprintf("%s %s\n  Expected response: %s\n  Actual response: %q%s",
-			p.method, p.url, m.Expected(), actual, a.formatHelp())
-		panic(msg)
-	})
+// mismatches describes every checker that fails for the response, or
+// returns "" if they all pass.
+func (a *HTTPAssert) mismatches() string {
+	var tlsMismatches []string
+	for _, c := range a.tlsChecks() {
+		if len(c.checkers) > 0 && a.tlsState == nil {
+			tlsMismatches = append(tlsMismatches, mismatch(c.value, c.checkers[:1], c.label, "Actual: plaintext connection"))
+			break
+		}
 
-	checkAll(a.responseBody, a.jsonCheckers, func(m Checker[string], actual string) {
-		msg := fmt.Sprintf("%s %s\n  Expected JSON: %s\n  Actual value: %v%s",
-			p.method, p.url, m.Expected(), actual, a.formatHelp())
-		panic(msg)
-	})
+		tlsMismatches = append(tlsMismatches, mismatch(c.value, c.checkers, c.label, fmt.Sprintf("Actual %s: %q", c.label, c.value)))
+	}
+
+	tlsMismatch := joinMismatches(tlsMismatches...)
+	if tlsMismatch != "" && a.tlsState != nil {
+		tlsMismatch += "\n  Connection: " + tlsDetails(a.tlsState)
+	}
+
+	bodyMismatch := bodyMismatch(a.responseBody, a.bodyCheckers, "response", a.config)
+
+	// The body is only shown once
+	valueActual := fmt.Sprintf("Actual value: %v", clipValue(a.responseBody, a.config.maxBodyLength(len("Actual value: ")), -1))
+	if bodyMismatch != "" {
+		valueActual = ""
+	}
+
+	jsonMismatch := jsonMismatch(a.responseBody, a.jsonCheckers, valueActual)
+	if jsonMismatch != "" {
+		valueActual = ""
+	}
+
+	return joinMismatches(
+		tlsMismatch,
+		mismatch(a.responseStatus, a.statusCheckers, "status",
+			fmt.Sprintf("Actual status: %d %s", a.responseStatus, http.StatusText(a.responseStatus))),
+		mismatch(a.proto, a.protoCheckers, "protocol", fmt.Sprintf("Actual protocol: %s", a.proto)),
+		mismatch(a.location, a.locationCheckers, "Location", a.locationActual()),
+		headerMismatch(a.responseHeader, a.headerCheckers),
+		cookieMismatch(a.responseCookies(), a.cookieCheckers, "cookie", cookieValue),
+		cookieMismatch(a.responseCookies(), a.setCookieCheckers, "Set-Cookie", (*http.Cookie).String),
+		bodyMismatch,
+		jsonMismatch,
+		yamlMismatch(a.responseBody, a.yamlCheckers, valueActual),
+		mismatch(a.duration, a.durationCheckers, "duration", fmt.Sprintf("Actual duration: %s", a.duration.Round(time.Microsecond))),
+		a.alternativesMismatch(),
+	)
 }
 
 // CLIAssert provides CLI command output and exit code assertions.
@@ -190,9 +740,20 @@ type CLIAssert struct {
 	promise  *CLIPromise
 	output   string
 	exitCode int
+	duration time.Duration
 
-	exitCheckers   []Checker[int]
-	outputCheckers []Checker[string]
+	exitCheckers     []Checker[int]
+	outputCheckers   []Checker[string]
+	yamlCheckers     []Checker[string]
+	durationCheckers []Checker[time.Duration]
+	// Checkers for the output of a command left running
+	streamCheckers []Checker[string]
+
+	// unmetSignal is the signal whose output never came, if any
+	unmetSignal *signalStep
+	// exitAfter is how long the command took to exit after its last signal
+	exitAfter         time.Duration
+	exitAfterCheckers []Checker[time.Duration]
 }
 
 // ExitCode adds expected exit code checkers.
@@ -209,35 +770,110 @@ func (a *CLIAssert) Output(checkers ...Checker[string]) *CLIAssert {
 	return a
 }
 
+// YAML adds expected checkers for a field of YAML output at the given gjson
+// path, e.g. a config dump.
+// All checkers must pass.
+func (a *CLIAssert) YAML(path string, checkers ...Checker[string]) *CLIAssert {
+	for _, checker := range checkers {
+		a.yamlCheckers = append(a.yamlCheckers, YAML(path, checker))
+	}
+
+	return a
+}
+
+// Duration adds checkers for how long the command took to run.
+// All checkers must pass.
+func (a *CLIAssert) Duration(checkers ...Checker[time.Duration]) *CLIAssert {
+	a.durationCheckers = append(a.durationCheckers, checkers...)
+	return a
+}
+
+// ExitAfter adds checkers for how long the command took to exit after the
+// last signal SignalWhen sent it, e.g. ExitAfter(LessThan(time.Second)).
+// All checkers must pass.
+func (a *CLIAssert) ExitAfter(checkers ...Checker[time.Duration]) *CLIAssert {
+	a.exitAfterCheckers = append(a.exitAfterCheckers, checkers...)
+	return a
+}
+
+// OutputEventually adds checkers for the output of a command that doesn't
+// exit, e.g. a daemon. Assert starts the command and returns once its stdout
+// and stderr so far pass all checkers, within the Eventually timeout. The
+// command is left running and killed at the end of the test. It can't be
+// combined with the other checkers, which need the command to exit.
+func (a *CLIAssert) OutputEventually(checkers ...Checker[string]) *CLIAssert {
+	a.streamCheckers = append(a.streamCheckers, checkers...)
+	return a
+}
+
 func (a *CLIAssert) Assert(help string) {
 	a.help = help
 
 	p := a.promise
-	switch p.timing {
-	case TimingEventually:
-		eventually(p.ctx, a.execute, p.timeout, a.config.RetryPollInterval)
-	case TimingConsistently:
-		consistently(p.ctx, a.execute, p.timeout, a.config.RetryPollInterval)
-	default:
-		a.execute()
+	if len(a.streamCheckers) > 0 {
+		if len(a.exitCheckers) > 0 || len(a.outputCheckers) > 0 || len(a.yamlCheckers) > 0 ||
+			len(a.durationCheckers) > 0 || len(a.exitAfterCheckers) > 0 {
+			panic("OutputEventually() can't be combined with ExitCode(), Output(), YAML(), Duration() or ExitAfter()")
+		}
+		if p.timing == TimingConsistently {
+			panic("OutputEventually() can't be combined with Consistently()")
+		}
+		if len(p.signals) > 0 {
+			panic("OutputEventually() can't be combined with SignalWhen()")
+		}
+		if len(p.pipeline) > 0 {
+			panic("OutputEventually() can't be used on a Pipe()")
+		}
+
+		a.stream()
+		return
+	}
+
+	if len(p.pipeline) > 0 && len(p.signals) > 0 {
+		panic("SignalWhen() can't be used on a Pipe()")
 	}
+	if len(a.exitAfterCheckers) > 0 && len(p.signals) == 0 {
+		panic("ExitAfter() needs a signal sent with SignalWhen()")
+	}
+
+	a.sampling = p.run(a.execute)
+
+	p.breadcrumbs.add("%s → exit %d", p.commandLine(), a.exitCode)
 
 	a.check()
 }
 
 func (a *CLIAssert) execute() bool {
 	p := a.promise
+	p.watchdog.check()
 
-	ctx, cancel := context.WithTimeout(p.ctx, a.config.ExecuteTimeout)
+	ctx, cancel := context.WithTimeout(p.ctx, p.attemptTimeout())
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, p.command, p.args...)
+	a.unmetSignal = nil
+
+	start := time.Now()
+	var stdout []byte
+	var err error
+	switch {
+	case len(p.pipeline) > 0:
+		stdout, err = p.outputPipeline(ctx)
+	case len(p.signals) > 0:
+		stdout, err = a.outputWithSignals(p.build(ctx))
+	default:
+		stdout, err = p.build(ctx).Output()
+	}
+	a.duration = time.Since(start)
+
+	if a.unmetSignal != nil {
+		a.output = string(stdout)
+		return false
+	}
 
-	stdout, err := cmd.Output()
 	if err != nil {
 		var exitError *exec.ExitError
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
-			a.output = fmt.Sprintf("%s timed out after %s", p.command, a.config.ExecuteTimeout)
+			a.output = fmt.Sprintf("%s timed out after %s", p.command, p.attemptTimeout())
 			a.exitCode = -1
 		} else if errors.Is(ctx.Err(), context.Canceled) {
 			a.output = fmt.Sprintf("%s was cancelled", p.command)
@@ -254,23 +890,2040 @@ func (a *CLIAssert) execute() bool {
 	}
 
 	return checkAll(a.exitCode, a.exitCheckers, nil) &&
-		checkAll(a.output, a.outputCheckers, nil)
+		checkAll(a.output, a.outputCheckers, nil) &&
+		checkAll(a.output, a.yamlCheckers, nil) &&
+		checkAll(a.duration, a.durationCheckers, nil) &&
+		checkAll(a.exitAfter, a.exitAfterCheckers, nil)
 }
 
-func (a *CLIAssert) check() {
-	p := a.promise
+// build creates the command to run with the promise's directory,
+// environment and stdin.
+func (p *CLIPromise) build(ctx context.Context) *exec.Cmd {
+	command := p.command
+	if p.dir != "" && strings.ContainsRune(command, filepath.Separator) {
+		// A relative command, e.g. ./run.sh, would be resolved against the directory
+		command, _ = filepath.Abs(command)
+	}
 
-	checkAll(a.exitCode, a.exitCheckers, func(m Checker[int], actual int) {
-		msg := fmt.Sprintf("%s %s\n  Expected exit code: %s\n  Actual exit code: %d%s",
-			p.command, strings.Join(p.args, " "), m.Expected(), actual,
-			a.formatHelp())
-		panic(msg)
-	})
+	cmd := exec.CommandContext(ctx, command, p.args...)
+	cmd.Dir = p.dir
+	if len(p.env) > 0 {
+		// Later values win, so these override the inherited ones
+		cmd.Env = append(os.Environ(), p.env...)
+	}
 
-	checkAll(a.output, a.outputCheckers, func(m Checker[string], actual string) {
-		msg := fmt.Sprintf("%s %s\n  Expected output: %s\n  Actual output: %q%s",
-			p.command, strings.Join(p.args, " "), m.Expected(), actual,
-			a.formatHelp())
-		panic(msg)
+	stdin, err := p.stdinFor()
+	if err != nil {
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	cmd.Stdin = stdin
+
+	return cmd
+}
+
+// stream starts the command, leaving it running until the end of the test,
+// and waits until its output so far passes the OutputEventually checkers.
+func (a *CLIAssert) stream() {
+	p := a.promise
+	p.watchdog.check()
+	p.metrics.assertions.Add(1)
+
+	timeout := a.config.DefaultRetryTimeout
+	if p.timing == TimingEventually {
+		timeout = p.timeout
+	}
+
+	s := &execStream{
+		state:   p.state,
+		changed: make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+
+	// The process group is killed, not just the command, so it's started
+	// without a context
+	cmd := p.build(context.Background())
+	cmd.Stdout, cmd.Stderr = s, s
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	start := time.Now()
+	err := cmd.Start()
+	if err != nil {
+		panic(err.Error())
+	}
+	p.metrics.processes.Add(1)
+
+	s.pid = cmd.Process.Pid
+	p.state.add(ProcessRecord{
+		Name:    filepath.Base(p.command),
+		PID:     s.pid,
+		Started: start.UTC().Truncate(time.Second),
+		RunDir:  p.runDir,
+	})
+	p.addStream(s)
+
+	go func() {
+		s.exitErr = cmd.Wait()
+		p.state.remove(s.pid)
+		close(s.exited)
+	}()
+
+	p.breadcrumbs.add("%s → running", p.commandLine())
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		output, changed := s.snapshot()
+		if checkAll(output, a.streamCheckers, nil) {
+			return
+		}
+
+		select {
+		case <-changed:
+			continue
+		case <-s.exited:
+			// Output written just before exiting may still be in flight
+			time.Sleep(a.config.RetryPollInterval)
+			output, _ = s.snapshot()
+			if checkAll(output, a.streamCheckers, nil) {
+				return
+			}
+
+			a.failStream(output, fmt.Sprintf("Actual: the command exited (%s)", s.exitStatus()))
+		case <-timer.C:
+			a.failStream(output, fmt.Sprintf("Waited: %s", timeout))
+		case <-p.ctx.Done():
+			a.failStream(output, "Cancelled while waiting for output")
+		}
+	}
+}
+
+// failStream reports output that didn't pass the OutputEventually checkers.
+func (a *CLIAssert) failStream(output, detail string) {
+	msg := bodyMismatch(output, a.streamCheckers, "output", a.config)
+	panic(fmt.Sprintf("%s\n  %s\n  %s%s", a.promise.describe(), msg, detail, a.formatHelp()))
+}
+
+// describe renders the command, or each command of a pipeline, with its
+// environment, directory and stdin.
+func (p *CLIPromise) describe() string {
+	if len(p.pipeline) > 0 {
+		stages := make([]string, len(p.pipeline))
+		for i, stage := range p.pipeline {
+			stages[i] = stage.describe()
+		}
+
+		return strings.Join(stages, "\n  | ")
+	}
+
+	command := strings.Join(append([]string{p.command}, p.args...), " ")
+	if len(p.env) > 0 {
+		command = strings.Join(p.env, " ") + " " + command
+	}
+	if p.dir != "" {
+		command += fmt.Sprintf("\n  Directory: %s", p.dir)
+	}
+	if p.stdin != nil {
+		command += fmt.Sprintf("\n  Stdin: %q", truncateInput(p.stdin))
+	}
+	if len(p.signals) > 0 {
+		var steps []string
+		for _, step := range p.signals {
+			steps = append(steps, fmt.Sprintf("%s once %s", signalName(step.signal), step.checker.Expected()))
+		}
+		command += fmt.Sprintf("\n  Signals: %s", strings.Join(steps, ", then "))
+	}
+
+	return command
+}
+
+func (a *CLIAssert) check() {
+	if step := a.unmetSignal; step != nil {
+		msg := mismatch(a.output, []Checker[string]{step.checker}, fmt.Sprintf("output before %s", signalName(step.signal)),
+			fmt.Sprintf("Actual output: %q", a.output))
+		panic(fmt.Sprintf("%s\n  %s%s", a.promise.describe(), msg, a.formatHelp()))
+	}
+
+	outputMismatch := bodyMismatch(a.output, a.outputCheckers, "output", a.config)
+
+	// The output is only shown once
+	yamlActual := fmt.Sprintf("Actual output: %q", clipValue(a.output, a.config.maxBodyLength(len("Actual output: ")), -1))
+	if outputMismatch != "" {
+		yamlActual = ""
+	}
+
+	mismatches := joinMismatches(
+		mismatch(a.exitCode, a.exitCheckers, "exit code", fmt.Sprintf("Actual exit code: %d", a.exitCode)),
+		outputMismatch,
+		yamlMismatch(a.output, a.yamlCheckers, yamlActual),
+		mismatch(a.duration, a.durationCheckers, "duration", fmt.Sprintf("Actual duration: %s", a.duration.Round(time.Microsecond))),
+		a.exitAfterMismatch(),
+	)
+
+	if mismatches != "" {
+		msg := fmt.Sprintf("%s\n  %s%s", a.promise.describe(), mismatches, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// exitAfterMismatch reports an exit that didn't pass the ExitAfter checkers.
+func (a *CLIAssert) exitAfterMismatch() string {
+	if len(a.exitAfterCheckers) == 0 {
+		return ""
+	}
+
+	last := a.promise.signals[len(a.promise.signals)-1]
+	return mismatch(a.exitAfter, a.exitAfterCheckers, fmt.Sprintf("exit after %s", signalName(last.signal)),
+		fmt.Sprintf("Actual: exited after %s", a.exitAfter.Round(time.Millisecond)))
+}
+
+// FuzzAssert provides assertions that a process survives malformed inputs.
+type FuzzAssert struct {
+	AssertBase
+
+	promise *FuzzPromise
+
+	// Outcome of the first input that failed, if any
+	failedIndex    int
+	crashed        bool
+	responseStatus int
+
+	statusCheckers []Checker[int]
+}
+
+// Status adds checkers for the status code of every response the process sends back.
+// Inputs that are answered by closing the connection are not checked.
+// All checkers must pass.
+func (a *FuzzAssert) Status(checkers ...Checker[int]) *FuzzAssert {
+	a.statusCheckers = append(a.statusCheckers, checkers...)
+	return a
+}
+
+func (a *FuzzAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	a.check()
+}
+
+func (a *FuzzAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	a.failedIndex = -1
+	a.crashed = false
+
+	for i, input := range p.inputs {
+		status, responded, err := a.send(input)
+		if err == nil {
+			// The process must still accept connections after each input
+			var conn net.Conn
+			conn, err = net.DialTimeout(p.network, p.addr, a.config.ExecuteTimeout)
+			if err == nil {
+				conn.Close()
+			}
+		}
+
+		if err != nil {
+			a.failedIndex = i
+			a.crashed = true
+			return false
+		}
+
+		if responded && !checkAll(status, a.statusCheckers, nil) {
+			a.failedIndex = i
+			a.responseStatus = status
+			return false
+		}
+	}
+
+	return true
+}
+
+// send writes a single raw input and returns the response status, if any.
+// An error is returned if the process could not be reached.
+func (a *FuzzAssert) send(input []byte) (int, bool, error) {
+	p := a.promise
+
+	p.issue()
+
+	conn, err := net.DialTimeout(p.network, p.addr, a.config.ExecuteTimeout)
+	if err != nil {
+		return 0, false, err
+	}
+	conn = p.metrics.conn(conn)
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(a.config.ExecuteTimeout))
+
+	// Writes may fail if the process rejects the input early
+	_, err = conn.Write(input)
+	if err == nil {
+		if halfCloser, ok := conn.(interface{ CloseWrite() error }); ok {
+			halfCloser.CloseWrite()
+		}
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return 0, false, nil
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, true, nil
+}
+
+func (a *FuzzAssert) check() {
+	p := a.promise
+	if a.failedIndex < 0 {
+		return
+	}
+
+	input := p.inputs[a.failedIndex]
+	if a.crashed {
+		msg := fmt.Sprintf("FUZZ %s\n  Input #%d: %q\n  Expected: server keeps accepting connections\n  Actual: server stopped accepting connections%s",
+			p.addr, a.failedIndex+1, truncateInput(input), a.formatHelp())
+		panic(msg)
+	}
+
+	checkAll(a.responseStatus, a.statusCheckers, func(m Checker[int], actual int) {
+		msg := fmt.Sprintf("FUZZ %s\n  Input #%d: %q\n  Expected status: %s\n  Actual status: %d %s%s",
+			p.addr, a.failedIndex+1, truncateInput(input), m.Expected(), actual,
+			http.StatusText(actual), a.formatHelp())
+		panic(msg)
+	})
+}
+
+// truncateInput shortens long raw inputs for error messages.
+func truncateInput(input []byte) string {
+	const maxLen = 120
+	if len(input) <= maxLen {
+		return string(input)
+	}
+
+	return fmt.Sprintf("%s... (%d more bytes)", input[:maxLen], len(input)-maxLen)
+}
+
+// LogsAssert provides assertions on a process's log output.
+type LogsAssert struct {
+	AssertBase
+
+	promise *LogsPromise
+	output  string
+
+	// First log line matched by a Never checker, if any
+	lineNumber int
+	line       string
+	lineMatch  Checker[string]
+
+	outputCheckers []Checker[string]
+	neverCheckers  []Checker[string]
+}
+
+// Output adds checkers for the log output produced during the current test.
+// All checkers must pass.
+func (a *LogsAssert) Output(checkers ...Checker[string]) *LogsAssert {
+	a.outputCheckers = append(a.outputCheckers, checkers...)
+	return a
+}
+
+// Never adds checkers that no single log line may satisfy,
+// e.g. Never(Contains("panic")).
+func (a *LogsAssert) Never(checkers ...Checker[string]) *LogsAssert {
+	a.neverCheckers = append(a.neverCheckers, checkers...)
+	return a
+}
+
+func (a *LogsAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	a.check()
+}
+
+// AssertAtEnd defers the assertion until the current test finishes,
+// covering all log output produced during the test.
+func (a *LogsAssert) AssertAtEnd(help string) {
+	a.promise.atEnd(func() {
+		a.Assert(help)
+	})
+}
+
+func (a *LogsAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	// A missing log means the process hasn't written anything yet
+	data, _ := os.ReadFile(p.path)
+
+	offset := min(p.offset, int64(len(data)))
+	a.output = string(data[offset:])
+	a.lineMatch = nil
+
+	if !checkAll(a.output, a.outputCheckers, nil) {
+		return false
+	}
+
+	lineOffset := bytes.Count(data[:offset], []byte("\n"))
+	for i, line := range strings.Split(a.output, "\n") {
+		for _, checker := range a.neverCheckers {
+			if checker.Check(line) {
+				a.lineNumber = lineOffset + i + 1
+				a.line = line
+				a.lineMatch = checker
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (a *LogsAssert) check() {
+	p := a.promise
+
+	if outputMismatch := bodyMismatch(a.output, a.outputCheckers, "output", a.config); outputMismatch != "" {
+		msg := fmt.Sprintf("LOGS %s\n  %s%s", p.name, outputMismatch, a.formatHelp())
+		panic(msg)
+	}
+
+	if a.lineMatch != nil {
+		msg := fmt.Sprintf("LOGS %s\n  Expected: no line %s\n  Actual line %d: %q%s",
+			p.name, a.lineMatch.Expected(), a.lineNumber, a.line, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// responseIdleTimeout is how long a response may stay silent before it's considered complete.
+const responseIdleTimeout = 200 * time.Millisecond
+
+// TCPAssert provides assertions on raw TCP responses.
+type TCPAssert struct {
+	AssertBase
+
+	promise  *TCPPromise
+	response string
+
+	responseCheckers []Checker[string]
+}
+
+// Response adds expected checkers for the raw response bytes.
+// All checkers must pass.
+func (a *TCPAssert) Response(checkers ...Checker[string]) *TCPAssert {
+	a.responseCheckers = append(a.responseCheckers, checkers...)
+	return a
+}
+
+func (a *TCPAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	a.check()
+}
+
+func (a *TCPAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	p.issue()
+
+	conn, err := net.DialTimeout(p.network, p.addr, a.config.ExecuteTimeout)
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	conn = p.metrics.conn(conn)
+	defer conn.Close()
+
+	deadline := time.Now().Add(a.config.ExecuteTimeout)
+	conn.SetWriteDeadline(deadline)
+
+	_, err = conn.Write(p.data)
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+
+	a.response = string(readResponse(conn, deadline, p.until))
+
+	return checkAll(a.response, a.responseCheckers, nil)
+}
+
+// readResponse reads from conn until delim is seen (if set), the connection
+// is closed, it goes idle, or the deadline passes.
+func readResponse(conn net.Conn, deadline time.Time, delim []byte) []byte {
+	var response []byte
+
+	buf := make([]byte, 4096)
+	for {
+		readDeadline := deadline
+		if len(response) > 0 && len(delim) == 0 {
+			readDeadline = time.Now().Add(responseIdleTimeout)
+			if readDeadline.After(deadline) {
+				readDeadline = deadline
+			}
+		}
+		conn.SetReadDeadline(readDeadline)
+
+		n, err := conn.Read(buf)
+		response = append(response, buf[:n]...)
+
+		if len(delim) > 0 && bytes.Contains(response, delim) {
+			return response
+		}
+
+		if err != nil {
+			return response
+		}
+	}
+}
+
+func (a *TCPAssert) check() {
+	p := a.promise
+
+	checkAll(a.response, a.responseCheckers, func(m Checker[string], actual string) {
+		msg := fmt.Sprintf("TCP %s\n  Sent: %q\n  Expected response: %s\n  Actual response: %q%s",
+			p.addr, truncateInput(p.data), m.Expected(), actual, a.formatHelp())
+		panic(msg)
+	})
+}
+
+// HTTPRawAssert provides assertions on the response to a raw HTTP request.
+type HTTPRawAssert struct {
+	AssertBase
+
+	promise  *HTTPRawPromise
+	response string
+
+	// Parsed response, nil if the response isn't valid HTTP
+	parsed     *http.Response
+	parseErr   error
+	parsedBody string
+
+	statusCheckers   []Checker[int]
+	headerCheckers   map[string][]Checker[string]
+	bodyCheckers     []Checker[string]
+	responseCheckers []Checker[string]
+}
+
+// Status adds expected status code checkers.
+// All checkers must pass.
+func (a *HTTPRawAssert) Status(checkers ...Checker[int]) *HTTPRawAssert {
+	a.statusCheckers = append(a.statusCheckers, checkers...)
+	return a
+}
+
+// Header adds expected checkers for a response header's value. Repeated
+// headers are joined with ", ". All checkers must pass.
+func (a *HTTPRawAssert) Header(name string, checkers ...Checker[string]) *HTTPRawAssert {
+	name = http.CanonicalHeaderKey(name)
+	a.headerCheckers[name] = append(a.headerCheckers[name], checkers...)
+	return a
+}
+
+// Body adds expected checkers for the response body.
+// All checkers must pass.
+func (a *HTTPRawAssert) Body(checkers ...Checker[string]) *HTTPRawAssert {
+	a.bodyCheckers = append(a.bodyCheckers, checkers...)
+	return a
+}
+
+// Response adds expected checkers for the raw response bytes,
+// status line and headers included. All checkers must pass.
+func (a *HTTPRawAssert) Response(checkers ...Checker[string]) *HTTPRawAssert {
+	a.responseCheckers = append(a.responseCheckers, checkers...)
+	return a
+}
+
+func (a *HTTPRawAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	requestLine, _, _ := strings.Cut(string(p.request), "\r\n")
+	status := "invalid response"
+	if a.parsed != nil {
+		status = strconv.Itoa(a.parsed.StatusCode)
+	}
+	p.breadcrumbs.add("RAW %q → %s", truncateValue(requestLine, 40), status)
+
+	a.check()
+}
+
+func (a *HTTPRawAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	p.issue()
+
+	conn, err := net.DialTimeout(p.network, p.addr, a.config.ExecuteTimeout)
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	conn = p.metrics.conn(conn)
+	defer conn.Close()
+
+	deadline := time.Now().Add(a.config.ExecuteTimeout)
+	conn.SetWriteDeadline(deadline)
+
+	// The server may reject the request and hang up before reading all of it
+	_, err = conn.Write(p.request)
+	if err != nil && !errors.Is(err, syscall.EPIPE) && !errors.Is(err, syscall.ECONNRESET) {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+
+	a.response = string(readResponse(conn, deadline, nil))
+	a.parse()
+
+	return a.passes()
+}
+
+// parse decodes the raw response, remembering why if it isn't valid HTTP.
+func (a *HTTPRawAssert) parse() {
+	a.parsed, a.parseErr, a.parsedBody = nil, nil, ""
+
+	if a.response == "" {
+		a.parseErr = fmt.Errorf("no response")
+		return
+	}
+
+	// Responses to HEAD carry a Content-Length but no body
+	method, _, _ := bytes.Cut(a.promise.request, []byte(" "))
+	req := &http.Request{Method: string(method)}
+
+	resp, err := http.ReadResponse(bufio.NewReader(strings.NewReader(a.response)), req)
+	if err != nil {
+		a.parseErr = err
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		a.parseErr = err
+		return
+	}
+
+	a.parsed, a.parsedBody = resp, string(body)
+}
+
+func (a *HTTPRawAssert) passes() bool {
+	if !checkAll(a.response, a.responseCheckers, nil) {
+		return false
+	}
+
+	if a.parsed == nil {
+		return len(a.statusCheckers) == 0 && len(a.headerCheckers) == 0 && len(a.bodyCheckers) == 0
+	}
+
+	return checkAll(a.parsed.StatusCode, a.statusCheckers, nil) &&
+		headersPass(a.parsed.Header, a.headerCheckers) &&
+		checkAll(a.parsedBody, a.bodyCheckers, nil)
+}
+
+func (a *HTTPRawAssert) check() {
+	p := a.promise
+
+	responseMismatch := mismatch(a.response, a.responseCheckers, "response", fmt.Sprintf("Actual response: %q", a.response))
+
+	var mismatches string
+	if a.parsed == nil {
+		needsParsed := len(a.statusCheckers) > 0 || len(a.headerCheckers) > 0 || len(a.bodyCheckers) > 0
+		parseMismatch := ""
+		if needsParsed {
+			parseMismatch = fmt.Sprintf("Expected a valid HTTP response\n  Actual response: %q (%v)", a.response, a.parseErr)
+		}
+
+		mismatches = joinMismatches(responseMismatch, parseMismatch)
+	} else {
+		mismatches = joinMismatches(
+			mismatch(a.parsed.StatusCode, a.statusCheckers, "status",
+				fmt.Sprintf("Actual status: %d %s", a.parsed.StatusCode, http.StatusText(a.parsed.StatusCode))),
+			headerMismatch(a.parsed.Header, a.headerCheckers),
+			bodyMismatch(a.parsedBody, a.bodyCheckers, "body", a.config),
+			responseMismatch,
+		)
+	}
+
+	if mismatches != "" {
+		msg := fmt.Sprintf("HTTP %s\n  Sent: %q\n  %s%s", p.addr, truncateInput(p.request), mismatches, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// HTTPConnAssert provides assertions on the responses received over a single
+// connection and on whether the server closed it afterwards.
+type HTTPConnAssert struct {
+	AssertBase
+
+	promise   *HTTPConnPromise
+	responses []connResponse
+	closed    bool
+
+	// Why fewer responses than requests were received, if they were
+	stopped string
+
+	// Unmet expectations, if any
+	failure string
+
+	countCheckers  []Checker[int]
+	statusCheckers map[int][]Checker[int]
+	headerCheckers map[int]map[string][]Checker[string]
+	bodyCheckers   map[int][]Checker[string]
+	closedCheckers []Checker[bool]
+}
+
+// connResponse is a response parsed off a shared connection.
+type connResponse struct {
+	status int
+	header http.Header
+	body   string
+}
+
+// Responses adds checkers for the number of complete responses received.
+// All checkers must pass.
+func (a *HTTPConnAssert) Responses(checkers ...Checker[int]) *HTTPConnAssert {
+	a.countCheckers = append(a.countCheckers, checkers...)
+	return a
+}
+
+// Status adds expected status code checkers for the response at index i,
+// which must be received. All checkers must pass.
+func (a *HTTPConnAssert) Status(i int, checkers ...Checker[int]) *HTTPConnAssert {
+	a.statusCheckers[i] = append(a.statusCheckers[i], checkers...)
+	return a
+}
+
+// Header adds expected checkers for a header's value in the response at
+// index i, which must be received. Repeated headers are joined with ", ".
+// All checkers must pass.
+func (a *HTTPConnAssert) Header(i int, name string, checkers ...Checker[string]) *HTTPConnAssert {
+	if a.headerCheckers[i] == nil {
+		a.headerCheckers[i] = make(map[string][]Checker[string])
+	}
+
+	name = http.CanonicalHeaderKey(name)
+	a.headerCheckers[i][name] = append(a.headerCheckers[i][name], checkers...)
+	return a
+}
+
+// Body adds expected checkers for the body of the response at index i,
+// which must be received. All checkers must pass.
+func (a *HTTPConnAssert) Body(i int, checkers ...Checker[string]) *HTTPConnAssert {
+	a.bodyCheckers[i] = append(a.bodyCheckers[i], checkers...)
+	return a
+}
+
+// Closed adds checkers for whether the server closed the connection, either
+// after its last response or before answering every request.
+// All checkers must pass.
+func (a *HTTPConnAssert) Closed(checkers ...Checker[bool]) *HTTPConnAssert {
+	a.closedCheckers = append(a.closedCheckers, checkers...)
+	return a
+}
+
+func (a *HTTPConnAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	state := "open"
+	if a.closed {
+		state = "closed"
+	}
+	p.breadcrumbs.add("CONN %d requests → %d responses, %s", len(p.requests), len(a.responses), state)
+
+	a.check()
+}
+
+func (a *HTTPConnAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	p.issue()
+
+	conn, err := net.DialTimeout(p.network, p.addr, a.config.ExecuteTimeout)
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	conn = p.metrics.conn(conn)
+	defer conn.Close()
+
+	a.responses, a.closed, a.stopped = nil, false, ""
+
+	deadline := time.Now().Add(a.config.ExecuteTimeout)
+	conn.SetDeadline(deadline)
+	reader := bufio.NewReader(conn)
+
+	if p.pipelined {
+		if a.write(conn, bytes.Join(p.requests, nil)) {
+			for i := range p.requests {
+				if !a.read(reader, i) {
+					break
+				}
+			}
+		}
+	} else {
+		for i, request := range p.requests {
+			if !a.write(conn, request) || !a.read(reader, i) {
+				break
+			}
+		}
+	}
+
+	// Every request was answered, so anything but a prompt hang up means
+	// the server is keeping the connection alive
+	if a.stopped == "" {
+		conn.SetReadDeadline(time.Now().Add(responseIdleTimeout))
+		_, err := reader.ReadByte()
+		a.closed = connClosed(err)
+	}
+
+	a.failure = a.evaluate()
+
+	return a.failure == ""
+}
+
+// write sends data, reporting false if the server has already hung up.
+func (a *HTTPConnAssert) write(conn net.Conn, data []byte) bool {
+	p := a.promise
+
+	_, err := conn.Write(data)
+	if err == nil {
+		return true
+	}
+
+	if !errors.Is(err, syscall.EPIPE) && !errors.Is(err, syscall.ECONNRESET) {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+
+	a.closed = true
+	a.stopped = fmt.Sprintf("the connection was closed after %d response(s)", len(a.responses))
+	return false
+}
+
+// read parses the response to the request at index i, reporting false if
+// there isn't a complete one.
+func (a *HTTPConnAssert) read(reader *bufio.Reader, i int) bool {
+	// Responses to HEAD carry a Content-Length but no body
+	method, _, _ := bytes.Cut(a.promise.requests[i], []byte(" "))
+	req := &http.Request{Method: string(method)}
+
+	resp, err := http.ReadResponse(reader, req)
+	if err == nil {
+		var body []byte
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		// net/http strips Connection: close from HTTP/1.1 responses once it's noted
+		if resp.Close && resp.ProtoAtLeast(1, 1) && resp.Header.Get("Connection") == "" {
+			resp.Header.Set("Connection", "close")
+		}
+
+		if err == nil {
+			a.responses = append(a.responses, connResponse{resp.StatusCode, resp.Header, string(body)})
+			return true
+		}
+	}
+
+	var netErr net.Error
+	switch {
+	case connClosed(err):
+		a.closed = true
+		a.stopped = fmt.Sprintf("the connection was closed after %d response(s)", len(a.responses))
+	case errors.As(err, &netErr) && netErr.Timeout():
+		a.stopped = fmt.Sprintf("no response #%d within %s", i+1, a.config.ExecuteTimeout)
+	default:
+		a.stopped = fmt.Sprintf("response #%d isn't valid HTTP (%v)", i+1, err)
+	}
+
+	return false
+}
+
+// connClosed reports whether a read error means the peer hung up.
+func connClosed(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// evaluate describes every unmet expectation, or returns "" if all are met.
+func (a *HTTPConnAssert) evaluate() string {
+	var responseMismatches []string
+	indices := slices.Collect(maps.Keys(a.statusCheckers))
+	indices = append(indices, slices.Collect(maps.Keys(a.headerCheckers))...)
+	indices = append(indices, slices.Collect(maps.Keys(a.bodyCheckers))...)
+	slices.Sort(indices)
+	for _, i := range slices.Compact(indices) {
+		if i >= len(a.responses) {
+			responseMismatches = append(responseMismatches,
+				fmt.Sprintf("Expected response #%d\n  Actual: %d response(s)", i+1, len(a.responses)))
+			continue
+		}
+
+		resp := a.responses[i]
+		responseMismatches = append(responseMismatches,
+			mismatch(resp.status, a.statusCheckers[i], fmt.Sprintf("response #%d status", i+1),
+				fmt.Sprintf("Actual response #%d status: %d %s", i+1, resp.status, http.StatusText(resp.status))))
+
+		for _, name := range slices.Sorted(maps.Keys(a.headerCheckers[i])) {
+			value := headerValue(resp.header, name)
+			actual := fmt.Sprintf("Actual response #%d %s header: %q", i+1, name, value)
+			if len(resp.header.Values(name)) == 0 {
+				actual = fmt.Sprintf("Actual response #%d %s header: (missing)", i+1, name)
+			}
+
+			responseMismatches = append(responseMismatches,
+				mismatch(value, a.headerCheckers[i][name], fmt.Sprintf("response #%d %s header", i+1, name), actual))
+		}
+
+		responseMismatches = append(responseMismatches,
+			mismatch(resp.body, a.bodyCheckers[i], fmt.Sprintf("response #%d body", i+1),
+				fmt.Sprintf("Actual response #%d body: %q", i+1, resp.body)))
+	}
+
+	return joinMismatches(
+		mismatch(len(a.responses), a.countCheckers, "responses", fmt.Sprintf("Actual responses: %d", len(a.responses))),
+		joinMismatches(responseMismatches...),
+		mismatch(a.closed, a.closedCheckers, "connection closed", fmt.Sprintf("Actual connection closed: %t", a.closed)),
+	)
+}
+
+func (a *HTTPConnAssert) check() {
+	p := a.promise
+
+	if a.failure != "" {
+		mode := "one after another"
+		if p.pipelined {
+			mode = "pipelined"
+		}
+
+		msg := fmt.Sprintf("HTTP %s (%d requests over one connection, %s)\n  %s", p.addr, len(p.requests), mode, a.failure)
+		if a.stopped != "" {
+			msg += "\n  Note: " + a.stopped
+		}
+
+		panic(msg + a.formatHelp())
+	}
+}
+
+// UDPAssert provides assertions on the datagrams received in reply.
+type UDPAssert struct {
+	AssertBase
+
+	promise   *UDPPromise
+	datagrams []string
+
+	// Unmet expectations, if any
+	failure string
+
+	noReply          bool
+	countCheckers    []Checker[int]
+	datagramCheckers map[int][]Checker[string]
+}
+
+// NoReply expects the process not to reply at all within the reply timeout.
+func (a *UDPAssert) NoReply() *UDPAssert {
+	a.noReply = true
+	return a
+}
+
+// Count adds checkers for the number of reply datagrams.
+// All checkers must pass.
+func (a *UDPAssert) Count(checkers ...Checker[int]) *UDPAssert {
+	a.countCheckers = append(a.countCheckers, checkers...)
+	return a
+}
+
+// Response adds checkers for the first reply datagram, which must arrive.
+// All checkers must pass.
+func (a *UDPAssert) Response(checkers ...Checker[string]) *UDPAssert {
+	return a.Datagram(0, checkers...)
+}
+
+// Datagram adds checkers for the reply datagram at index i, which must arrive.
+// All checkers must pass.
+func (a *UDPAssert) Datagram(i int, checkers ...Checker[string]) *UDPAssert {
+	if a.datagramCheckers == nil {
+		a.datagramCheckers = make(map[int][]Checker[string])
+	}
+
+	a.datagramCheckers[i] = append(a.datagramCheckers[i], checkers...)
+	return a
+}
+
+func (a *UDPAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	a.check()
+}
+
+func (a *UDPAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	p.issue()
+
+	conn, err := net.Dial("udp", p.addr)
+	if err != nil {
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	conn = p.metrics.conn(conn)
+	defer conn.Close()
+
+	_, err = conn.Write(p.payload)
+	if err != nil {
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+
+	// Wait for the first datagram, then collect any that follow in quick succession
+	a.datagrams = nil
+	buf := make([]byte, 65535)
+	deadline := time.Now().Add(p.replyTimeout)
+	for {
+		conn.SetReadDeadline(deadline)
+
+		n, err := conn.Read(buf)
+		if err != nil {
+			// Timeouts and ICMP port unreachable both mean no (more) replies
+			break
+		}
+
+		a.datagrams = append(a.datagrams, string(buf[:n]))
+		deadline = time.Now().Add(responseIdleTimeout)
+	}
+
+	a.failure = a.evaluate()
+	return a.failure == ""
+}
+
+// evaluate describes every unmet expectation, or returns "" if all are met.
+func (a *UDPAssert) evaluate() string {
+	p := a.promise
+
+	var noReplyMismatch string
+	if a.noReply && len(a.datagrams) > 0 {
+		noReplyMismatch = fmt.Sprintf("Expected: no reply\n  Actual: %d datagram(s), first: %q", len(a.datagrams), a.datagrams[0])
+	}
+
+	var datagramMismatches []string
+	for _, i := range slices.Sorted(maps.Keys(a.datagramCheckers)) {
+		checkers := a.datagramCheckers[i]
+
+		if i >= len(a.datagrams) {
+			datagramMismatches = append(datagramMismatches, fmt.Sprintf("Expected datagram #%d: %s\n  Actual: no datagram within %s",
+				i+1, checkers[0].Expected(), p.replyTimeout))
+			continue
+		}
+
+		datagramMismatches = append(datagramMismatches,
+			mismatch(a.datagrams[i], checkers, fmt.Sprintf("datagram #%d", i+1),
+				fmt.Sprintf("Actual datagram #%d: %q", i+1, a.datagrams[i])))
+	}
+
+	return joinMismatches(
+		noReplyMismatch,
+		mismatch(len(a.datagrams), a.countCheckers, "datagrams", fmt.Sprintf("Actual datagrams: %d", len(a.datagrams))),
+		joinMismatches(datagramMismatches...),
+	)
+}
+
+func (a *UDPAssert) check() {
+	p := a.promise
+
+	if a.failure != "" {
+		msg := fmt.Sprintf("UDP %s\n  Sent: %q\n  %s%s",
+			p.addr, truncateInput(p.payload), a.failure, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// DNSAssert provides assertions on a DNS response.
+type DNSAssert struct {
+	AssertBase
+
+	promise *DNSPromise
+	reply   *dnsMessage
+
+	// Unmet expectations, if any
+	failure string
+
+	rcodeCheckers  []Checker[DNSRCode]
+	countCheckers  []Checker[int]
+	answerCheckers map[int][]Checker[string]
+	ttlCheckers    map[int][]Checker[int]
+}
+
+// RCode adds expected response code checkers.
+// All checkers must pass.
+func (a *DNSAssert) RCode(checkers ...Checker[DNSRCode]) *DNSAssert {
+	a.rcodeCheckers = append(a.rcodeCheckers, checkers...)
+	return a
+}
+
+// AnswerCount adds checkers for the number of records in the answer section.
+// All checkers must pass.
+func (a *DNSAssert) AnswerCount(checkers ...Checker[int]) *DNSAssert {
+	a.countCheckers = append(a.countCheckers, checkers...)
+	return a
+}
+
+// Answer adds checkers for the value of the answer at index i, which must exist.
+// Values are rendered as in a zone file, e.g. "1.2.3.4" or "10 mail.example.com.".
+// All checkers must pass.
+func (a *DNSAssert) Answer(i int, checkers ...Checker[string]) *DNSAssert {
+	a.answerCheckers[i] = append(a.answerCheckers[i], checkers...)
+	return a
+}
+
+// TTL adds checkers for the TTL in seconds of the answer at index i, which must exist.
+// All checkers must pass.
+func (a *DNSAssert) TTL(i int, checkers ...Checker[int]) *DNSAssert {
+	a.ttlCheckers[i] = append(a.ttlCheckers[i], checkers...)
+	return a
+}
+
+func (a *DNSAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	if a.reply != nil {
+		p.breadcrumbs.add("DNS %s %s → %s (%d answers)", p.qtype, p.qname, a.reply.rcode, len(a.reply.answers))
+	}
+
+	a.check()
+}
+
+func (a *DNSAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	p.issue()
+
+	conn, err := net.Dial("udp", p.addr)
+	if err != nil {
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	conn = p.metrics.conn(conn)
+	defer conn.Close()
+
+	// A new ID per attempt, so a late response to an earlier attempt isn't
+	// taken for this one
+	query := slices.Clone(p.query)
+	binary.BigEndian.PutUint16(query, uint16(rand.Uint32()))
+
+	_, err = conn.Write(query)
+	if err != nil {
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+
+	a.reply = nil
+	a.failure = ""
+
+	// Skip stray datagrams, e.g. late responses to an earlier query
+	buf := make([]byte, 65535)
+	conn.SetReadDeadline(time.Now().Add(p.replyTimeout))
+	for a.reply == nil && a.failure == "" {
+		n, err := conn.Read(buf)
+		if err != nil {
+			// Timeouts and ICMP port unreachable both mean no response
+			a.failure = fmt.Sprintf("Expected: a response\n  Actual: no response within %s", p.replyTimeout)
+			break
+		}
+
+		if n < 2 || buf[0] != query[0] || buf[1] != query[1] {
+			continue
+		}
+
+		reply, err := parseDNSMessage(buf[:n])
+		if err != nil {
+			a.failure = fmt.Sprintf("Expected: a valid DNS response\n  Actual: %v\n  Received: %q", err, truncateInput(buf[:n]))
+			break
+		}
+
+		if !reply.response {
+			a.failure = "Expected: a response\n  Actual: a message without the QR bit set"
+			break
+		}
+
+		a.reply = &reply
+	}
+
+	if a.reply != nil {
+		a.failure = a.evaluate()
+	}
+
+	return a.failure == ""
+}
+
+// evaluate describes every unmet expectation, or returns "" if all are met.
+func (a *DNSAssert) evaluate() string {
+	reply := a.reply
+
+	var answerMismatches []string
+	indices := append(slices.Collect(maps.Keys(a.answerCheckers)), slices.Collect(maps.Keys(a.ttlCheckers))...)
+	slices.Sort(indices)
+	for _, i := range slices.Compact(indices) {
+		if i >= len(reply.answers) {
+			answerMismatches = append(answerMismatches,
+				fmt.Sprintf("Expected answer #%d\n  Actual: %d answer(s)", i+1, len(reply.answers)))
+			continue
+		}
+
+		answer := reply.answers[i]
+		answerMismatches = append(answerMismatches,
+			mismatch(answer.value, a.answerCheckers[i], fmt.Sprintf("answer #%d", i+1),
+				fmt.Sprintf("Actual answer #%d: %s", i+1, answer.value)),
+			mismatch(int(answer.ttl), a.ttlCheckers[i], fmt.Sprintf("answer #%d TTL", i+1),
+				fmt.Sprintf("Actual answer #%d TTL: %d", i+1, answer.ttl)),
+		)
+	}
+
+	return joinMismatches(
+		mismatch(reply.rcode, a.rcodeCheckers, "rcode", fmt.Sprintf("Actual rcode: %s", reply.rcode)),
+		mismatch(len(reply.answers), a.countCheckers, "answers", fmt.Sprintf("Actual answers: %d", len(reply.answers))),
+		joinMismatches(answerMismatches...),
+	)
+}
+
+func (a *DNSAssert) check() {
+	p := a.promise
+
+	if a.failure != "" {
+		msg := fmt.Sprintf("DNS %s %s @ %s\n  %s", p.qtype, p.qname, p.addr, a.failure)
+		if a.reply != nil {
+			msg += "\n  Answers:" + formatDNSAnswers(a.reply.answers)
+		}
+
+		panic(msg + a.formatHelp())
+	}
+}
+
+// formatDNSAnswers renders answers one per line, as in a zone file.
+func formatDNSAnswers(answers []dnsRecord) string {
+	if len(answers) == 0 {
+		return " (none)"
+	}
+
+	var lines []string
+	for _, answer := range answers {
+		rtype := fmt.Sprintf("TYPE%d", answer.rtype)
+		for name, code := range dnsTypes {
+			if code == answer.rtype {
+				rtype = name
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("\n    %s %d IN %s %s", answer.name, answer.ttl, rtype, answer.value))
+	}
+
+	return strings.Join(lines, "")
+}
+
+// WSAssert provides assertions on the messages received over a websocket.
+type WSAssert struct {
+	AssertBase
+
+	promise  *WSPromise
+	received []string
+
+	// Unmet expectations, if any
+	failure string
+
+	countCheckers   []Checker[int]
+	messageCheckers map[int][]Checker[string]
+}
+
+// Count adds checkers for the number of messages received.
+// Messages are collected until the server goes quiet.
+// All checkers must pass.
+func (a *WSAssert) Count(checkers ...Checker[int]) *WSAssert {
+	a.countCheckers = append(a.countCheckers, checkers...)
+	return a
+}
+
+// Response adds checkers for the first message received, which must arrive.
+// All checkers must pass.
+func (a *WSAssert) Response(checkers ...Checker[string]) *WSAssert {
+	return a.Message(0, checkers...)
+}
+
+// Message adds checkers for the message at index i, which must arrive.
+// All checkers must pass.
+func (a *WSAssert) Message(i int, checkers ...Checker[string]) *WSAssert {
+	if a.messageCheckers == nil {
+		a.messageCheckers = make(map[int][]Checker[string])
+	}
+
+	a.messageCheckers[i] = append(a.messageCheckers[i], checkers...)
+	return a
+}
+
+func (a *WSAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	a.check()
+}
+
+func (a *WSAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	p.issue()
+
+	conn, err := dialWS(p.network, p.addr, p.host, p.path, a.config.ExecuteTimeout, p.metrics.conn)
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	defer conn.close()
+
+	conn.conn.SetWriteDeadline(time.Now().Add(a.config.ExecuteTimeout))
+	for _, message := range p.messages {
+		if err := conn.writeFrame(wsText, []byte(message)); err != nil {
+			p.watchdog.checkAfterError(p.ctx)
+			panic(fmt.Sprintf("An error occurred: %v", err))
+		}
+	}
+
+	// Read until every checked message has arrived; when counting, keep
+	// reading until the server goes quiet
+	needed := 0
+	for i := range a.messageCheckers {
+		needed = max(needed, i+1)
+	}
+
+	a.received = nil
+	deadline := time.Now().Add(p.replyTimeout)
+	for len(a.received) < needed || len(a.countCheckers) > 0 {
+		readDeadline := deadline
+		if len(a.received) >= needed {
+			readDeadline = time.Now().Add(responseIdleTimeout)
+			if readDeadline.After(deadline) {
+				readDeadline = deadline
+			}
+		}
+
+		message, err := conn.readMessage(readDeadline)
+		if errors.Is(err, errWSMessageTooLarge) {
+			a.failure = fmt.Sprintf("Expected message #%d: at most %s\n  Actual: a larger message",
+				len(a.received)+1, formatBytes(maxWSMessageSize))
+			return false
+		}
+		if err != nil {
+			break
+		}
+
+		a.received = append(a.received, message)
+	}
+
+	a.failure = a.evaluate()
+	return a.failure == ""
+}
+
+// evaluate describes every unmet expectation, or returns "" if all are met.
+func (a *WSAssert) evaluate() string {
+	p := a.promise
+
+	var messageMismatches []string
+	for _, i := range slices.Sorted(maps.Keys(a.messageCheckers)) {
+		checkers := a.messageCheckers[i]
+
+		if i >= len(a.received) {
+			messageMismatches = append(messageMismatches, fmt.Sprintf("Expected message #%d: %s\n  Actual: no message within %s",
+				i+1, checkers[0].Expected(), p.replyTimeout))
+			continue
+		}
+
+		messageMismatches = append(messageMismatches,
+			mismatch(a.received[i], checkers, fmt.Sprintf("message #%d", i+1),
+				fmt.Sprintf("Actual message #%d: %q", i+1, a.received[i])))
+	}
+
+	return joinMismatches(
+		mismatch(len(a.received), a.countCheckers, "messages", fmt.Sprintf("Actual messages: %d", len(a.received))),
+		joinMismatches(messageMismatches...),
+	)
+}
+
+func (a *WSAssert) check() {
+	p := a.promise
+
+	if a.failure != "" {
+		msg := fmt.Sprintf("WS %s\n  Sent: %q\n  %s%s", p.path, p.messages, a.failure, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// GRPCAssert provides gRPC status and response assertions.
+// The response message is checked in its JSON form.
+type GRPCAssert struct {
+	AssertBase
+
+	promise       *GRPCPromise
+	response      string
+	status        GRPCCode
+	statusMessage string
+
+	statusCheckers   []Checker[GRPCCode]
+	responseCheckers []Checker[string]
+	jsonCheckers     []Checker[string]
+}
+
+// Status adds expected gRPC status code checkers.
+// All checkers must pass.
+func (a *GRPCAssert) Status(checkers ...Checker[GRPCCode]) *GRPCAssert {
+	a.statusCheckers = append(a.statusCheckers, checkers...)
+	return a
+}
+
+// Response adds expected checkers for the response message as JSON.
+// All checkers must pass.
+func (a *GRPCAssert) Response(checkers ...Checker[string]) *GRPCAssert {
+	a.responseCheckers = append(a.responseCheckers, checkers...)
+	return a
+}
+
+// JSON adds expected checkers for a response field at the given gjson path.
+// All checkers must pass.
+func (a *GRPCAssert) JSON(path string, checkers ...Checker[string]) *GRPCAssert {
+	for _, checker := range checkers {
+		a.jsonCheckers = append(a.jsonCheckers, JSON(path, checker))
+	}
+
+	return a
+}
+
+func (a *GRPCAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	p.breadcrumbs.add("GRPC %s → %s", p.method.path(), a.status)
+
+	a.check()
+}
+
+func (a *GRPCAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	client := &http.Client{Timeout: a.config.ExecuteTimeout, Transport: p.metrics.transport(p.transport)}
+
+	p.issue()
+
+	message, status, statusMessage, err := grpcCall(p.ctx, client, p.url, p.message)
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+
+	a.status = status
+	a.statusMessage = statusMessage
+	a.response = ""
+	if status == GRPCOK {
+		a.response, err = p.method.Response.decodeJSON(message)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid gRPC response: %v", err))
+		}
+	}
+
+	return checkAll(a.status, a.statusCheckers, nil) &&
+		checkAll(a.response, a.responseCheckers, nil) &&
+		checkAll(a.response, a.jsonCheckers, nil)
+}
+
+func (a *GRPCAssert) check() {
+	p := a.promise
+
+	responseMismatch := mismatch(a.response, a.responseCheckers, "response",
+		fmt.Sprintf("Actual response: %s (status %s)", a.response, a.status))
+
+	// The response is only shown once
+	jsonActual := fmt.Sprintf("Actual response: %s (status %s)", a.response, a.status)
+	if responseMismatch != "" {
+		jsonActual = ""
+	}
+
+	mismatches := joinMismatches(
+		mismatch(a.status, a.statusCheckers, "status", fmt.Sprintf("Actual status: %s %q", a.status, a.statusMessage)),
+		responseMismatch,
+		jsonMismatch(a.response, a.jsonCheckers, jsonActual),
+	)
+
+	if mismatches != "" {
+		msg := fmt.Sprintf("gRPC %s/%s\n  Request: %s\n  %s%s",
+			p.method.Service, p.method.Method, p.request, mismatches, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// RESPAssert provides assertions on a Redis protocol reply.
+type RESPAssert struct {
+	AssertBase
+
+	promise *RESPPromise
+	reply   respReply
+
+	// Unmet expectations, if any
+	failure string
+
+	expectNil       bool
+	stringCheckers  []Checker[string]
+	errorCheckers   []Checker[string]
+	integerCheckers []Checker[int]
+	lenCheckers     []Checker[int]
+	elementCheckers map[int][]Checker[string]
+}
+
+// String adds checkers for a simple or bulk string reply.
+// All checkers must pass.
+func (a *RESPAssert) String(checkers ...Checker[string]) *RESPAssert {
+	a.stringCheckers = append(a.stringCheckers, checkers...)
+	return a
+}
+
+// Error adds checkers for an error reply, e.g. Matches("^ERR ").
+// All checkers must pass.
+func (a *RESPAssert) Error(checkers ...Checker[string]) *RESPAssert {
+	a.errorCheckers = append(a.errorCheckers, checkers...)
+	return a
+}
+
+// Integer adds checkers for an integer reply.
+// All checkers must pass.
+func (a *RESPAssert) Integer(checkers ...Checker[int]) *RESPAssert {
+	a.integerCheckers = append(a.integerCheckers, checkers...)
+	return a
+}
+
+// Nil expects a null reply, e.g. GET on a missing key.
+func (a *RESPAssert) Nil() *RESPAssert {
+	a.expectNil = true
+	return a
+}
+
+// Len adds checkers for the length of an array reply.
+// All checkers must pass.
+func (a *RESPAssert) Len(checkers ...Checker[int]) *RESPAssert {
+	a.lenCheckers = append(a.lenCheckers, checkers...)
+	return a
+}
+
+// Element adds checkers for the element at index i of an array reply.
+// Integer elements are checked in their decimal form.
+// All checkers must pass.
+func (a *RESPAssert) Element(i int, checkers ...Checker[string]) *RESPAssert {
+	if a.elementCheckers == nil {
+		a.elementCheckers = make(map[int][]Checker[string])
+	}
+
+	a.elementCheckers[i] = append(a.elementCheckers[i], checkers...)
+	return a
+}
+
+func (a *RESPAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	p.breadcrumbs.add("RESP %s → %s", truncateValue(formatCommand(p.args), 40), truncateValue(strings.ReplaceAll(a.reply.String(), "\n", " "), 40))
+
+	a.check()
+}
+
+func (a *RESPAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	p.issue()
+
+	conn, err := net.DialTimeout(p.network, p.addr, a.config.ExecuteTimeout)
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	conn = p.metrics.conn(conn)
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(a.config.ExecuteTimeout))
+
+	_, err = conn.Write(encodeRESP(p.args))
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+
+	a.reply, err = readRESP(bufio.NewReader(conn))
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("RESP %s\n  Invalid reply: %v%s", formatCommand(p.args), err, a.formatHelp()))
+	}
+
+	a.failure = a.evaluate()
+	return a.failure == ""
+}
+
+// evaluate describes every unmet expectation, or returns "" if all are met.
+// The reply itself is shown once, by check.
+func (a *RESPAssert) evaluate() string {
+	r := a.reply
+
+	var nilMismatch string
+	if a.expectNil && !r.null {
+		nilMismatch = "Expected reply: (nil)"
+	}
+
+	stringMismatch := mismatch(r.str, a.stringCheckers, "string", "")
+	isString := !r.null && (r.kind == respSimpleString || r.kind == respBulkString)
+	if len(a.stringCheckers) > 0 && !isString {
+		stringMismatch = fmt.Sprintf("Expected string: %s", a.stringCheckers[0].Expected())
+	}
+
+	errorMismatch := mismatch(r.str, a.errorCheckers, "error", "")
+	if len(a.errorCheckers) > 0 && r.kind != respError {
+		errorMismatch = fmt.Sprintf("Expected error: %s", a.errorCheckers[0].Expected())
+	}
+
+	integerMismatch := mismatch(int(r.integer), a.integerCheckers, "integer", "")
+	if len(a.integerCheckers) > 0 && r.kind != respInteger {
+		integerMismatch = fmt.Sprintf("Expected integer: %s", a.integerCheckers[0].Expected())
+	}
+
+	var arrayMismatches []string
+	isArray := !r.null && r.kind == respArray
+	if (len(a.lenCheckers) > 0 || len(a.elementCheckers) > 0) && !isArray {
+		arrayMismatches = append(arrayMismatches, "Expected reply: array")
+	} else {
+		arrayMismatches = append(arrayMismatches, mismatch(len(r.array), a.lenCheckers, "array length", ""))
+
+		for _, i := range slices.Sorted(maps.Keys(a.elementCheckers)) {
+			checkers := a.elementCheckers[i]
+			if i >= len(r.array) {
+				arrayMismatches = append(arrayMismatches, fmt.Sprintf("Expected element #%d: %s", i+1, checkers[0].Expected()))
+				continue
+			}
+
+			element := r.array[i].str
+			if r.array[i].kind == respInteger {
+				element = strconv.FormatInt(r.array[i].integer, 10)
+			}
+
+			arrayMismatches = append(arrayMismatches, mismatch(element, checkers, fmt.Sprintf("element #%d", i+1), ""))
+		}
+	}
+
+	return joinMismatches(nilMismatch, stringMismatch, errorMismatch, integerMismatch, joinMismatches(arrayMismatches...))
+}
+
+func (a *RESPAssert) check() {
+	p := a.promise
+
+	if a.failure != "" {
+		actual := a.reply.String()
+		if strings.Contains(actual, "\n") {
+			actual = "\n" + indent(actual, "    ")
+		}
+
+		msg := fmt.Sprintf("RESP %s\n  %s\n  Actual reply: %s%s",
+			formatCommand(p.args), a.failure, actual, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// SSEAssert provides assertions on the events received from a stream.
+type SSEAssert struct {
+	AssertBase
+
+	promise *SSEPromise
+	events  []sseEvent
+
+	// Unmet expectations, if any
+	failure string
+
+	countCheckers   []Checker[int]
+	dataCheckers    map[int][]Checker[string]
+	nameCheckers    map[int][]Checker[string]
+	inOrderCheckers []Checker[string]
+}
+
+// Count adds checkers for the number of events received within the window.
+// All checkers must pass.
+func (a *SSEAssert) Count(checkers ...Checker[int]) *SSEAssert {
+	a.countCheckers = append(a.countCheckers, checkers...)
+	return a
+}
+
+// Event adds checkers for the data of the event at index i, which must arrive.
+// All checkers must pass.
+func (a *SSEAssert) Event(i int, checkers ...Checker[string]) *SSEAssert {
+	if a.dataCheckers == nil {
+		a.dataCheckers = make(map[int][]Checker[string])
+	}
+
+	a.dataCheckers[i] = append(a.dataCheckers[i], checkers...)
+	return a
+}
+
+// EventName adds checkers for the name of the event at index i, which must arrive.
+// Events without an explicit name are called "message".
+// All checkers must pass.
+func (a *SSEAssert) EventName(i int, checkers ...Checker[string]) *SSEAssert {
+	if a.nameCheckers == nil {
+		a.nameCheckers = make(map[int][]Checker[string])
+	}
+
+	a.nameCheckers[i] = append(a.nameCheckers[i], checkers...)
+	return a
+}
+
+// InOrder expects events whose data pass each checker to arrive in the given
+// order, possibly with other events in between.
+func (a *SSEAssert) InOrder(checkers ...Checker[string]) *SSEAssert {
+	a.inOrderCheckers = append(a.inOrderCheckers, checkers...)
+	return a
+}
+
+func (a *SSEAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+
+	a.check()
+}
+
+func (a *SSEAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	// The stream is bounded by the window rather than a client timeout
+	client := newHTTPClient(0, p.socketPath, p.tls, false)
+	client.Transport = p.metrics.transport(client.Transport)
+
+	ctx, cancel := context.WithTimeout(p.ctx, p.window)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	p.issue()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		p.watchdog.checkAfterError(p.ctx)
+		panic(fmt.Sprintf("An error occurred: %v", err))
+	}
+	defer resp.Body.Close()
+
+	a.events = nil
+	if resp.StatusCode != http.StatusOK {
+		a.failure = fmt.Sprintf("Expected status: 200\n  Actual status: %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		return false
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "text/event-stream") {
+		a.failure = fmt.Sprintf("Expected Content-Type: text/event-stream\n  Actual Content-Type: %q", contentType)
+		return false
+	}
+
+	// Trigger the expected events while reading
+	duringDone := make(chan any, 1)
+	if p.during != nil {
+		go func() {
+			defer func() {
+				duringDone <- recover()
+			}()
+
+			p.during()
+		}()
+	} else {
+		duringDone <- nil
+	}
+
+	needed := 0
+	for i := range a.dataCheckers {
+		needed = max(needed, i+1)
+	}
+	for i := range a.nameCheckers {
+		needed = max(needed, i+1)
+	}
+	collectAll := len(a.countCheckers) > 0 || len(a.inOrderCheckers) > 0
+
+	readSSE(resp.Body, func(event sseEvent) bool {
+		a.events = append(a.events, event)
+		return collectAll || len(a.events) < needed
 	})
+	cancel()
+
+	if err := <-duringDone; err != nil {
+		panic(err)
+	}
+
+	a.failure = a.evaluate()
+	return a.failure == ""
+}
+
+// evaluate describes every unmet expectation, or returns "" if all are met.
+func (a *SSEAssert) evaluate() string {
+	p := a.promise
+
+	var eventMismatches []string
+	indices := append(slices.Collect(maps.Keys(a.dataCheckers)), slices.Collect(maps.Keys(a.nameCheckers))...)
+	slices.Sort(indices)
+	for _, i := range slices.Compact(indices) {
+		if i >= len(a.events) {
+			eventMismatches = append(eventMismatches,
+				fmt.Sprintf("Expected event #%d within %s\n  Actual events: %d", i+1, p.window, len(a.events)))
+			continue
+		}
+
+		event := a.events[i]
+		eventMismatches = append(eventMismatches,
+			mismatch(event.name, a.nameCheckers[i], fmt.Sprintf("event #%d name", i+1),
+				fmt.Sprintf("Actual event #%d name: %q", i+1, event.name)),
+			mismatch(event.data, a.dataCheckers[i], fmt.Sprintf("event #%d", i+1),
+				fmt.Sprintf("Actual event #%d: %q", i+1, event.data)),
+		)
+	}
+
+	var orderMismatch string
+	next := 0
+	for _, event := range a.events {
+		if next < len(a.inOrderCheckers) && a.inOrderCheckers[next].Check(event.data) {
+			next++
+		}
+	}
+	if next < len(a.inOrderCheckers) {
+		orderMismatch = fmt.Sprintf("Expected events in order, next: %s\n  Actual events:\n%s",
+			a.inOrderCheckers[next].Expected(), indent(a.formatEvents(), "    "))
+	}
+
+	return joinMismatches(
+		mismatch(len(a.events), a.countCheckers, "events", fmt.Sprintf("Actual events: %d", len(a.events))),
+		joinMismatches(eventMismatches...),
+		orderMismatch,
+	)
+}
+
+// formatEvents lists the received events, one per line.
+func (a *SSEAssert) formatEvents() string {
+	if len(a.events) == 0 {
+		return "(none)"
+	}
+
+	var lines []string
+	for i, event := range a.events {
+		lines = append(lines, fmt.Sprintf("%d) %s: %q", i+1, event.name, event.data))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (a *SSEAssert) check() {
+	p := a.promise
+
+	if a.failure != "" {
+		msg := fmt.Sprintf("SSE %s\n  %s%s", p.url, a.failure, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// FDAssert provides assertions on the file descriptors a process has open.
+type FDAssert struct {
+	AssertBase
+
+	promise *FDPromise
+	count   int
+	err     error
+
+	countCheckers  []Checker[int]
+	growthCheckers []Checker[int]
+}
+
+// Count adds checkers for the number of file descriptors open.
+// All checkers must pass.
+func (a *FDAssert) Count(checkers ...Checker[int]) *FDAssert {
+	a.countCheckers = append(a.countCheckers, checkers...)
+	return a
+}
+
+// Growth adds checkers for how many more file descriptors are open than
+// when FDCount was called, e.g. Growth(LessThan(10)) after a load test.
+// All checkers must pass.
+func (a *FDAssert) Growth(checkers ...Checker[int]) *FDAssert {
+	a.growthCheckers = append(a.growthCheckers, checkers...)
+	return a
+}
+
+func (a *FDAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	a.sampling = p.run(a.execute)
+	p.breadcrumbs.add("FDS %s → %d", p.name, a.count)
+
+	a.check()
+}
+
+func (a *FDAssert) execute() bool {
+	p := a.promise
+	p.watchdog.check()
+
+	a.count, a.err = p.count()
+	if a.err != nil {
+		return false
+	}
+
+	return checkAll(a.count, a.countCheckers, nil) &&
+		checkAll(a.count-p.baseline, a.growthCheckers, nil)
+}
+
+func (a *FDAssert) check() {
+	p := a.promise
+
+	if a.err != nil {
+		panic(fmt.Sprintf("FDS %s\n  Failed to count open file descriptors: %v%s", p.name, a.err, a.formatHelp()))
+	}
+
+	mismatches := joinMismatches(
+		mismatch(a.count, a.countCheckers, "open file descriptors",
+			fmt.Sprintf("Actual open file descriptors: %d", a.count)),
+		mismatch(a.count-p.baseline, a.growthCheckers, "growth in open file descriptors",
+			fmt.Sprintf("Actual growth: %+d (%d %s %d)", a.count-p.baseline, p.baseline, Marks.Arrow, a.count)),
+	)
+
+	if mismatches != "" {
+		msg := fmt.Sprintf("FDS %s\n  %s%s", p.name, mismatches, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// ExitAssert provides assertions on how a process exited.
+type ExitAssert struct {
+	AssertBase
+
+	promise *ExitPromise
+	exit    processExit
+
+	exitCheckers []Checker[int]
+}
+
+// ExitCode adds expected exit code checkers, e.g. ExitCode(Is(0)). A process
+// that was killed by a signal has exit code -1.
+// All checkers must pass.
+func (a *ExitAssert) ExitCode(checkers ...Checker[int]) *ExitAssert {
+	a.exitCheckers = append(a.exitCheckers, checkers...)
+	return a
+}
+
+func (a *ExitAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	p.watchdog.check()
+	p.metrics.assertions.Add(1)
+
+	a.exit = p.wait()
+	if a.exit.unchecked {
+		p.breadcrumbs.add("EXIT %s", p.name)
+		return
+	}
+
+	var mismatches string
+	if a.exit.running {
+		p.breadcrumbs.add("EXIT %s → still running after %s", p.name, a.exit.after)
+		mismatches = fmt.Sprintf("Expected exit within: %s\n  Actual: still running", a.exit.after)
+	} else {
+		p.breadcrumbs.add("EXIT %s → %s", p.name, a.exit.how)
+		mismatches = mismatch(a.exit.exitCode, a.exitCheckers, "exit code", "Actual: exited "+a.exit.how)
+	}
+
+	if mismatches != "" {
+		msg := fmt.Sprintf("EXIT %s\n  %s%s", p.name, mismatches, a.formatHelp())
+		panic(msg)
+	}
+}
+
+// SignalAssert provides assertions on how a process exits after a signal.
+type SignalAssert struct {
+	AssertBase
+
+	promise *SignalPromise
+	exit    processExit
+
+	exitAfterCheckers []Checker[time.Duration]
+	exitCheckers      []Checker[int]
+}
+
+// ExitAfter adds checkers for how long the process took to exit after the
+// signal, e.g. ExitAfter(LessThan(time.Second)).
+// All checkers must pass.
+func (a *SignalAssert) ExitAfter(checkers ...Checker[time.Duration]) *SignalAssert {
+	a.exitAfterCheckers = append(a.exitAfterCheckers, checkers...)
+	return a
+}
+
+// ExitCode adds expected exit code checkers. A process that was killed by a
+// signal has exit code -1.
+// All checkers must pass.
+func (a *SignalAssert) ExitCode(checkers ...Checker[int]) *SignalAssert {
+	a.exitCheckers = append(a.exitCheckers, checkers...)
+	return a
+}
+
+func (a *SignalAssert) Assert(help string) {
+	a.help = help
+
+	p := a.promise
+	p.watchdog.check()
+	p.metrics.assertions.Add(1)
+
+	a.exit = p.send()
+
+	name := signalName(p.signal)
+	if a.exit.unchecked {
+		p.breadcrumbs.add("%s %s", name, p.name)
+		return
+	}
+	p.breadcrumbs.add("%s %s → exit %d after %s", name, p.name, a.exit.exitCode, a.exit.after.Round(time.Millisecond))
+
+	actual := fmt.Sprintf("Actual: exited after %s", a.exit.after.Round(time.Millisecond))
+	if a.exit.killed {
+		actual = fmt.Sprintf("Actual: still running after %s, killed with SIGKILL", a.exit.after.Round(time.Millisecond))
+	}
+
+	mismatches := joinMismatches(
+		mismatch(a.exit.after, a.exitAfterCheckers, fmt.Sprintf("exit after %s", name), actual),
+		mismatch(a.exit.exitCode, a.exitCheckers, "exit code", fmt.Sprintf("Actual exit code: %d", a.exit.exitCode)),
+	)
+
+	if mismatches != "" {
+		msg := fmt.Sprintf("%s %s\n  %s%s", name, p.name, mismatches, a.formatHelp())
+		panic(msg)
+	}
 }
@@ -0,0 +1,52 @@
+package attest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// breadcrumbCount is how many recent steps are kept for failure messages.
+const breadcrumbCount = 5
+
+// breadcrumbs is a ring buffer of the last few operations the harness
+// performed, giving failures some narrative context without verbose output.
+type breadcrumbs struct {
+	mu    sync.Mutex
+	steps []string
+	next  int
+}
+
+// add records a step, evicting the oldest one if the buffer is full. Only
+// format's symbols are rendered for the terminal, as args may hold output
+// from the process that's shown as is.
+func (b *breadcrumbs) add(format string, args ...any) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	step := fmt.Sprintf(Marks.render(format), args...)
+	if len(b.steps) < breadcrumbCount {
+		b.steps = append(b.steps, step)
+		return
+	}
+
+	b.steps[b.next] = step
+	b.next = (b.next + 1) % breadcrumbCount
+}
+
+// String lists the recorded steps from oldest to newest.
+func (b *breadcrumbs) String() string {
+	if b == nil {
+		return ""
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ordered := append(append([]string{}, b.steps[b.next:]...), b.steps[:b.next]...)
+	return strings.Join(ordered, "; ")
+}
@@ -0,0 +1,151 @@
+package attest
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// timeoutMultiplierEnv overrides the timeout multiplier, e.g. on a slow CI runner.
+const timeoutMultiplierEnv = "LSFR_TIMEOUT_MULTIPLIER"
+
+// Calibration compares the machine against generous references, so only a
+// clearly slow machine gets longer timeouts, and never more than the cap.
+const (
+	referenceProcessStart = 20 * time.Millisecond
+	referenceRTT          = time.Millisecond
+	maxCalibratedFactor   = 4.0
+	calibrationSamples    = 5
+)
+
+// timeoutMultiplier resolves the factor timeouts are scaled by, preferring
+// the environment over the configuration and calibrating if neither sets one.
+func timeoutMultiplier(config *Config) (float64, error) {
+	if value := os.Getenv(timeoutMultiplierEnv); value != "" {
+		multiplier, err := strconv.ParseFloat(value, 64)
+		if err != nil || multiplier <= 0 || math.IsInf(multiplier, 0) {
+			return 0, fmt.Errorf("%s must be a positive number, got %q", timeoutMultiplierEnv, value)
+		}
+
+		return multiplier, nil
+	}
+
+	if config.TimeoutMultiplier > 0 {
+		return config.TimeoutMultiplier, nil
+	}
+
+	return calibrate(), nil
+}
+
+// calibrate measures how long starting a process and a localhost round trip
+// take and returns how much slower than the references the slowest of them is.
+func calibrate() float64 {
+	factor := 1.0
+	if start, ok := measureProcessStart(); ok {
+		factor = max(factor, float64(start)/float64(referenceProcessStart))
+	}
+
+	if rtt, ok := measureRTT(); ok {
+		factor = max(factor, float64(rtt)/float64(referenceRTT))
+	}
+
+	return math.Round(min(factor, maxCalibratedFactor)*10) / 10
+}
+
+// measureProcessStart returns the fastest of a few runs of a no-op shell.
+func measureProcessStart() (time.Duration, bool) {
+	fastest := time.Duration(math.MaxInt64)
+	for range calibrationSamples {
+		start := time.Now()
+		if err := exec.Command("sh", "-c", "exit 0").Run(); err != nil {
+			return 0, false
+		}
+
+		fastest = min(fastest, time.Since(start))
+	}
+
+	return fastest, true
+}
+
+// measureRTT returns the fastest of a few round trips over a localhost connection.
+func measureRTT() (time.Duration, bool) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, false
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 1)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				return
+			}
+			if _, err := conn.Write(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	conn, err := net.DialTimeout("tcp", listener.Addr().String(), time.Second)
+	if err != nil {
+		return 0, false
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	fastest := time.Duration(math.MaxInt64)
+	buf := make([]byte, 1)
+	for range calibrationSamples {
+		start := time.Now()
+		if _, err := conn.Write(buf); err != nil {
+			return 0, false
+		}
+		if _, err := conn.Read(buf); err != nil {
+			return 0, false
+		}
+
+		fastest = min(fastest, time.Since(start))
+	}
+
+	return fastest, true
+}
+
+// scaleTimeouts returns a copy of the configuration with its timeouts scaled
+// by multiplier. Poll intervals and delays are left as they are.
+func (c *Config) scaleTimeouts(multiplier float64) *Config {
+	scaled := *c
+	scaled.TimeoutMultiplier = multiplier
+	scaled.ProcessStartTimeout = scaleDuration(c.ProcessStartTimeout, multiplier)
+	scaled.ProcessShutdownTimeout = scaleDuration(c.ProcessShutdownTimeout, multiplier)
+	scaled.DefaultRetryTimeout = scaleDuration(c.DefaultRetryTimeout, multiplier)
+	scaled.ExecuteTimeout = scaleDuration(c.ExecuteTimeout, multiplier)
+
+	return &scaled
+}
+
+// Scaled returns d scaled by the run's TimeoutMultiplier, for time limits
+// that should grow on slow machines like timeouts do, e.g.
+// ExitAfter(LessThan(do.Scaled(2 * time.Second))).
+func (do *Do) Scaled(d time.Duration) time.Duration {
+	return scaleDuration(d, do.config.TimeoutMultiplier)
+}
+
+// scaleDuration scales d by multiplier, treating an unset multiplier as 1.
+func scaleDuration(d time.Duration, multiplier float64) time.Duration {
+	if multiplier <= 0 {
+		return d
+	}
+
+	return time.Duration(float64(d) * multiplier)
+}
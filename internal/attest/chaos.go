@@ -0,0 +1,261 @@
+package attest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ChaosFault is a kind of fault Chaos injects.
+type ChaosFault int
+
+const (
+	// ChaosKill kills a process with SIGKILL and starts it again later.
+	ChaosKill ChaosFault = iota + 1
+	// ChaosPause freezes a process with Pause and resumes it later.
+	ChaosPause
+	// ChaosPartition cuts a process off from the others with Partition and heals it later.
+	ChaosPartition
+	// ChaosRestart restarts a process gracefully with SIGTERM.
+	ChaosRestart
+)
+
+func (f ChaosFault) String() string {
+	switch f {
+	case ChaosKill:
+		return "kill"
+	case ChaosPause:
+		return "pause"
+	case ChaosPartition:
+		return "partition"
+	case ChaosRestart:
+		return "restart"
+	default:
+		return fmt.Sprintf("ChaosFault(%d)", int(f))
+	}
+}
+
+// Bounds of how long each fault lasts and how long the processes are left
+// alone before the next one, which short runs scale down to fit a few faults.
+const (
+	minChaosInterval = 200 * time.Millisecond
+	maxChaosInterval = 2 * time.Second
+)
+
+// ChaosRun is a sequence of faults being injected in the background.
+type ChaosRun struct {
+	seed uint64
+	// interval is the longest a fault lasts or the processes are left alone
+	interval time.Duration
+	// stop ends the run early, done is closed once it's over
+	stop chan struct{}
+	done chan struct{}
+	// err is why injecting a fault failed, if it did
+	err any
+}
+
+// Chaos injects random faults into the processes started by the harness for
+// duration, in the background, while the test keeps checking that the
+// system behaves, e.g.
+//
+//	chaos := do.Chaos(0, 10*time.Second)
+//	for chaos.Running() {
+//		do.HTTP("node-1", "GET", "/kv/key").T().Status(Is(200)).Assert(help)
+//	}
+//
+// Faults are picked from faults, or all of them if none are given, and hit
+// one process at a time, each undone before the next, so a majority of a
+// cluster stays up. Processes are started again, resumed and healed when the
+// run ends; healing also removes latency, packet loss and throttling.
+//
+// The sequence of faults is drawn from seed, a random one if 0, which is
+// printed if the test fails so the same faults can be replayed.
+func (do *Do) Chaos(seed uint64, duration time.Duration, faults ...ChaosFault) *ChaosRun {
+	if duration <= 0 {
+		panic(fmt.Sprintf("Chaos() requires a positive duration, got %s", duration))
+	}
+	if do.chaos != nil && do.chaos.Running() {
+		panic("Chaos() is already running in this test")
+	}
+
+	if len(faults) == 0 {
+		faults = []ChaosFault{ChaosKill, ChaosPause, ChaosPartition, ChaosRestart}
+	}
+
+	// Only processes the harness runs itself can be killed and paused
+	var targets []string
+	do.processes.Range(func(name string, proc *Process) bool {
+		if !proc.manual && proc.server == nil && proc.cmd != nil {
+			targets = append(targets, name)
+		}
+
+		return true
+	})
+	slices.Sort(targets)
+
+	if len(targets) == 0 && !do.replaying() {
+		panic("Chaos() requires processes started by the harness")
+	}
+
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	run := &ChaosRun{
+		seed:     seed,
+		interval: min(max(duration/4, minChaosInterval), maxChaosInterval),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	do.chaos = run
+
+	do.breadcrumbs.add("CHAOS seed %d for %s (%s)", seed, duration, strings.Join(faultNames(faults), ", "))
+
+	// Nothing runs during a dry run, so there's nothing to disrupt
+	if do.replaying() {
+		close(run.done)
+		return run
+	}
+
+	go run.inject(do, duration, faults, targets)
+
+	return run
+}
+
+// faultNames returns the names of faults.
+func faultNames(faults []ChaosFault) []string {
+	names := make([]string, len(faults))
+	for i, f := range faults {
+		names[i] = f.String()
+	}
+
+	return names
+}
+
+// inject applies a fault to a random target, holds it, undoes it and waits,
+// over and over until the run is over.
+func (c *ChaosRun) inject(do *Do, duration time.Duration, faults []ChaosFault, targets []string) {
+	defer close(c.done)
+
+	rng := rand.New(rand.NewPCG(c.seed, c.seed))
+	end := time.Now().Add(duration)
+
+	// undo recovers from the fault in effect, if any
+	var undo func()
+	defer func() {
+		if err := recover(); err != nil {
+			c.err = err
+		}
+
+		if undo != nil {
+			func() {
+				defer func() {
+					if err := recover(); err != nil && c.err == nil {
+						c.err = err
+					}
+				}()
+
+				undo()
+			}()
+		}
+	}()
+
+	for c.sleep(rng, end) {
+		fault, name := faults[rng.IntN(len(faults))], targets[rng.IntN(len(targets))]
+		switch fault {
+		case ChaosKill:
+			proc := do.getProcess(name)
+			do.Kill(name)
+			undo = func() {
+				do.breadcrumbs.add("START %s", name)
+				do.respawn(name, proc)
+			}
+		case ChaosPause:
+			do.Pause(name)
+			undo = func() { do.Resume(name) }
+		case ChaosPartition:
+			do.Partition(name)
+			undo = do.Heal
+		case ChaosRestart:
+			do.Restart(name)
+		default:
+			panic(fmt.Sprintf("Chaos() doesn't know how to inject %s", fault))
+		}
+
+		c.sleep(rng, end)
+		if undo != nil {
+			undo()
+			undo = nil
+		}
+	}
+}
+
+// sleep waits a random interval, cut short by the end of the run, and
+// reports whether there's time left for another fault.
+func (c *ChaosRun) sleep(rng *rand.Rand, end time.Time) bool {
+	d := minChaosInterval + time.Duration(rng.Int64N(int64(c.interval-minChaosInterval)+1))
+	d = min(d, time.Until(end))
+
+	select {
+	case <-c.stop:
+		return false
+	case <-time.After(d):
+		return time.Now().Before(end)
+	}
+}
+
+// Seed returns the seed the faults are drawn from.
+func (c *ChaosRun) Seed() uint64 {
+	return c.seed
+}
+
+// Running reports whether faults are still being injected.
+func (c *ChaosRun) Running() bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Wait blocks until the run is over and every process has recovered. It
+// panics if a fault couldn't be injected or undone.
+func (c *ChaosRun) Wait() {
+	<-c.done
+	if c.err != nil {
+		panic(c.err)
+	}
+}
+
+// stopChaos ends the current test's chaos run early, if any, and returns why
+// it failed, if it did.
+func (do *Do) stopChaos() any {
+	c := do.chaos
+	if c == nil {
+		return nil
+	}
+
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+	<-c.done
+
+	err := c.err
+	c.err = nil
+	return err
+}
+
+// chaosNote returns a note with the seed of the current test's chaos run,
+// if any, for failures.
+func (do *Do) chaosNote() string {
+	if do.chaos == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("Faults were injected with do.Chaos seed %d, pass it to Chaos() to replay them", do.chaos.seed)
+}
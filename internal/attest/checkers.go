@@ -1,11 +1,22 @@
 package attest
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
+	"math"
+	"mime/multipart"
 	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/goccy/go-yaml"
 	"github.com/tidwall/gjson"
 )
 
@@ -62,6 +73,64 @@ func (m isNullChecker[T]) Expected() string {
 	return "null"
 }
 
+// jsonTypeChecker validates the type of a JSON value.
+type jsonTypeChecker struct {
+	name string
+}
+
+// IsNumber creates a checker that requires a JSON number, e.g.
+// JSON("term", IsNumber()), which the string "1" doesn't pass.
+func IsNumber() jsonTypeChecker {
+	return jsonTypeChecker{name: "number"}
+}
+
+// IsString creates a checker that requires a JSON string.
+func IsString() jsonTypeChecker {
+	return jsonTypeChecker{name: "string"}
+}
+
+// IsBool creates a checker that requires a JSON true or false.
+func IsBool() jsonTypeChecker {
+	return jsonTypeChecker{name: "boolean"}
+}
+
+// IsArray creates a checker that requires a JSON array.
+func IsArray() jsonTypeChecker {
+	return jsonTypeChecker{name: "array"}
+}
+
+// IsObject creates a checker that requires a JSON object.
+func IsObject() jsonTypeChecker {
+	return jsonTypeChecker{name: "object"}
+}
+
+// Check parses actual as a JSON document, e.g. a whole response body.
+func (m jsonTypeChecker) Check(actual string) bool {
+	return gjson.Valid(actual) && m.checkResult(gjson.Parse(actual))
+}
+
+// checkResult checks a value found by gjson, e.g. a field.
+func (m jsonTypeChecker) checkResult(result gjson.Result) bool {
+	switch m.name {
+	case "number":
+		return result.Type == gjson.Number
+	case "string":
+		return result.Type == gjson.String
+	case "boolean":
+		return result.Type == gjson.True || result.Type == gjson.False
+	case "array":
+		return result.IsArray()
+	case "object":
+		return result.IsObject()
+	default:
+		return false
+	}
+}
+
+func (m jsonTypeChecker) Expected() string {
+	return fmt.Sprintf("a JSON %s", m.name)
+}
+
 // containsChecker validates that a string contains a substring.
 type containsChecker struct {
 	substring string
@@ -80,6 +149,185 @@ func (m containsChecker) Expected() string {
 	return fmt.Sprintf("containing %q", m.substring)
 }
 
+// containsAllChecker validates that a string contains several substrings.
+type containsAllChecker struct {
+	substrings []string
+}
+
+// ContainsAll creates a checker that checks if actual contains every one of
+// the substrings, in any order.
+func ContainsAll(substrings ...string) containsAllChecker {
+	return containsAllChecker{substrings: substrings}
+}
+
+func (m containsAllChecker) Check(actual string) bool {
+	return len(m.differences(actual)) == 0
+}
+
+func (m containsAllChecker) Expected() string {
+	return fmt.Sprintf("containing all of %s", quoteAll(m.substrings))
+}
+
+// differences lists the substrings actual is missing.
+func (m containsAllChecker) differences(actual string) []string {
+	var missing []string
+	for _, substring := range m.substrings {
+		if !strings.Contains(actual, substring) {
+			missing = append(missing, fmt.Sprintf("missing %q", substring))
+		}
+	}
+
+	return missing
+}
+
+// containsInOrderChecker validates that a string contains several
+// substrings, one after another.
+type containsInOrderChecker struct {
+	substrings []string
+}
+
+// ContainsInOrder creates a checker that checks if actual contains the
+// substrings in the given order, without overlapping, e.g. lines of a log.
+func ContainsInOrder(substrings ...string) containsInOrderChecker {
+	return containsInOrderChecker{substrings: substrings}
+}
+
+func (m containsInOrderChecker) Check(actual string) bool {
+	return len(m.differences(actual)) == 0
+}
+
+func (m containsInOrderChecker) Expected() string {
+	return fmt.Sprintf("containing %s in order", quoteAll(m.substrings))
+}
+
+// differences describes the first substring that isn't where it should be.
+func (m containsInOrderChecker) differences(actual string) []string {
+	rest := actual
+	for i, substring := range m.substrings {
+		at := strings.Index(rest, substring)
+		if at >= 0 {
+			rest = rest[at+len(substring):]
+			continue
+		}
+
+		if i > 0 && strings.Contains(actual, substring) {
+			return []string{fmt.Sprintf("%q appears, but not after %q", substring, m.substrings[i-1])}
+		}
+
+		return []string{fmt.Sprintf("missing %q", substring)}
+	}
+
+	return nil
+}
+
+// quoteAll quotes each string and joins them with commas.
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// emptyChecker validates that a value is, or isn't, empty.
+type emptyChecker struct {
+	empty bool
+}
+
+// Empty creates a checker that checks if actual is empty. A JSON field is
+// empty when it's missing, null, "", [] or {}.
+func Empty() emptyChecker {
+	return emptyChecker{empty: true}
+}
+
+// NotEmpty creates a checker that checks if actual isn't empty, e.g. that
+// JSON("leader", NotEmpty()) is set after an election.
+func NotEmpty() emptyChecker {
+	return emptyChecker{empty: false}
+}
+
+func (m emptyChecker) Check(actual string) bool {
+	return (actual == "") == m.empty
+}
+
+// checkResult checks a value found by gjson, e.g. a field.
+func (m emptyChecker) checkResult(result gjson.Result) bool {
+	empty := result.Type == gjson.Null || (result.Type == gjson.String && result.Str == "")
+	if result.IsArray() || result.IsObject() {
+		empty = true
+		result.ForEach(func(_, _ gjson.Result) bool {
+			empty = false
+			return false
+		})
+	}
+
+	return empty == m.empty
+}
+
+func (m emptyChecker) Expected() string {
+	if m.empty {
+		return "empty"
+	}
+
+	return "not empty"
+}
+
+// equalFoldChecker validates that a string equals a value, ignoring case.
+type equalFoldChecker struct {
+	value string
+}
+
+// EqualFold creates a checker that checks if actual equals value under
+// Unicode case folding, e.g. for header values and hostnames.
+func EqualFold(value string) equalFoldChecker {
+	return equalFoldChecker{value: value}
+}
+
+func (m equalFoldChecker) Check(actual string) bool {
+	return strings.EqualFold(actual, m.value)
+}
+
+func (m equalFoldChecker) Expected() string {
+	return fmt.Sprintf("%q, ignoring case", m.value)
+}
+
+// hasPrefixChecker validates that a string starts with a prefix.
+type hasPrefixChecker struct {
+	prefix string
+}
+
+// HasPrefix creates a checker that checks if actual starts with the prefix.
+func HasPrefix(prefix string) hasPrefixChecker {
+	return hasPrefixChecker{prefix: prefix}
+}
+
+func (m hasPrefixChecker) Check(actual string) bool {
+	return strings.HasPrefix(actual, m.prefix)
+}
+
+func (m hasPrefixChecker) Expected() string {
+	return fmt.Sprintf("starting with %q", m.prefix)
+}
+
+// hasSuffixChecker validates that a string ends with a suffix.
+type hasSuffixChecker struct {
+	suffix string
+}
+
+// HasSuffix creates a checker that checks if actual ends with the suffix.
+func HasSuffix(suffix string) hasSuffixChecker {
+	return hasSuffixChecker{suffix: suffix}
+}
+
+func (m hasSuffixChecker) Check(actual string) bool {
+	return strings.HasSuffix(actual, m.suffix)
+}
+
+func (m hasSuffixChecker) Expected() string {
+	return fmt.Sprintf("ending with %q", m.suffix)
+}
+
 // matchesChecker validates that a string matches a regex pattern.
 type matchesChecker struct {
 	pattern *regexp.Regexp
@@ -162,6 +410,174 @@ func (m oneOfChecker[T]) Expected() string {
 	return fmt.Sprintf("one of [%v, %v, %v, ... and %d more]", m.values[0], m.values[1], m.values[2], len(m.values)-3)
 }
 
+// lessThanChecker checks that a value is below a bound.
+type lessThanChecker[T cmp.Ordered] struct {
+	value T
+}
+
+// LessThan creates a checker that requires a value less than the given one.
+func LessThan[T cmp.Ordered](value T) lessThanChecker[T] {
+	return lessThanChecker[T]{value: value}
+}
+
+func (m lessThanChecker[T]) Check(actual T) bool {
+	return actual < m.value
+}
+
+func (m lessThanChecker[T]) Expected() string {
+	return fmt.Sprintf("less than %v", m.value)
+}
+
+// greaterThanChecker checks that a value is above a bound.
+type greaterThanChecker[T cmp.Ordered] struct {
+	value T
+}
+
+// GreaterThan creates a checker that requires a value greater than the given one.
+func GreaterThan[T cmp.Ordered](value T) greaterThanChecker[T] {
+	return greaterThanChecker[T]{value: value}
+}
+
+func (m greaterThanChecker[T]) Check(actual T) bool {
+	return actual > m.value
+}
+
+func (m greaterThanChecker[T]) Expected() string {
+	return fmt.Sprintf("greater than %v", m.value)
+}
+
+// betweenChecker checks that a value is within a range.
+type betweenChecker[T cmp.Ordered] struct {
+	low, high T
+}
+
+// Between creates a checker that requires a value from low to high,
+// inclusive, e.g. Between(200, 299) for any successful status.
+func Between[T cmp.Ordered](low, high T) betweenChecker[T] {
+	if low > high {
+		panic(fmt.Sprintf("Between() requires low <= high, got %v and %v", low, high))
+	}
+
+	return betweenChecker[T]{low: low, high: high}
+}
+
+func (m betweenChecker[T]) Check(actual T) bool {
+	return actual >= m.low && actual <= m.high
+}
+
+func (m betweenChecker[T]) Expected() string {
+	return fmt.Sprintf("between %v and %v", m.low, m.high)
+}
+
+// nonDecreasingChecker checks that each value is at least the last one.
+type nonDecreasingChecker[T cmp.Ordered] struct {
+	mu   sync.Mutex
+	last T
+	seen bool
+}
+
+// NonDecreasing creates a stateful checker that requires every value it
+// checks to be at least the largest seen so far, e.g. a Raft term polled with
+// Consistently(): Body(JSON("term", NonDecreasing[int]())). Its state is kept
+// across Assert calls that share it.
+func NonDecreasing[T cmp.Ordered]() *nonDecreasingChecker[T] {
+	return &nonDecreasingChecker[T]{}
+}
+
+func (m *nonDecreasingChecker[T]) Check(actual T) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.seen && actual < m.last {
+		return false
+	}
+
+	m.last, m.seen = actual, true
+	return true
+}
+
+func (m *nonDecreasingChecker[T]) Expected() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.seen {
+		return "non-decreasing"
+	}
+
+	return fmt.Sprintf("non-decreasing, at least %v", m.last)
+}
+
+// closeToChecker checks that a float is within a tolerance of a value.
+type closeToChecker struct {
+	value     float64
+	tolerance float64
+}
+
+// CloseTo creates a checker that requires a float within tolerance of value,
+// inclusive, e.g. Body(JSON("avg_latency_ms", CloseTo(12.5, 0.1))).
+func CloseTo(value, tolerance float64) closeToChecker {
+	if tolerance < 0 || math.IsNaN(tolerance) {
+		panic(fmt.Sprintf("CloseTo() requires a tolerance >= 0, got %v", tolerance))
+	}
+
+	return closeToChecker{value: value, tolerance: tolerance}
+}
+
+func (m closeToChecker) Check(actual float64) bool {
+	return math.Abs(actual-m.value) <= m.tolerance
+}
+
+func (m closeToChecker) Expected() string {
+	return fmt.Sprintf("%v ± %v", m.value, m.tolerance)
+}
+
+// timeChecker validates a string holding an RFC 3339 timestamp, e.g. a JSON
+// field, optionally checking when it is.
+type timeChecker struct {
+	expected string
+	check    func(t time.Time) bool
+}
+
+// IsRFC3339 creates a checker that requires an RFC 3339 timestamp, with or
+// without fractional seconds.
+func IsRFC3339() timeChecker {
+	return timeChecker{expected: "an RFC 3339 time"}
+}
+
+// After creates a checker that requires an RFC 3339 timestamp after t.
+func After(t time.Time) timeChecker {
+	return timeChecker{
+		expected: fmt.Sprintf("an RFC 3339 time after %s", t.Format(time.RFC3339Nano)),
+		check:    func(actual time.Time) bool { return actual.After(t) },
+	}
+}
+
+// WithinDuration creates a checker that requires an RFC 3339 timestamp at
+// most d before or after now, e.g. a TTL's expiry with
+// JSON("expires_at", WithinDuration(time.Now().Add(ttl), time.Second)).
+func WithinDuration(now time.Time, d time.Duration) timeChecker {
+	return timeChecker{
+		expected: fmt.Sprintf("an RFC 3339 time within %s of %s", d, now.Format(time.RFC3339Nano)),
+		check: func(actual time.Time) bool {
+			diff := actual.Sub(now)
+			return diff >= -d && diff <= d
+		},
+	}
+}
+
+func (m timeChecker) Check(actual string) bool {
+	t, err := time.Parse(time.RFC3339Nano, actual)
+	if err != nil {
+		return false
+	}
+
+	return m.check == nil || m.check(t)
+}
+
+func (m timeChecker) Expected() string {
+	return m.expected
+}
+
 // notChecker negates another checker.
 type notChecker[T any] struct {
 	checker Checker[T]
@@ -180,40 +596,512 @@ func (m notChecker[T]) Expected() string {
 	return fmt.Sprintf("not %s", m.checker.Expected())
 }
 
+// funcChecker validates with a predicate.
+type funcChecker[T any] struct {
+	desc string
+	fn   func(T) bool
+}
+
+// CheckFunc creates a checker from a one-off predicate, described by desc in
+// failure messages, e.g.
+//
+//	CheckFunc("an RFC 3339 time", func(s string) bool {
+//		_, err := time.Parse(time.RFC3339, s)
+//		return err == nil
+//	})
+func CheckFunc[T any](desc string, fn func(T) bool) funcChecker[T] {
+	return funcChecker[T]{desc: desc, fn: fn}
+}
+
+func (m funcChecker[T]) Check(actual T) bool {
+	return m.fn(actual)
+}
+
+func (m funcChecker[T]) Expected() string {
+	return m.desc
+}
+
+// captureChecker stores the value it checks so later requests can use it.
+type captureChecker[T any] struct {
+	dst *T
+}
+
+// Capture creates a checker that always passes and stores the value it
+// checks in dst, e.g. Header("ETag", Capture(&etag)), so a later request in
+// the same test can use it. After retries, dst holds the last attempt's value.
+func Capture[T any](dst *T) captureChecker[T] {
+	if dst == nil {
+		panic("Capture() requires a non-nil destination")
+	}
+
+	return captureChecker[T]{dst: dst}
+}
+
+func (m captureChecker[T]) Check(actual T) bool {
+	*m.dst = actual
+	return true
+}
+
+func (m captureChecker[T]) Expected() string {
+	return "any value"
+}
+
+// describedChecker labels another checker with what the value represents.
+type describedChecker[T any] struct {
+	label   string
+	checker Checker[T]
+}
+
+// Describe labels checker with what the checked value represents, which
+// failure messages show instead of the raw label or path, e.g.
+// JSON("leader", Describe("leader address", Is("127.0.0.1:8001"))).
+func Describe[T any](label string, checker Checker[T]) describedChecker[T] {
+	return describedChecker[T]{label: label, checker: checker}
+}
+
+func (m describedChecker[T]) Check(actual T) bool {
+	return m.checker.Check(actual)
+}
+
+func (m describedChecker[T]) Expected() string {
+	return m.checker.Expected()
+}
+
+func (m describedChecker[T]) description() string {
+	return m.label
+}
+
+// described is a checker labelled with Describe.
+type described interface {
+	description() string
+}
+
+// allOfChecker requires every one of several checkers to pass.
+type allOfChecker[T any] struct {
+	checkers []Checker[T]
+}
+
+// AllOf creates a checker that passes when all of checkers pass, e.g. to
+// negate several checks at once with Not(AllOf(...)).
+func AllOf[T any](checkers ...Checker[T]) allOfChecker[T] {
+	return allOfChecker[T]{checkers: checkers}
+}
+
+func (m allOfChecker[T]) Check(actual T) bool {
+	for _, checker := range m.checkers {
+		if !checker.Check(actual) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m allOfChecker[T]) Expected() string {
+	return joinExpected(m.checkers, " and ")
+}
+
+// anyOfChecker requires at least one of several checkers to pass.
+type anyOfChecker[T any] struct {
+	checkers []Checker[T]
+}
+
+// AnyOf creates a checker that passes when any of checkers passes, e.g.
+// Status(AnyOf(Is(200), Is(307))). For alternatives that check several
+// values, e.g. a status and a header, use HTTPAssert.Or.
+func AnyOf[T any](checkers ...Checker[T]) anyOfChecker[T] {
+	return anyOfChecker[T]{checkers: checkers}
+}
+
+func (m anyOfChecker[T]) Check(actual T) bool {
+	for _, checker := range m.checkers {
+		if checker.Check(actual) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m anyOfChecker[T]) Expected() string {
+	return joinExpected(m.checkers, " or ")
+}
+
+// joinExpected describes several checkers joined by sep, in parentheses so
+// they read unambiguously when nested.
+func joinExpected[T any](checkers []Checker[T], sep string) string {
+	expected := make([]string, len(checkers))
+	for i, checker := range checkers {
+		expected[i] = checker.Expected()
+	}
+
+	return "(" + strings.Join(expected, sep) + ")"
+}
+
 // JSONFieldChecker pairs a gjson path with a checker for that field.
 type JSONFieldChecker struct {
 	path    string
-	checker Checker[string]
+	checker interface{ Expected() string }
+	// check validates the field found at path
+	check func(result gjson.Result) bool
 }
 
-// JSON creates a checker that extracts a JSON field at the given path and validates it.
-func JSON(path string, checker Checker[string]) JSONFieldChecker {
-	return JSONFieldChecker{path: path, checker: checker}
+// JSON creates a checker that extracts a JSON field at the given path and
+// validates it. Checkers for strings see the field's string representation,
+// while checkers for numbers and booleans need a field of that type, e.g.
+// JSON("term", GreaterThan(1)).
+func JSON[T any](path string, checker Checker[T]) JSONFieldChecker {
+	return JSONFieldChecker{
+		path:    path,
+		checker: checker,
+		check: func(result gjson.Result) bool {
+			return checkJSONField(result, checker)
+		},
+	}
 }
 
-func (m JSONFieldChecker) Check(actual string) bool {
-	result := gjson.Get(actual, m.path)
-
-	switch m.checker.(type) {
-	case isNullChecker[string]:
+// checkJSONField validates a JSON field with a checker for T.
+func checkJSONField[T any](result gjson.Result, checker Checker[T]) bool {
+	switch c := any(checker).(type) {
+	case describedChecker[T]:
+		return checkJSONField(result, c.checker)
+	case notChecker[T]:
+		// The negated checker may need the field itself, e.g. Not(IsString())
+		return !checkJSONField(result, c.checker)
+	case isNullChecker[T]:
 		return result.Type == gjson.Null
-	case hasLenChecker[string]:
+	case jsonTypeChecker:
+		// The string representation would lose the field's type
+		return c.checkResult(result)
+	case emptyChecker:
+		return c.checkResult(result)
+	case allOfChecker[T]:
+		// Each checker sees the field itself, e.g. AnyOf(IsNull[string](), IsNumber())
+		for _, checker := range c.checkers {
+			if !checkJSONField(result, checker) {
+				return false
+			}
+		}
+		return true
+	case anyOfChecker[T]:
+		for _, checker := range c.checkers {
+			if checkJSONField(result, checker) {
+				return true
+			}
+		}
+		return false
+	case hasLenChecker[T]:
 		// For length checks, we need the actual Go value
-		checker := m.checker.(hasLenChecker[string])
-		value := result.Value()
-		return reflect.ValueOf(value).Len() == checker.length
-	default:
+		value := reflect.ValueOf(result.Value())
+		switch value.Kind() {
+		case reflect.Slice, reflect.Map, reflect.String:
+			return value.Len() == c.length
+		default:
+			return false
+		}
+	}
+
+	if result.Type == gjson.Null {
+		return false
+	}
+
+	var value any
+	switch any(*new(T)).(type) {
+	case string:
 		// Most checkers work on the string representation
-		if result.Type == gjson.Null {
+		value = result.String()
+	case int:
+		if result.Type != gjson.Number || result.Float() != math.Trunc(result.Float()) {
+			return false
+		}
+		value = int(result.Int())
+	case int64:
+		if result.Type != gjson.Number || result.Float() != math.Trunc(result.Float()) {
 			return false
 		}
+		value = result.Int()
+	case float64:
+		if result.Type != gjson.Number {
+			return false
+		}
+		value = result.Float()
+	case bool:
+		if result.Type != gjson.True && result.Type != gjson.False {
+			return false
+		}
+		value = result.Bool()
+	default:
+		panic(fmt.Sprintf("JSON() can't check fields as %T, use a checker for a string, int, int64, float64 or bool", *new(T)))
+	}
+
+	return checker.Check(value.(T))
+}
+
+// JSONCapture creates a checker that stores the JSON field at the given path
+// in dst, e.g. Body(JSONCapture("id", &id)) to GET the created resource next.
+// It fails if the field is missing, null or not a T.
+func JSONCapture[T any](path string, dst *T) JSONFieldChecker {
+	return JSON(path, Capture(dst))
+}
+
+// JSONLen creates a checker for the number of elements of the JSON array or
+// object at the given path, e.g. JSONLen("entries", Is(3)).
+func JSONLen(path string, checker Checker[int]) JSONFieldChecker {
+	return JSONFieldChecker{
+		path:    path,
+		checker: expectation(fmt.Sprintf("length %s", checker.Expected())),
+		check: func(result gjson.Result) bool {
+			if !result.IsArray() && !result.IsObject() {
+				return false
+			}
+
+			n := 0
+			result.ForEach(func(_, _ gjson.Result) bool {
+				n++
+				return true
+			})
+
+			return checker.Check(n)
+		},
+	}
+}
+
+// JSONContains creates a checker that requires an element of the JSON array
+// at the given path to pass checker, e.g. JSONContains("members", Is("node-3")).
+// Elements are checked the way JSON checks fields.
+func JSONContains[T any](path string, checker Checker[T]) JSONFieldChecker {
+	return JSONFieldChecker{
+		path:    path,
+		checker: expectation(fmt.Sprintf("an element %s", checker.Expected())),
+		check: func(result gjson.Result) bool {
+			if !result.IsArray() {
+				return false
+			}
+
+			for _, element := range result.Array() {
+				if checkJSONField(element, checker) {
+					return true
+				}
+			}
 
-		return m.checker.Check(result.String())
+			return false
+		},
 	}
 }
 
+// expectation describes what a checker built from others expects.
+type expectation string
+
+func (e expectation) Expected() string {
+	return string(e)
+}
+
+func (m JSONFieldChecker) Check(actual string) bool {
+	return m.check(gjson.Get(actual, m.path))
+}
+
 func (m JSONFieldChecker) Expected() string {
-	return fmt.Sprintf("field %s: %s", m.path, m.checker.Expected())
+	return fmt.Sprintf("field %s: %s", m.field(), m.checker.Expected())
+}
+
+// field names the field for failure messages, with its description if any.
+func (m JSONFieldChecker) field() string {
+	if d, ok := m.checker.(described); ok {
+		return fmt.Sprintf("%s (%s)", d.description(), m.path)
+	}
+
+	return m.path
+}
+
+// YAMLFieldChecker pairs a path with a checker for that field of a YAML document.
+type YAMLFieldChecker struct {
+	field JSONFieldChecker
+}
+
+// YAML creates a checker that extracts a field of a YAML document and
+// validates it like JSON does, with the same gjson path syntax, e.g.
+// YAML("spec.replicas", Is(3)) or YAML("nodes.0.name", Is("node-1")).
+func YAML[T any](path string, checker Checker[T]) YAMLFieldChecker {
+	return YAMLFieldChecker{field: JSON(path, checker)}
+}
+
+func (m YAMLFieldChecker) Check(actual string) bool {
+	data, err := yaml.YAMLToJSON([]byte(actual))
+	if err != nil {
+		return false
+	}
+
+	return m.field.Check(string(data))
+}
+
+func (m YAMLFieldChecker) Expected() string {
+	return "YAML " + m.field.Expected()
+}
+
+// jsonEqualsChecker validates that a string is a JSON document equal to another.
+type jsonEqualsChecker struct {
+	value any
+	raw   string
+}
+
+// JSONEquals creates a checker that requires a JSON document equal to
+// expected, ignoring key order and whitespace. Numbers are equal if their
+// values are, e.g. 1 and 1.0. Failures list where the documents differ.
+func JSONEquals(expected string) jsonEqualsChecker {
+	var value any
+	err := json.Unmarshal([]byte(expected), &value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid expected JSON %q: %v", expected, err))
+	}
+
+	raw, _ := json.Marshal(value)
+	return jsonEqualsChecker{value: value, raw: string(raw)}
+}
+
+func (m jsonEqualsChecker) Check(actual string) bool {
+	var value any
+	err := json.Unmarshal([]byte(actual), &value)
+	return err == nil && reflect.DeepEqual(m.value, value)
+}
+
+func (m jsonEqualsChecker) Expected() string {
+	return fmt.Sprintf("JSON equal to %s", m.raw)
+}
+
+// maxJSONDifferences is how many differences a failing JSONEquals lists.
+const maxJSONDifferences = 10
+
+// differences lists where actual differs from the expected document.
+func (m jsonEqualsChecker) differences(actual string) []string {
+	var value any
+	err := json.Unmarshal([]byte(actual), &value)
+	if err != nil {
+		return []string{fmt.Sprintf("not valid JSON: %v", err)}
+	}
+
+	var diffs []string
+	jsonDiff("$", m.value, value, &diffs)
+	if len(diffs) > maxJSONDifferences {
+		more := len(diffs) - maxJSONDifferences
+		diffs = append(diffs[:maxJSONDifferences], fmt.Sprintf("... and %d more", more))
+	}
+
+	return diffs
+}
+
+// jsonDiff appends a line for each path where actual differs from expected.
+func jsonDiff(path string, expected, actual any, diffs *[]string) {
+	switch expected := expected.(type) {
+	case map[string]any:
+		actual, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(expected)) {
+			value, ok := actual[key]
+			if !ok {
+				*diffs = append(*diffs, fmt.Sprintf("%s: missing, expected %s", jsonPath(path, key), jsonText(expected[key])))
+				continue
+			}
+
+			jsonDiff(jsonPath(path, key), expected[key], value, diffs)
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(actual)) {
+			if _, ok := expected[key]; !ok {
+				*diffs = append(*diffs, fmt.Sprintf("%s: unexpected %s", jsonPath(path, key), jsonText(actual[key])))
+			}
+		}
+
+		return
+	case []any:
+		actual, ok := actual.([]any)
+		if !ok {
+			break
+		}
+
+		for i := range min(len(expected), len(actual)) {
+			jsonDiff(fmt.Sprintf("%s[%d]", path, i), expected[i], actual[i], diffs)
+		}
+
+		for i := len(actual); i < len(expected); i++ {
+			*diffs = append(*diffs, fmt.Sprintf("%s[%d]: missing, expected %s", path, i, jsonText(expected[i])))
+		}
+
+		for i := len(expected); i < len(actual); i++ {
+			*diffs = append(*diffs, fmt.Sprintf("%s[%d]: unexpected %s", path, i, jsonText(actual[i])))
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: expected %s, got %s", path, jsonText(expected), jsonText(actual)))
+	}
+}
+
+// plainJSONKey matches keys that can be written after a dot in a path.
+var plainJSONKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonPath appends key to a path, quoting it if it isn't a plain name.
+func jsonPath(path, key string) string {
+	if plainJSONKey.MatchString(key) {
+		return path + "." + key
+	}
+
+	return fmt.Sprintf("%s[%q]", path, key)
+}
+
+// jsonText renders a decoded JSON value for a difference, shortened if it's long.
+func jsonText(value any) string {
+	raw, _ := json.Marshal(value)
+	return truncateValue(string(raw), 60)
+}
+
+// formPartChecker checks a named part of a multipart body.
+type formPartChecker struct {
+	name    string
+	checker Checker[string]
+}
+
+// FormPart creates a checker that extracts the part with the given form
+// name from a multipart body and validates its content. The boundary is
+// taken from the body's first line, so no Content-Type is needed.
+func FormPart(name string, checker Checker[string]) formPartChecker {
+	return formPartChecker{name: name, checker: checker}
+}
+
+func (m formPartChecker) Check(actual string) bool {
+	content, ok := formPartContent(actual, m.name)
+	return ok && m.checker.Check(content)
+}
+
+func (m formPartChecker) Expected() string {
+	return fmt.Sprintf("part %s: %s", m.name, m.checker.Expected())
+}
+
+// formPartContent returns the content of the first part with the given
+// form name, or false if the body isn't multipart or has no such part.
+func formPartContent(body, name string) (string, bool) {
+	firstLine, _, _ := strings.Cut(body, "\n")
+	boundary, ok := strings.CutPrefix(strings.TrimRight(firstLine, "\r"), "--")
+	if !ok || boundary == "" {
+		return "", false
+	}
+
+	reader := multipart.NewReader(strings.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return "", false
+		}
+
+		if part.FormName() == name {
+			content, err := io.ReadAll(part)
+			return string(content), err == nil
+		}
+	}
 }
 
 // checkAll returns true if all checkers pass for the given value.
@@ -0,0 +1,96 @@
+package attest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// PeerArg is an argument StartCluster renders for each node of a cluster.
+type PeerArg struct {
+	tmpl *template.Template
+}
+
+// PeerNode is what a PeerTemplate is rendered with for each node.
+type PeerNode struct {
+	// Name is the node's name, e.g. node-1
+	Name string
+	// Index is the node's position in the cluster, starting at 1
+	Index int
+	// Peers lists every other node as <name>=<addr>, separated by commas
+	Peers string
+}
+
+// PeerTemplate is an argument passed to every node StartCluster starts,
+// a text/template rendered with the node's PeerNode, e.g. PeerTemplate("--peers={{.Peers}}")
+// or PeerTemplate("--id={{.Name}}").
+func PeerTemplate(text string) PeerArg {
+	tmpl, err := template.New("peers").Parse(text)
+	if err == nil {
+		// Catch unknown fields now rather than once the cluster starts
+		err = tmpl.Execute(io.Discard, PeerNode{})
+	}
+	if err != nil {
+		panic(fmt.Sprintf("PeerTemplate() requires a valid template: %v", err))
+	}
+
+	return PeerArg{tmpl: tmpl}
+}
+
+// StartCluster starts n processes named <prefix>-1 to <prefix>-n and returns
+// their names. Every node's ports are allocated before any of them starts,
+// so each can be told about all its peers, e.g.
+//
+//	nodes := do.StartCluster("node", 5, PeerTemplate("--peers={{.Peers}}"))
+//
+// Each argument is rendered for each node, so one without fields, e.g.
+// PeerTemplate("--cache"), is passed to every node as is. Peer addresses
+// are PeerAddress proxies, so Partition can cut nodes off from each other.
+func (do *Do) StartCluster(prefix string, n int, args ...PeerArg) []string {
+	return do.StartClusterWith(prefix, n, nil, args...)
+}
+
+// StartClusterWith starts a cluster like StartCluster, with every node
+// configured by opts as in StartWith.
+func (do *Do) StartClusterWith(prefix string, n int, opts []StartOption, args ...PeerArg) []string {
+	if n <= 0 {
+		panic(fmt.Sprintf("StartCluster() requires at least one node, got %d", n))
+	}
+
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s-%d", prefix, i+1)
+	}
+
+	procs := make([]*Process, n)
+	for i, name := range names {
+		var peers []string
+		for _, peer := range names {
+			if peer != name {
+				peers = append(peers, fmt.Sprintf("%s=%s", peer, do.PeerAddress(name, peer)))
+			}
+		}
+
+		node := PeerNode{Name: name, Index: i + 1, Peers: strings.Join(peers, ",")}
+		rendered := make([]string, len(args))
+		for j, arg := range args {
+			var b strings.Builder
+			err := arg.tmpl.Execute(&b, node)
+			if err != nil {
+				panic(fmt.Sprintf("Failed to render PeerTemplate for %s: %v", name, err))
+			}
+			rendered[j] = b.String()
+		}
+
+		procs[i] = newProcess(name, opts, rendered)
+		procs[i].allocatePorts()
+	}
+
+	for i, name := range names {
+		do.breadcrumbs.add("START %s", name)
+		do.startProcess(name, procs[i])
+	}
+
+	return names
+}
@@ -6,6 +6,10 @@ import "time"
 type Config struct {
 	// Command is the script/command used to build & run the system under test.
 	Command string
+	// Dir is the directory Command runs in, the current one if empty. A
+	// relative Command is taken relative to it, so WorkingDir should be
+	// absolute when it's set.
+	Dir string
 
 	// WorkingDir is the base directory for test runs.
 	WorkingDir string
@@ -22,8 +26,26 @@ type Config struct {
 	// RetryPollInterval for Eventually and Consistently operations.
 	RetryPollInterval time.Duration
 
-	// ExecuteTimeout for HTTP client requests.
+	// ExecuteTimeout for HTTP client requests and commands, unless a promise sets WithTimeout.
 	ExecuteTimeout time.Duration
+
+	// TimeoutMultiplier scales the timeouts above and those given to Within and WithTimeout.
+	// Zero calibrates it from how fast the machine starts processes and
+	// makes localhost round trips. LSFR_TIMEOUT_MULTIPLIER overrides it.
+	TimeoutMultiplier float64
+
+	// MaxRequestsPerSecond caps outgoing requests to avoid exhausting local ports.
+	// Zero only throttles once requests could have used up the ephemeral
+	// port range. A negative value disables throttling.
+	MaxRequestsPerSecond int
+
+	// MaxBodyLength caps how much of a body or output failures show. Longer
+	// ones keep both ends and where they differ from the expected value.
+	// A negative value shows them in full.
+	MaxBodyLength int
+	// LineWidth caps the width of the lines of failure messages. Zero leaves
+	// them to wrap.
+	LineWidth int
 }
 
 // DefaultConfig returns the default configuration.
@@ -37,5 +59,6 @@ func DefaultConfig() *Config {
 		DefaultRetryTimeout:    5 * time.Second,
 		RetryPollInterval:      100 * time.Millisecond,
 		ExecuteTimeout:         15 * time.Second,
+		MaxBodyLength:          500,
 	}
 }
@@ -0,0 +1,82 @@
+package attest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/cookiejar"
+	"slices"
+	"sync"
+)
+
+// cookieSessions holds a cookie jar per named session, shared by every
+// request made in that session.
+type cookieSessions struct {
+	mu   sync.Mutex
+	jars map[string]*cookiejar.Jar
+}
+
+// jar returns the session's cookie jar, creating it on first use.
+func (s *cookieSessions) jar(name string) *cookiejar.Jar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jars == nil {
+		s.jars = make(map[string]*cookiejar.Jar)
+	}
+
+	jar, ok := s.jars[name]
+	if !ok {
+		// Without a public suffix list, cookies are only ever sent back to the host that set them
+		jar, _ = cookiejar.New(nil)
+		s.jars[name] = jar
+	}
+
+	return jar
+}
+
+// findCookie returns the last cookie with the given name, or nil if none was set.
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	var found *http.Cookie
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			found = cookie
+		}
+	}
+
+	return found
+}
+
+// cookieMismatch describes failing checkers on the cookies set by a response,
+// comparing either their values or their Set-Cookie lines.
+func cookieMismatch(cookies []*http.Cookie, checkers map[string][]Checker[string], label string, render func(*http.Cookie) string) string {
+	var mismatches []string
+	for _, name := range slices.Sorted(maps.Keys(checkers)) {
+		var value string
+		actual := fmt.Sprintf("Actual %s %s: (not set)", name, label)
+		if cookie := findCookie(cookies, name); cookie != nil {
+			value = render(cookie)
+			actual = fmt.Sprintf("Actual %s %s: %q", name, label, value)
+		}
+
+		mismatches = append(mismatches, mismatch(value, checkers[name], fmt.Sprintf("%s %s", name, label), actual))
+	}
+
+	return joinMismatches(mismatches...)
+}
+
+// cookiesPass reports whether every cookie checker passes.
+func cookiesPass(cookies []*http.Cookie, checkers map[string][]Checker[string], render func(*http.Cookie) string) bool {
+	for name, cookieCheckers := range checkers {
+		cookie := findCookie(cookies, name)
+		if cookie == nil || !checkAll(render(cookie), cookieCheckers, nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func cookieValue(cookie *http.Cookie) string {
+	return cookie.Value
+}
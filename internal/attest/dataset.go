@@ -0,0 +1,120 @@
+package attest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// DatasetSpec describes how a challenge's processes serve the data they store,
+// so VerifyDataset can check large datasets, e.g. after every restart.
+type DatasetSpec struct {
+	// Path is where a single key's value is read from, with %s standing for
+	// the key, e.g. "/kv/%s"
+	Path string
+
+	// Digest is the path of an optional bulk endpoint. It's POSTed the keys,
+	// one per line, and responds with the hex SHA-256 of each key and its
+	// value in the order given, both followed by a NUL byte. Missing keys
+	// are left out.
+	Digest string
+}
+
+// datasetVerifier verifies datasets against a process, remembering if its
+// digest endpoint turns out not to be implemented.
+type datasetVerifier struct {
+	spec     DatasetSpec
+	noDigest atomic.Bool
+}
+
+// VerifyDataset checks that the named process holds every key in data with
+// its value. If the challenge's digest endpoint is implemented, the whole
+// dataset is checked in one request, otherwise, or if the digests differ,
+// it falls back to reading every key to find the first one that's wrong.
+// The stage must declare its DatasetSpec with Suite.Dataset.
+func (do *Do) VerifyDataset(name string, data map[string]string, help string) {
+	verifier := do.dataset
+	if verifier == nil {
+		panic("VerifyDataset requires the suite to declare a DatasetSpec with Dataset()")
+	}
+
+	keys := slices.Sorted(maps.Keys(data))
+
+	// Recordings only hold the exchanges of HTTP promises, so they read every key
+	if verifier.spec.Digest != "" && !verifier.noDigest.Load() && do.exchanges == nil {
+		digest, implemented := do.fetchDigest(name, verifier, keys)
+		if !implemented {
+			verifier.noDigest.Store(true)
+		}
+
+		if digest == datasetDigest(keys, data) {
+			do.breadcrumbs.add("VERIFY %s %d keys (digest)", name, len(keys))
+			return
+		}
+	}
+
+	do.breadcrumbs.add("VERIFY %s %d keys", name, len(keys))
+	for _, key := range keys {
+		do.HTTP(name, "GET", fmt.Sprintf(verifier.spec.Path, key)).T().
+			Status(Is(200)).
+			Body(Is(data[key])).
+			Assert(help)
+	}
+}
+
+// fetchDigest asks the process for the digest of keys. It returns "" if
+// the request fails, and reports whether the endpoint is implemented.
+func (do *Do) fetchDigest(name string, verifier *datasetVerifier, keys []string) (string, bool) {
+	proc := do.getProcess(name)
+
+	client := newHTTPClient(do.config.ExecuteTimeout, proc.socketPath, proc.tls, false)
+
+	body := strings.Join(keys, "\n")
+	req, err := http.NewRequestWithContext(do.ctx, "POST", proc.url(verifier.spec.Digest), strings.NewReader(body))
+	if err != nil {
+		return "", true
+	}
+
+	do.limiter.wait(do.ctx)
+	do.metrics.requests.Add(1)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", true
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return "", false
+	case http.StatusOK:
+	default:
+		return "", true
+	}
+
+	digest, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", true
+	}
+
+	return strings.ToLower(strings.TrimSpace(string(digest))), true
+}
+
+// datasetDigest is the digest a process holding data responds with for keys.
+func datasetDigest(keys []string, data map[string]string) string {
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write([]byte(data[key]))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,172 @@
+package attest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+)
+
+// maxDiskFill is the most free space FillDisk fills, so it never fills a
+// large disk the user depends on.
+const maxDiskFill = 256 << 20
+
+// diskFillerName is the file FillDisk takes up the free space with.
+const diskFillerName = ".lsfr-disk-filler"
+
+// FillDisk takes up the free space of the filesystem the named process
+// keeps its DataDir on, so its writes fail with ENOSPC until the end of the
+// test. Only a small filesystem can be filled, e.g. one mounted for
+// WithDiskLimit, otherwise the test is skipped before anything is written.
+func (do *Do) FillDisk(name string) {
+	do.getProcess(name)
+	do.breadcrumbs.add("FILL DISK %s", name)
+
+	dir := do.DataDir(name)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create %s: %v", dir, err))
+	}
+
+	var stat syscall.Statfs_t
+	err = syscall.Statfs(dir, &stat)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to check the free space of %s: %v", dir, err))
+	}
+
+	free := int64(stat.Bavail) * int64(stat.Bsize)
+	do.SkipIf(free > maxDiskFill, fmt.Sprintf("%s has more than %d MiB free, start %s WithDiskLimit as root to fill it",
+		dir, maxDiskFill>>20, name))
+
+	path := filepath.Join(dir, diskFillerName)
+	do.diskUndo = append(do.diskUndo, func() {
+		os.Remove(path)
+	})
+
+	full, err := fillDisk(path, maxDiskFill)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to fill the disk: %v", err))
+	}
+	if !full {
+		panic(fmt.Sprintf("Failed to fill the disk: %s still had space after %d MiB", dir, maxDiskFill>>20))
+	}
+}
+
+// mountDataDir mounts a tmpfs of size bytes at dir for WithDiskLimit,
+// unless it's mounted already, e.g. when the process restarts. Without
+// root, dir is left as is and FillDisk skips its test.
+func (do *Do) mountDataDir(dir string, size int64) {
+	do.diskMu.Lock()
+	defer do.diskMu.Unlock()
+
+	if _, tried := do.diskMounts[dir]; tried {
+		return
+	}
+
+	if do.diskMounts == nil {
+		do.diskMounts = make(map[string]bool)
+	}
+	do.diskMounts[dir] = mountDisk(dir, size) == nil
+}
+
+// unmountDataDirs unmounts the DataDirs mounted for WithDiskLimit.
+func (do *Do) unmountDataDirs() {
+	do.diskMu.Lock()
+	defer do.diskMu.Unlock()
+
+	for dir, mounted := range do.diskMounts {
+		if mounted {
+			unmountDisk(dir)
+		}
+	}
+	do.diskMounts = nil
+}
+
+// fillDisk writes zeros to path until the filesystem is full, and reports
+// whether it filled up before limit bytes were written.
+func fillDisk(path string, limit int64) (bool, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 1<<20)
+	var written int64
+	for written < limit {
+		n, err := f.Write(buf)
+		written += int64(n)
+		if errors.Is(err, syscall.ENOSPC) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Some filesystems only find out there's no space when flushing
+	err = f.Sync()
+	if errors.Is(err, syscall.ENOSPC) {
+		return true, nil
+	}
+
+	return false, err
+}
+
+// MakeDataDirReadOnly makes the named process's DataDir and everything in
+// it read-only until the end of the test, so creating, writing, renaming
+// and deleting its files fail with EACCES. Other processes' data stays
+// writable. Permissions don't apply to root, so the test is skipped when
+// running as root.
+func (do *Do) MakeDataDirReadOnly(name string) {
+	do.getProcess(name)
+	do.SkipIf(os.Geteuid() == 0, "permissions don't apply to root, run lsfr as another user")
+	do.breadcrumbs.add("READ-ONLY %s", name)
+
+	dir := do.DataDir(name)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create %s: %v", dir, err))
+	}
+
+	type change struct {
+		path string
+		mode fs.FileMode
+	}
+
+	var changes []change
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, change{path: path, mode: info.Mode().Perm()})
+		return os.Chmod(path, info.Mode().Perm()&^0222)
+	})
+
+	do.diskUndo = append(do.diskUndo, func() {
+		for _, c := range slices.Backward(changes) {
+			os.Chmod(c.path, c.mode)
+		}
+	})
+
+	if err != nil {
+		panic(fmt.Sprintf("Failed to make %s read-only: %v", dir, err))
+	}
+}
+
+// restoreDisk undoes the disk faults injected so far.
+func (do *Do) restoreDisk() {
+	for _, undo := range slices.Backward(do.diskUndo) {
+		undo()
+	}
+	do.diskUndo = nil
+}
@@ -0,0 +1,16 @@
+package attest
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// mountDisk mounts a tmpfs of size bytes at dir, which needs root.
+func mountDisk(dir string, size int64) error {
+	return syscall.Mount("tmpfs", dir, "tmpfs", 0, fmt.Sprintf("size=%d", size))
+}
+
+// unmountDisk undoes mountDisk, detaching the tmpfs if it's still in use.
+func unmountDisk(dir string) error {
+	return syscall.Unmount(dir, syscall.MNT_DETACH)
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package attest
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func mountDisk(dir string, size int64) error {
+	return fmt.Errorf("mounting a tmpfs isn't supported on %s", runtime.GOOS)
+}
+
+func unmountDisk(dir string) error {
+	return nil
+}
@@ -0,0 +1,263 @@
+package attest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DNSRCode is a DNS response code.
+type DNSRCode int
+
+const (
+	DNSNoError DNSRCode = iota
+	DNSFormErr
+	DNSServFail
+	DNSNXDomain
+	DNSNotImp
+	DNSRefused
+)
+
+var dnsRCodeNames = []string{"NOERROR", "FORMERR", "SERVFAIL", "NXDOMAIN", "NOTIMP", "REFUSED"}
+
+func (c DNSRCode) String() string {
+	if c >= 0 && int(c) < len(dnsRCodeNames) {
+		return dnsRCodeNames[c]
+	}
+
+	return fmt.Sprintf("RCODE(%d)", int(c))
+}
+
+// dnsTypes maps the supported record type names to their codes.
+var dnsTypes = map[string]uint16{
+	"A":     1,
+	"NS":    2,
+	"CNAME": 5,
+	"SOA":   6,
+	"PTR":   12,
+	"MX":    15,
+	"TXT":   16,
+	"AAAA":  28,
+	"SRV":   33,
+}
+
+// dnsRecord is a single resource record from the answer section.
+type dnsRecord struct {
+	name  string
+	rtype uint16
+	ttl   uint32
+	// value is the record data in zone file presentation format
+	value string
+}
+
+// dnsMessage is a decoded DNS response.
+type dnsMessage struct {
+	id        uint16
+	response  bool
+	truncated bool
+	rcode     DNSRCode
+	answers   []dnsRecord
+}
+
+// encodeDNSQuery encodes a recursive query for a single question.
+func encodeDNSQuery(id, qtype uint16, qname string) ([]byte, error) {
+	buf := binary.BigEndian.AppendUint16(nil, id)
+	buf = binary.BigEndian.AppendUint16(buf, 0x0100) // RD
+	buf = binary.BigEndian.AppendUint16(buf, 1)      // QDCOUNT
+	buf = append(buf, 0, 0, 0, 0, 0, 0)              // ANCOUNT, NSCOUNT, ARCOUNT
+
+	for label := range strings.SplitSeq(strings.TrimSuffix(qname, "."), ".") {
+		if label == "" || len(label) > 63 {
+			return nil, fmt.Errorf("invalid domain name %q", qname)
+		}
+
+		buf = append(buf, byte(len(label)))
+		buf = append(buf, label...)
+	}
+	buf = append(buf, 0)
+
+	buf = binary.BigEndian.AppendUint16(buf, qtype)
+	buf = binary.BigEndian.AppendUint16(buf, 1) // IN
+
+	return buf, nil
+}
+
+// parseDNSMessage decodes the header and answer section of a response.
+func parseDNSMessage(msg []byte) (dnsMessage, error) {
+	if len(msg) < 12 {
+		return dnsMessage{}, fmt.Errorf("message is %d bytes, shorter than a header", len(msg))
+	}
+
+	flags := binary.BigEndian.Uint16(msg[2:4])
+	result := dnsMessage{
+		id:        binary.BigEndian.Uint16(msg[0:2]),
+		response:  flags&0x8000 != 0,
+		truncated: flags&0x0200 != 0,
+		rcode:     DNSRCode(flags & 0x000f),
+	}
+
+	questions := int(binary.BigEndian.Uint16(msg[4:6]))
+	answers := int(binary.BigEndian.Uint16(msg[6:8]))
+
+	offset := 12
+	for range questions {
+		_, next, err := readDNSName(msg, offset)
+		if err != nil {
+			return dnsMessage{}, err
+		}
+
+		offset = next + 4
+		if offset > len(msg) {
+			return dnsMessage{}, fmt.Errorf("question section is truncated")
+		}
+	}
+
+	for range answers {
+		name, next, err := readDNSName(msg, offset)
+		if err != nil {
+			return dnsMessage{}, err
+		}
+
+		if next+10 > len(msg) {
+			return dnsMessage{}, fmt.Errorf("answer section is truncated")
+		}
+
+		record := dnsRecord{
+			name:  name,
+			rtype: binary.BigEndian.Uint16(msg[next : next+2]),
+			ttl:   binary.BigEndian.Uint32(msg[next+4 : next+8]),
+		}
+
+		length := int(binary.BigEndian.Uint16(msg[next+8 : next+10]))
+		start := next + 10
+		if start+length > len(msg) {
+			return dnsMessage{}, fmt.Errorf("record data for %s is truncated", name)
+		}
+
+		record.value, err = formatRData(msg, record.rtype, start, length)
+		if err != nil {
+			return dnsMessage{}, err
+		}
+
+		result.answers = append(result.answers, record)
+		offset = start + length
+	}
+
+	return result, nil
+}
+
+// readDNSName reads a possibly compressed domain name at offset, returning
+// it fully qualified along with the offset just past it.
+func readDNSName(msg []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+
+	// Each jump must go backwards, which rules out pointer loops
+	limit := offset
+	for {
+		if offset >= len(msg) {
+			return "", 0, fmt.Errorf("domain name is truncated")
+		}
+
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+
+			return strings.Join(labels, ".") + ".", next, nil
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(msg) {
+				return "", 0, fmt.Errorf("compression pointer is truncated")
+			}
+
+			pointer := int(binary.BigEndian.Uint16(msg[offset:offset+2]) & 0x3fff)
+			if pointer >= limit {
+				return "", 0, fmt.Errorf("compression pointer %d doesn't point backwards", pointer)
+			}
+
+			if next < 0 {
+				next = offset + 2
+			}
+			offset, limit = pointer, pointer
+		default:
+			if offset+1+length > len(msg) {
+				return "", 0, fmt.Errorf("domain name label is truncated")
+			}
+
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
+
+// formatRData renders record data the way it appears in a zone file.
+func formatRData(msg []byte, rtype uint16, start, length int) (string, error) {
+	data := msg[start : start+length]
+
+	switch rtype {
+	case dnsTypes["A"], dnsTypes["AAAA"]:
+		if len(data) != net.IPv4len && len(data) != net.IPv6len {
+			return "", fmt.Errorf("address record has %d bytes", len(data))
+		}
+
+		return net.IP(data).String(), nil
+	case dnsTypes["NS"], dnsTypes["CNAME"], dnsTypes["PTR"]:
+		name, _, err := readDNSName(msg, start)
+		return name, err
+	case dnsTypes["MX"]:
+		if len(data) < 3 {
+			return "", fmt.Errorf("MX record is truncated")
+		}
+
+		name, _, err := readDNSName(msg, start+2)
+		return fmt.Sprintf("%d %s", binary.BigEndian.Uint16(data), name), err
+	case dnsTypes["SRV"]:
+		if len(data) < 7 {
+			return "", fmt.Errorf("SRV record is truncated")
+		}
+
+		name, _, err := readDNSName(msg, start+6)
+		return fmt.Sprintf("%d %d %d %s", binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(data[2:]),
+			binary.BigEndian.Uint16(data[4:]), name), err
+	case dnsTypes["TXT"]:
+		var parts []string
+		for i := 0; i < len(data); {
+			n := int(data[i])
+			if i+1+n > len(data) {
+				return "", fmt.Errorf("TXT record is truncated")
+			}
+
+			parts = append(parts, strconv.Quote(string(data[i+1:i+1+n])))
+			i += 1 + n
+		}
+
+		return strings.Join(parts, " "), nil
+	case dnsTypes["SOA"]:
+		mname, next, err := readDNSName(msg, start)
+		if err != nil {
+			return "", err
+		}
+
+		rname, next, err := readDNSName(msg, next)
+		if err != nil {
+			return "", err
+		}
+
+		if next+20 > start+length {
+			return "", fmt.Errorf("SOA record is truncated")
+		}
+
+		var fields []string
+		for i := range 5 {
+			fields = append(fields, strconv.FormatUint(uint64(binary.BigEndian.Uint32(msg[next+4*i:])), 10))
+		}
+
+		return fmt.Sprintf("%s %s %s", mname, rname, strings.Join(fields, " ")), nil
+	default:
+		return fmt.Sprintf("\\# %d %x", len(data), data), nil
+	}
+}
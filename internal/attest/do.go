@@ -1,14 +1,26 @@
 package attest
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -17,34 +29,126 @@ import (
 
 // Do provides the test harness and acts as the test runner.
 type Do struct {
+	*runState
+
+	// ctx is the context operations run under, which Concurrently narrows
+	// to its group's
+	ctx context.Context
+	// concurrent is set for the copies Concurrently passes to its functions
+	concurrent bool
+}
+
+// runState is the state of a run, shared by a Do and its group-scoped copies.
+type runState struct {
 	processes  *threadsafe.Map[string, *Process]
 	config     *Config
 	workingDir string
+	limiter    *rateLimiter
+	jitter     *jitter
+	trace      *traceLog
+	watchdog   *watchdog
+
+	// Programs running under a pseudo-terminal
+	interactive *threadsafe.Map[string, *InteractiveSession]
+	// Commands started by OutputEventually, killed at the end of the test
+	streams  []*execStream
+	streamMu sync.Mutex
+
+	// Last few operations, shown alongside failures
+	breadcrumbs *breadcrumbs
+	// Latest HTTP exchange of the current test, kept for failures
+	lastHTTP *lastExchange
+	// Counters of the work done, for the summary
+	metrics *metrics
+	// Running processes, for `lsfr ps`
+	state *processState
+
+	// Extra arguments appended to every started process
+	processArgs []string
+	// Whether the user starts and stops processes themselves, e.g. under a debugger
+	manualStart bool
+	// portPassing is how processes are told their ports
+	portPassing PortPassing
+	// Creates handlers that serve processes in-process, if set
+	server Server
+
+	// Cookie jars of named HTTP sessions
+	sessions *cookieSessions
+
+	// HTTP exchanges being recorded or replayed, if any
+	exchanges *exchanges
+	// Model HTTP operations are mirrored to, if any
+	oracle *shadowOracle
+	// How to read back stored data for VerifyDataset, if declared
+	dataset *datasetVerifier
 
-	ctx    context.Context
+	// Descriptors of callable gRPC methods, keyed by path
+	grpcMethods map[string]GRPCMethod
+	// grpcTransports are shared by gRPC calls, and closed once the run is done
+	grpcTransports *grpcTransports
+
+	// Values of the matrix dimensions the suite is running under, if any
+	variant map[string]any
+
+	// Proxies carrying traffic between processes, which Partition cuts
+	network *network
+	// Undoes disk faults injected during the current test
+	diskUndo []func()
+	// DataDirs WithDiskLimit tried to mount, and whether it did, unmounted once the run is done
+	diskMu     sync.Mutex
+	diskMounts map[string]bool
+	// Faults injected at random during the current test, if any
+	chaos *ChaosRun
+
+	// Log offsets at the start of the current test and assertions to run at its end
+	logMarks *threadsafe.Map[string, int64]
+	deferred []func()
+	deferMu  sync.Mutex
+
+	// runCtx lives as long as the run, and so do the processes started under it
+	runCtx context.Context
 	cancel context.CancelFunc
 }
 
-// newDo creates a new Do instance with custom configuration.
-func newDo(ctx context.Context, config *Config) *Do {
+// newDo creates a new Do instance with custom configuration. A non-empty
+// suffix is appended to the name of the run's working directory.
+func newDo(ctx context.Context, config *Config, suffix string) *Do {
 	doCtx, cancel := context.WithCancel(ctx)
 
 	// Build working directory path with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	workingDir := filepath.Join(config.WorkingDir, fmt.Sprintf("run-%s", timestamp))
+	runName := "run-" + time.Now().Format("20060102-150405")
+	if suffix != "" {
+		runName += "-" + suffix
+	}
+	workingDir := filepath.Join(config.WorkingDir, runName)
 
 	err := os.MkdirAll(workingDir, 0755)
 	if err != nil {
 		panic(fmt.Sprintf("failed to create working directory: %v", err))
 	}
 
-	return &Do{
-		processes:  threadsafe.NewMap[string, *Process](),
-		config:     config,
-		workingDir: workingDir,
-		ctx:        doCtx,
-		cancel:     cancel,
-	}
+	processes := threadsafe.NewMap[string, *Process]()
+
+	return &Do{runState: &runState{
+		processes:      processes,
+		interactive:    threadsafe.NewMap[string, *InteractiveSession](),
+		config:         config,
+		workingDir:     workingDir,
+		logMarks:       threadsafe.NewMap[string, int64](),
+		limiter:        newRateLimiter(config.MaxRequestsPerSecond),
+		trace:          newTraceLog(filepath.Join(workingDir, "trace.log")),
+		breadcrumbs:    &breadcrumbs{},
+		lastHTTP:       &lastExchange{},
+		metrics:        &metrics{},
+		state:          &processState{path: filepath.Join(config.WorkingDir, processStateFile)},
+		sessions:       &cookieSessions{},
+		grpcMethods:    make(map[string]GRPCMethod),
+		grpcTransports: &grpcTransports{},
+		watchdog:       &watchdog{},
+		network:        newNetwork(processes.Get),
+		runCtx:         doCtx,
+		cancel:         cancel,
+	}, ctx: doCtx}
 }
 
 // Process represents a running process.
@@ -53,8 +157,175 @@ type Process struct {
 	args    []string
 	logFile *os.File
 
-	realPort int
-	fauxPort int
+	// exited is closed once the process has exited, with exitErr holding the result of Wait.
+	// stopping is set when the harness itself terminates the process.
+	exited   chan struct{}
+	exitErr  error
+	stopping atomic.Bool
+	// paused is set while the process is stopped with SIGSTOP
+	paused atomic.Bool
+
+	// manual is set when the user manages the process's lifecycle
+	manual bool
+	// server serves the process in-process instead of cmd, if it's set
+	server *http.Server
+
+	realPort   int
+	fauxPort   int
+	socketPath string
+	// host is the address of an attached process, which may not be local
+	host string
+	// udp is set for processes that listen on a UDP port
+	udp bool
+	// tls is set for processes that serve HTTPS
+	tls bool
+	// limits caps the resources the process can use
+	limits Limit
+	// ready tells when the process is ready, once it accepts connections by default
+	ready Readiness
+	// ports are the extra TCP ports the process listens on, by name
+	ports map[string]int
+	// logStart is where this run of the process starts in its log
+	logStart int64
+}
+
+// network returns the network the process listens on.
+func (p *Process) network() string {
+	if p.socketPath != "" {
+		return "unix"
+	}
+
+	if p.udp {
+		return "udp"
+	}
+
+	return "tcp"
+}
+
+// address returns the address the process listens on.
+func (p *Process) address() string {
+	if p.socketPath != "" {
+		return p.socketPath
+	}
+
+	if p.host != "" {
+		return net.JoinHostPort(p.host, strconv.Itoa(p.realPort))
+	}
+
+	return fmt.Sprintf("127.0.0.1:%d", p.realPort)
+}
+
+// accepting reports whether the process currently accepts connections.
+// UDP is connectionless, so a UDP process counts as accepting once its port is bound.
+func (p *Process) accepting() bool {
+	if p.udp {
+		return udpBound(p.address())
+	}
+
+	conn, err := net.DialTimeout(p.network(), p.address(), 100*time.Millisecond)
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+	return true
+}
+
+// udpBound reports whether a socket is bound to addr's UDP port, from the
+// kernel's socket table where there is one. Elsewhere, it sends an empty
+// datagram, which is refused if nothing is bound and otherwise assumed to
+// have arrived. Binding the port to check would race the process for it.
+func udpBound(addr string) bool {
+	_, portText, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	port, err := strconv.ParseUint(portText, 10, 16)
+	if err != nil {
+		return false
+	}
+
+	found := false
+	for _, table := range []string{"/proc/net/udp", "/proc/net/udp6"} {
+		data, err := os.ReadFile(table)
+		if err != nil {
+			continue
+		}
+
+		found = true
+		suffix := fmt.Sprintf(":%04X", port)
+		for line := range strings.Lines(string(data)) {
+			fields := strings.Fields(line)
+			if len(fields) > 1 && strings.HasSuffix(fields[1], suffix) {
+				return true
+			}
+		}
+	}
+	if found {
+		return false
+	}
+
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	_, err = conn.Write(nil)
+	if err != nil {
+		return !errors.Is(err, syscall.ECONNREFUSED)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, err = conn.Read(make([]byte, 1))
+	return !errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// url returns the HTTP URL of path on the process.
+func (p *Process) url(path string) string {
+	scheme := "http"
+	if p.tls {
+		scheme = "https"
+	}
+
+	if p.socketPath != "" {
+		return fmt.Sprintf("%s://localhost%s", scheme, path)
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, p.address(), path)
+}
+
+// newHTTPClient creates an HTTP client that dials socketPath instead of TCP if set.
+// Server certificates aren't verified so tests can inspect them instead.
+// If forceHTTP2 is set, only HTTP/2 is spoken, with prior knowledge (h2c) over plaintext.
+func newHTTPClient(timeout time.Duration, socketPath string, useTLS, forceHTTP2 bool) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if socketPath == "" && !useTLS && !forceHTTP2 {
+		return client
+	}
+
+	transport := &http.Transport{DisableKeepAlives: true}
+	if socketPath != "" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+	}
+
+	if useTLS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		transport.ForceAttemptHTTP2 = true
+	}
+
+	if forceHTTP2 {
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP2(useTLS)
+		transport.Protocols.SetUnencryptedHTTP2(!useTLS)
+	}
+
+	client.Transport = transport
+	return client
 }
 
 // getProcess retrieves a process by name or panics if not found.
@@ -68,255 +339,1546 @@ func (do *Do) getProcess(name string) *Process {
 
 // Start starts the process with an OS-assigned port.
 func (do *Do) Start(name string, args ...string) {
-	do.startWithPort(name, 0, args...)
+	do.StartWith(name, nil, args...)
 }
 
-// startWithPort starts the process on the specified port.
-func (do *Do) startWithPort(name string, port int, args ...string) {
-	select {
-	case <-do.ctx.Done():
+// StartOption configures how StartWith runs a process: a Limit on its
+// resources, when it's Readiness or an ExtraPort.
+type StartOption interface {
+	apply(name string, proc *Process)
+}
+
+// StartWith starts the process with an OS-assigned port, configured by
+// opts, e.g. do.StartWith("node", []StartOption{WithMemoryLimit(256<<20)}).
+func (do *Do) StartWith(name string, opts []StartOption, args ...string) {
+	proc := newProcess(name, opts, args)
+	do.breadcrumbs.add("START %s", name)
+	do.startWithPort(name, 0, proc)
+}
+
+// newProcess returns a process configured with StartWith's options and arguments.
+func newProcess(name string, opts []StartOption, args []string) *Process {
+	proc := &Process{args: slices.Clone(args)}
+	for _, opt := range opts {
+		opt.apply(name, proc)
+	}
+
+	return proc
+}
+
+// Attach registers a process the harness doesn't manage, e.g. one the user runs
+// themselves or one in a container. addr is either host:port or unix:<path>.
+// The harness waits for it to accept connections but never stops or restarts it.
+func (do *Do) Attach(name, addr string) {
+	if do.replaying() {
+		do.processes.Set(name, &Process{})
 		return
-	default:
 	}
 
-	// Get OS-assigned port
-	if port == 0 {
-		listener, err := net.Listen("tcp", ":0")
+	proc := &Process{}
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		proc.socketPath = path
+	} else {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			panic(fmt.Sprintf("invalid address %q for %s: %v", addr, name, err))
+		}
+
+		proc.host = host
+		proc.realPort, err = strconv.Atoi(port)
 		if err != nil {
-			panic(fmt.Sprintf("Failed to get OS-assigned port: %v", err))
+			panic(fmt.Sprintf("invalid port in address %q for %s: %v", addr, name, err))
 		}
-		port = listener.Addr().(*net.TCPAddr).Port
-		listener.Close()
 	}
 
-	// Start the process
-	portArg := fmt.Sprintf("--port=%d", port)
-	workingDirArg := fmt.Sprintf("--working-dir=%s", do.workingDir)
-	newArgs := append([]string{portArg, workingDirArg}, args...)
+	succeeded := eventually(do.ctx, proc.accepting, do.config.ProcessStartTimeout, do.config.RetryPollInterval)
+	if !succeeded && do.ctx.Err() == nil {
+		panic(fmt.Sprintf("Could not connect to %s at %s.\n\n"+
+			"Ensure the process is running and listening on that address.", name, addr))
+	}
 
-	cmd := exec.CommandContext(do.ctx, do.config.Command, newArgs...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	do.processes.Set(name, proc)
+}
 
-	// Redirect stdout/stderr to log file
-	logPath := filepath.Join(do.workingDir, fmt.Sprintf("%s.log", name))
-	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// StartSocket starts the process listening on a Unix domain socket
+// inside the run's working directory instead of a TCP port.
+func (do *Do) StartSocket(name string, args ...string) {
+	do.breadcrumbs.add("START %s", name)
+	socketPath := filepath.Join(do.workingDir, fmt.Sprintf("%s.sock", name))
+	do.startProcess(name, &Process{socketPath: socketPath, args: args})
+}
+
+// StartUDP starts the process with an OS-assigned UDP port.
+func (do *Do) StartUDP(name string, args ...string) {
+	do.breadcrumbs.add("START %s", name)
+	conn, err := net.ListenPacket("udp", ":0")
 	if err != nil {
-		panic(fmt.Sprintf("failed to create log file: %v", err))
+		panic(fmt.Sprintf("Failed to get OS-assigned port: %v", err))
 	}
-	cmd.Stdout = logFile
-	cmd.Stderr = logFile
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	conn.Close()
 
-	err = cmd.Start()
+	do.startProcess(name, &Process{realPort: port, udp: true, args: args})
+}
+
+// StartTLS starts the process with an OS-assigned port, serving HTTPS.
+// A self-signed certificate for localhost is generated in the run's working
+// directory and passed as --tls-cert=<path> and --tls-key=<path>.
+func (do *Do) StartTLS(name string, args ...string) {
+	do.breadcrumbs.add("START %s", name)
+	certPath, keyPath, err := generateCertificate(do.workingDir, name)
 	if err != nil {
-		logFile.Close()
-		panic(err.Error())
+		panic(fmt.Sprintf("Failed to generate certificate: %v", err))
 	}
 
-	proc := &Process{realPort: port, cmd: cmd, args: args, logFile: logFile}
-	do.waitForPort(proc)
+	args = append([]string{"--tls-cert=" + certPath, "--tls-key=" + keyPath}, args...)
+	do.startProcess(name, &Process{realPort: freePort(), tls: true, args: args})
+}
 
-	do.processes.Set(name, proc)
+// startWithPort starts the process on the specified port, and its extra
+// ports on OS-assigned ones.
+func (do *Do) startWithPort(name string, port int, proc *Process) {
+	proc.realPort = port
+	proc.allocatePorts()
+	do.startProcess(name, proc)
 }
 
-// waitForPort waits for a process to accept connections on its port.
-func (do *Do) waitForPort(proc *Process) {
-	host := fmt.Sprintf("127.0.0.1:%d", proc.realPort)
+// allocatePorts assigns OS-assigned ports to the process's main port, unless
+// it already has one or picks its own, and its extra ports.
+func (p *Process) allocatePorts() {
+	if p.realPort == 0 && p.ready.portPattern == nil {
+		p.realPort = freePort()
+	}
+	for portName := range p.ports {
+		p.ports[portName] = freePort()
+	}
+}
 
-	succeeded := eventually(do.ctx, func() bool {
-		conn, err := net.DialTimeout("tcp", host, 100*time.Millisecond)
-		if err != nil {
-			return false
-		}
+// freePort returns a TCP port the OS considers free.
+func freePort() int {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		panic(fmt.Sprintf("Failed to get OS-assigned port: %v", err))
+	}
+	defer listener.Close()
 
-		conn.Close()
-		return true
-	}, do.config.ProcessStartTimeout, do.config.RetryPollInterval)
+	return listener.Addr().(*net.TCPAddr).Port
+}
 
-	if !succeeded {
-		select {
-		case <-do.ctx.Done():
-			return
-		default:
-			log.Fatalf(
-				"\nCould not connect to http://%s.\n\n"+
-					"Possible issues:\n"+
-					"- run.sh script not executable (run: chmod +x run.sh)\n"+
-					"- Process not starting on port %d\n"+
-					"- Process crashing during startup\n\n"+
-					"Debug with: ./run.sh and check for error messages", host, proc.realPort,
-			)
-		}
+// ExtraPort is another port a process started with StartWith listens on.
+type ExtraPort string
+
+func (p ExtraPort) apply(name string, proc *Process) {
+	if proc.ports == nil {
+		proc.ports = make(map[string]int)
 	}
+	if _, exists := proc.ports[string(p)]; exists {
+		panic(fmt.Sprintf("%s has two ports named %q", name, p))
+	}
+	proc.ports[string(p)] = 0
 }
 
-// Stop sends SIGTERM to the process, then SIGKILL after timeout.
-func (do *Do) Stop(name string) {
-	proc := do.getProcess(name)
-	if proc.cmd == nil || proc.cmd.Process == nil {
-		return
+// WithPort gives the process another OS-assigned port, passed to it as
+// --<name>-port=<port>, or as its PortPassing convention has it, e.g.
+// WithPort("peer") for a gossip port passed as --peer-port. Requests go to
+// it with HTTPPromise.OnPort.
+func WithPort(name string) ExtraPort {
+	if !portName.MatchString(name) {
+		panic(fmt.Sprintf("WithPort() requires a lowercase name like \"peer\", got %q", name))
 	}
 
-	pgid := proc.cmd.Process.Pid
-	err := syscall.Kill(-pgid, syscall.SIGTERM)
-	if err != nil {
-		fmt.Println(red("Error stopping process running @"), red(proc.realPort))
-		return
-	}
+	return ExtraPort(name)
+}
 
-	// Wait for graceful exit, force kill if timeout
-	done := make(chan bool, 1)
-	go func() {
-		proc.cmd.Wait()
-		done <- true
-	}()
+// portName matches names that make readable flags.
+var portName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
 
-	select {
-	case <-done:
-		// Process exited gracefully
-	case <-time.After(do.config.ProcessShutdownTimeout):
-		do.Kill(name)
-		<-done
+// Port returns the named extra port of a process started with WithPort.
+func (do *Do) Port(name, port string) int {
+	n, ok := do.getProcess(name).ports[port]
+	if !ok {
+		panic(fmt.Sprintf("%s wasn't started with WithPort(%q)", name, port))
 	}
 
-	// Close log file after process exits
-	if proc.logFile != nil {
-		proc.logFile.Close()
-		proc.logFile = nil
-	}
+	return n
 }
 
-// Kill sends SIGKILL to kill the process immediately.
-func (do *Do) Kill(name string) {
-	proc := do.getProcess(name)
-	if proc.cmd == nil || proc.cmd.Process == nil {
+// startProcess starts the process on its port or socket and waits until it accepts connections.
+func (do *Do) startProcess(name string, proc *Process) {
+	select {
+	case <-do.ctx.Done():
 		return
+	default:
 	}
 
-	pgid := proc.cmd.Process.Pid
-	err := syscall.Kill(-pgid, syscall.SIGKILL)
-	if err != nil {
-		fmt.Println(red("Error killing process running @"), red(proc.realPort))
+	// Nothing runs during a dry run, responses come from the recording
+	if do.replaying() {
+		do.processes.Set(name, proc)
+		return
 	}
 
-	// Close log file if not already closed (e.g., when called directly, not via Stop)
-	if proc.logFile != nil {
-		proc.logFile.Close()
-		proc.logFile = nil
+	// Remove stale socket left behind by a killed process
+	if proc.socketPath != "" {
+		os.Remove(proc.socketPath)
 	}
-}
+	// The process picks its port again each time it starts
+	if proc.ready.portPattern != nil {
+		proc.realPort = 0
+	}
+	// Each process keeps its data apart, and finds it again after a restart
+	dataDir := do.DataDir(name)
+	err := os.MkdirAll(dataDir, 0755)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create data directory: %v", err))
+	}
+	if proc.limits.disk > 0 {
+		do.mountDataDir(dataDir, proc.limits.disk)
+	}
+	newArgs, env, err := do.portPassing.pass(proc, dataDir)
+	if err != nil {
+		panic(fmt.Sprintf("failed to pass ports to %s: %v", name, err))
+	}
+	newArgs = append(newArgs, proc.args...)
+	newArgs = append(newArgs, do.processArgs...)
 
-// Restart stops the process and starts it again.
-func (do *Do) Restart(name string, sig ...syscall.Signal) {
-	proc := do.getProcess(name)
-	if proc.cmd == nil {
+	if do.manualStart {
+		do.startManually(name, proc, newArgs, env)
 		return
 	}
 
-	signal := syscall.SIGTERM
-	if len(sig) > 0 {
-		signal = sig[0]
+	if do.server != nil {
+		do.startInProcess(name, proc, newArgs)
+		return
 	}
 
-	switch signal {
-	case syscall.SIGTERM:
-		do.Stop(name)
-	case syscall.SIGKILL:
-		do.Kill(name)
-	default:
-		do.Stop(name)
+	err = do.launch(name, proc, newArgs, env)
+	if err != nil {
+		panic(err.Error())
 	}
 
-	time.Sleep(do.config.ProcessRestartDelay)
+	if proc.ready.portPattern != nil {
+		do.waitForAnnouncedPort(name, proc)
+	}
+	if proc.ready.logPattern != nil {
+		do.waitForLog(name, proc, proc.ready.logPattern)
+	}
+	if proc.ready.logPattern == nil || proc.ready.portPattern != nil {
+		do.waitForPort(proc)
+	}
+	if proc.ready.healthPath != "" {
+		do.waitForHealthy(name, proc)
+	}
 
-	do.startWithPort(name, proc.realPort, proc.args...)
+	do.processes.Set(name, proc)
 }
 
-// Done cleans up all running processes.
-func (do *Do) Done() {
-	do.cancel()
+// launch runs the command for the process with args and env, logging its
+// output, without waiting for it to be ready.
+func (do *Do) launch(name string, proc *Process, args, env []string) error {
+	cmd := exec.CommandContext(do.runCtx, do.config.Command, args...)
+	if proc.limits.set() {
+		cmd = proc.limits.command(do.runCtx, do.config.Command, args...)
+	}
+	cmd.Dir = do.config.Dir
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
-	var processNames []string
-	do.processes.Range(func(name string, _ *Process) bool {
-		processNames = append(processNames, name)
-		return true
-	})
+	// Redirect stdout/stderr to log file
+	logFile, err := os.OpenFile(do.logPath(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create log file: %v", err)
+	}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if info, err := logFile.Stat(); err == nil {
+		proc.logStart = info.Size()
+	}
 
-	for _, name := range processNames {
-		do.Stop(name)
+	err = cmd.Start()
+	if err != nil {
+		logFile.Close()
+		return err
 	}
+	do.metrics.processes.Add(1)
+	do.state.add(ProcessRecord{
+		Name:    name,
+		PID:     cmd.Process.Pid,
+		Port:    proc.realPort,
+		Socket:  proc.socketPath,
+		Started: time.Now().UTC().Truncate(time.Second),
+		RunDir:  do.workingDir,
+	})
+
+	proc.cmd = cmd
+	proc.logFile = logFile
+	proc.exited = make(chan struct{})
+	go do.waitForExit(name, proc)
+
+	return nil
 }
 
-// Concurrently runs multiple functions in parallel and waits for completion.
-func (do *Do) Concurrently(fns ...func()) {
-	var wg sync.WaitGroup
-	var panicErr any
-	var panicMu sync.Mutex
+// CheckStart starts config.Command once the way tests start a process and
+// waits until it accepts connections on the port it's given, e.g. to check
+// a new run.sh before running any stage. The error says why it didn't,
+// with the process's output.
+func CheckStart(ctx context.Context, config *Config) error {
+	config = New().WithConfig(config).config
+	do := newDo(ctx, config, "start")
+	defer do.Done()
 
-	for _, fn := range fns {
-		wg.Add(1)
-		go func(f func()) {
-			defer wg.Done()
-			defer func() {
-				err := recover()
-				if err != nil {
-					panicMu.Lock()
-					if panicErr == nil {
-						panicErr = err
-					}
-					panicMu.Unlock()
-				}
-			}()
+	name := "server"
+	proc := newProcess(name, nil, nil)
+	proc.allocatePorts()
 
-			f()
-		}(fn)
+	dataDir := do.DataDir(name)
+	err := os.MkdirAll(dataDir, 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create data directory: %w", err)
 	}
 
-	wg.Wait()
+	args, env, err := do.portPassing.pass(proc, dataDir)
+	if err != nil {
+		return fmt.Errorf("Failed to pass ports: %w", err)
+	}
 
-	if panicErr != nil {
-		panic(panicErr)
+	err = do.launch(name, proc, args, env)
+	if err != nil {
+		return fmt.Errorf("Failed to start %s: %w\nEnsure it's executable (run: chmod +x %s).", config.Command, err, config.Command)
 	}
-}
+	// Done stops it, and it shouldn't be reported as crashing
+	proc.stopping.Store(true)
+	do.processes.Set(name, proc)
 
-// HTTP creates a deferred HTTP request.
-func (do *Do) HTTP(name, method, path string, args ...any) *HTTPPromise {
-	proc := do.getProcess(name)
-	url := fmt.Sprintf("http://127.0.0.1:%d%s", proc.realPort, path)
+	var exited bool
+	ready := eventually(do.ctx, func() bool {
+		select {
+		case <-proc.exited:
+			exited = true
+			return true
+		default:
+			return proc.accepting()
+		}
+	}, config.ProcessStartTimeout, config.RetryPollInterval)
 
-	var body []byte
-	if len(args) >= 1 {
-		body = []byte(args[0].(string))
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case exited:
+		return fmt.Errorf("%s exited %s before accepting connections on port %d.\n\n  Output:\n%s",
+			config.Command, describeExit(proc.cmd.ProcessState), proc.realPort, indentOutput(do.tailLog(name, crashLogLines)))
+	case !ready:
+		return fmt.Errorf("%s didn't accept connections on port %d within %s.\n\n  Output:\n%s",
+			config.Command, proc.realPort, config.ProcessStartTimeout, indentOutput(do.tailLog(name, crashLogLines)))
 	}
 
-	var headers H
-	if len(args) >= 2 {
-		headers = args[1].(H)
+	return nil
+}
+
+// indentOutput indents a process's output for display under a heading.
+func indentOutput(output string) string {
+	if output == "" {
+		return "    (none)"
 	}
 
-	return &HTTPPromise{
-		PromiseBase: PromiseBase{
-			timing: TimingImmediate,
-			ctx:    do.ctx,
-			config: do.config,
-		},
+	return indent(output, "    ")
+}
 
-		method:  method,
-		url:     url,
-		headers: headers,
-		body:    body,
-	}
+// DataDir returns the directory the named process keeps its data in, which
+// it's passed as --working-dir, e.g. to check the files it writes. Every
+// process gets its own, kept across restarts.
+func (do *Do) DataDir(name string) string {
+	return filepath.Join(do.workingDir, name)
 }
 
-// Exec creates a deferred CLI command execution.
-func (do *Do) Exec(args ...string) *CLIPromise {
-	return &CLIPromise{
-		PromiseBase: PromiseBase{
-			timing: TimingImmediate,
-			ctx:    do.ctx,
-			config: do.config,
-		},
+// manualTimeout bounds how long the harness waits for the user to start or stop a process.
+const manualTimeout = time.Hour
 
-		command: do.config.Command,
-		args:    args,
+// replaying reports whether HTTP responses are replayed from a recording.
+func (do *Do) replaying() bool {
+	return do.exchanges != nil && do.exchanges.replaying
+}
+
+// startManually asks the user to start the process, e.g. under a debugger,
+// and waits until it accepts connections.
+func (do *Do) startManually(name string, proc *Process, args, env []string) {
+	proc.manual = true
+
+	command := strings.Join(append(slices.Clone(env), do.config.Command), " ")
+	fmt.Printf("%s Start %s yourself, e.g. under a debugger:\n\n  %s %s\n\n",
+		yellow(Marks.Arrow), name, command, strings.Join(args, " "))
+	// The harness can't see the log of a process it didn't start
+	if proc.ready.portPattern != nil {
+		fmt.Printf("Type the port %s announced, then press Enter: ", name)
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		port, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil || port <= 0 || port > 65535 {
+			panic(fmt.Sprintf("%q isn't a valid port for %s", strings.TrimSpace(line), name))
+		}
+		proc.realPort = port
+		fmt.Println()
+	}
+	if proc.ready.logPattern != nil {
+		fmt.Printf("Press Enter once %s is ready...\n\n", name)
+		bufio.NewReader(os.Stdin).ReadString('\n')
+		do.processes.Set(name, proc)
+		return
+	}
+
+	fmt.Printf("Waiting for %s to accept connections on %s...\n\n", name, proc.address())
+
+	do.waitForPort(proc)
+
+	do.processes.Set(name, proc)
+}
+
+// stopManually asks the user to stop the process and waits until it no longer accepts connections.
+func (do *Do) stopManually(name string, proc *Process, signal string) {
+	fmt.Printf("\n%s Stop %s now (%s), then the tests will continue.\n\n", yellow(Marks.Arrow), name, signal)
+
+	eventually(do.ctx, func() bool {
+		return !proc.accepting()
+	}, manualTimeout, do.config.RetryPollInterval)
+}
+
+// logPath returns the path of the process's log file.
+func (do *Do) logPath(name string) string {
+	return filepath.Join(do.workingDir, fmt.Sprintf("%s.log", name))
+}
+
+// waitForExit reaps the process and reports unexpected exits to the watchdog.
+func (do *Do) waitForExit(name string, proc *Process) {
+	proc.exitErr = proc.cmd.Wait()
+	do.state.remove(proc.cmd.Process.Pid)
+
+	// The failure is recorded first so whoever is waiting on exited can report it
+	if !proc.stopping.Load() && do.runCtx.Err() == nil {
+		do.watchdog.fail(do.exitFailure(name, proc))
+	}
+	close(proc.exited)
+}
+
+// exitFailure describes an unexpected exit of the process.
+func (do *Do) exitFailure(name string, proc *Process) string {
+	status := "exit status 0"
+	if proc.exitErr != nil {
+		status = proc.exitErr.Error()
+	}
+
+	tail := do.tailLog(name, crashLogLines)
+	if proc.limits.memory > 0 && !proc.cmd.ProcessState.Success() {
+		return fmt.Sprintf("Process %s exited %s under its memory limit of %s, so it likely ran out of memory.\n\n"+
+			"Reduce how much memory it keeps, e.g. by evicting or spilling data to disk.\n\n  Last log lines:\n%s",
+			name, describeExit(proc.cmd.ProcessState), formatBytes(proc.limits.memory), indent(tail, "    "))
+	}
+
+	if proc.limits.cpu > 0 && proc.cmd.ProcessState.Sys().(syscall.WaitStatus).Signal() == syscall.SIGXCPU {
+		return fmt.Sprintf("Process %s used up its CPU time limit of %s (%s).\n\n  Last log lines:\n%s",
+			name, proc.limits.cpu, status, indent(tail, "    "))
+	}
+
+	return fmt.Sprintf("Process %s exited unexpectedly %s.\n\n  Last log lines:\n%s",
+		name, describeExit(proc.cmd.ProcessState), indent(tail, "    "))
+}
+
+// crashLogLines is how much of a process's log is shown when it exits unexpectedly.
+const crashLogLines = 20
+
+// describeExit says how a process exited, e.g. "with status 2" or "after SIGSEGV".
+func describeExit(state *os.ProcessState) string {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return "after " + signalName(status.Signal())
+	}
+
+	return fmt.Sprintf("with status %d", state.ExitCode())
+}
+
+// readLog returns the process's log from offset on.
+func (do *Do) readLog(name string, offset int64) ([]byte, error) {
+	file, err := os.Open(do.logPath(name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(io.NewSectionReader(file, offset, math.MaxInt64-offset))
+}
+
+// tailLog returns the last n lines of the process's log.
+func (do *Do) tailLog(name string, n int) string {
+	data, err := os.ReadFile(do.logPath(name))
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// waitForPort waits for a process to accept connections on its port or
+// socket, failing with why it exited if it does so first, e.g. running out
+// of memory.
+func (do *Do) waitForPort(proc *Process) {
+	addr := proc.address()
+
+	timeout := do.config.ProcessStartTimeout
+	if proc.manual {
+		timeout = manualTimeout
+	}
+
+	var exited bool
+	succeeded := eventually(do.ctx, func() bool {
+		select {
+		case <-proc.exited:
+			exited = true
+			return true
+		default:
+			return proc.accepting()
+		}
+	}, timeout, do.config.RetryPollInterval)
+
+	if exited {
+		do.watchdog.check()
+		return
+	}
+
+	if !succeeded {
+		select {
+		case <-do.ctx.Done():
+			return
+		default:
+			if proc.socketPath != "" {
+				log.Fatalf(
+					"\nCould not connect to unix socket %s.\n\n"+
+						"Possible issues:\n"+
+						"- run.sh script not executable (run: chmod +x run.sh)\n"+
+						"- Process not listening on the path passed via --socket\n"+
+						"- Process crashing during startup\n\n"+
+						"Debug with: ./run.sh and check for error messages", addr,
+				)
+			}
+
+			log.Fatalf(
+				"\nCould not connect to http://%s.\n\n"+
+					"Possible issues:\n"+
+					"- run.sh script not executable (run: chmod +x run.sh)\n"+
+					"- Process not starting on port %d\n"+
+					"- Process crashing during startup\n\n"+
+					"Debug with: ./run.sh and check for error messages", addr, proc.realPort,
+			)
+		}
+	}
+}
+
+// Stop sends SIGTERM to the process, then SIGKILL after timeout.
+func (do *Do) Stop(name string) {
+	do.breadcrumbs.add("STOP %s", name)
+	do.stop(name)
+}
+
+func (do *Do) stop(name string) {
+	proc := do.getProcess(name)
+	if proc.manual {
+		do.stopManually(name, proc, "send SIGTERM or press Ctrl+C in its terminal")
+		return
+	}
+
+	if proc.server != nil {
+		do.stopInProcess(proc)
+		return
+	}
+
+	if proc.cmd == nil || proc.cmd.Process == nil {
+		return
+	}
+
+	proc.stopping.Store(true)
+
+	select {
+	case <-proc.exited:
+		// Process already exited
+	default:
+		pgid := proc.cmd.Process.Pid
+		err := syscall.Kill(-pgid, syscall.SIGTERM)
+		if err != nil {
+			fmt.Println(red("Error stopping process running @"), red(proc.realPort))
+			return
+		}
+		proc.wake()
+
+		// Wait for graceful exit, force kill if timeout
+		select {
+		case <-proc.exited:
+			// Process exited gracefully
+		case <-time.After(do.config.ProcessShutdownTimeout):
+			do.kill(name)
+			<-proc.exited
+		}
+	}
+
+	// Close log file after process exits
+	if proc.logFile != nil {
+		proc.logFile.Close()
+		proc.logFile = nil
+	}
+}
+
+// Kill sends SIGKILL to kill the process immediately.
+func (do *Do) Kill(name string) {
+	do.breadcrumbs.add("KILL %s", name)
+	do.kill(name)
+}
+
+// Pause freezes the process with SIGSTOP, e.g. to simulate a long garbage
+// collection pause: it keeps its state and connections but handles nothing
+// until Resume, so its peers may decide it's dead.
+func (do *Do) Pause(name string) {
+	do.breadcrumbs.add("PAUSE %s", name)
+	do.sendJobControl(name, syscall.SIGSTOP)
+}
+
+// Resume continues a process frozen with Pause, with SIGCONT.
+func (do *Do) Resume(name string) {
+	do.breadcrumbs.add("RESUME %s", name)
+	do.sendJobControl(name, syscall.SIGCONT)
+}
+
+// sendJobControl pauses or resumes the process's group with sig.
+func (do *Do) sendJobControl(name string, sig syscall.Signal) {
+	proc := do.getProcess(name)
+	switch {
+	case do.replaying():
+		return
+	case proc.manual || proc.server != nil || proc.cmd == nil || proc.cmd.Process == nil:
+		panic(fmt.Sprintf("%s isn't a process started by the harness, so it can't be paused or resumed", name))
+	}
+
+	err := syscall.Kill(-proc.cmd.Process.Pid, sig)
+	if err != nil && !errors.Is(err, syscall.ESRCH) {
+		panic(fmt.Sprintf("Failed to send %s to %s: %v", signalName(sig), name, err))
+	}
+
+	proc.paused.Store(sig == syscall.SIGSTOP)
+
+	// SIGSTOP is delivered asynchronously, so the process may briefly keep running
+	if sig == syscall.SIGSTOP && !eventually(do.ctx, func() bool {
+		return stopped(proc.cmd.Process.Pid)
+	}, time.Second, 5*time.Millisecond) {
+		panic(fmt.Sprintf("%s didn't stop within 1s", name))
+	}
+}
+
+// stopped reports whether every thread of every process in group pgid has
+// stopped. It reports true where /proc isn't available, as there's no way
+// to tell.
+func stopped(pgid int) bool {
+	pids, err := groupPIDs(pgid)
+	if err != nil {
+		return true
+	}
+
+	for _, pid := range pids {
+		tasks, err := os.ReadDir(fmt.Sprintf("/proc/%d/task", pid))
+		if err != nil {
+			continue
+		}
+
+		for _, task := range tasks {
+			fields, err := procStat(fmt.Sprintf("/proc/%d/task/%s/stat", pid, task.Name()))
+			if err == nil && len(fields) > 0 && fields[0] != "T" && fields[0] != "t" {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// resumePaused resumes every process left paused, e.g. by a test that
+// failed before resuming it.
+func (do *Do) resumePaused() {
+	do.processes.Range(func(_ string, proc *Process) bool {
+		proc.wake()
+		return true
+	})
+}
+
+// wake resumes a paused process so a signal just sent to it is handled.
+func (p *Process) wake() {
+	if p.paused.CompareAndSwap(true, false) {
+		syscall.Kill(-p.cmd.Process.Pid, syscall.SIGCONT)
+	}
+}
+
+func (do *Do) kill(name string) {
+	proc := do.getProcess(name)
+	if proc.manual {
+		do.stopManually(name, proc, "send SIGKILL")
+		return
+	}
+
+	if proc.server != nil {
+		proc.server.Close()
+		<-proc.exited
+		return
+	}
+
+	if proc.cmd == nil || proc.cmd.Process == nil {
+		return
+	}
+
+	proc.stopping.Store(true)
+
+	pgid := proc.cmd.Process.Pid
+	err := syscall.Kill(-pgid, syscall.SIGKILL)
+	if err != nil && !errors.Is(err, syscall.ESRCH) {
+		fmt.Println(red("Error killing process running @"), red(proc.realPort))
+	}
+
+	// Close log file if not already closed (e.g., when called directly, not via Stop)
+	if proc.logFile != nil {
+		proc.logFile.Close()
+		proc.logFile = nil
+	}
+}
+
+// Restart stops the process and starts it again.
+func (do *Do) Restart(name string, sig ...syscall.Signal) {
+	proc := do.getProcess(name)
+	if proc.cmd == nil && !proc.manual && proc.server == nil {
+		return
+	}
+
+	signal := syscall.SIGTERM
+	if len(sig) > 0 {
+		signal = sig[0]
+	}
+
+	switch signal {
+	case syscall.SIGKILL:
+		do.breadcrumbs.add("RESTART %s (SIGKILL)", name)
+		do.kill(name)
+	default:
+		do.breadcrumbs.add("RESTART %s (SIGTERM)", name)
+		do.stop(name)
+	}
+
+	time.Sleep(do.config.ProcessRestartDelay)
+	do.respawn(name, proc)
+}
+
+// respawn starts a stopped process again the way it was first started.
+func (do *Do) respawn(name string, proc *Process) {
+	do.startProcess(name, &Process{
+		realPort:   proc.realPort,
+		socketPath: proc.socketPath,
+		udp:        proc.udp,
+		tls:        proc.tls,
+		args:       proc.args,
+		limits:     proc.limits,
+		ready:      proc.ready,
+		ports:      proc.ports,
+	})
+}
+
+// Signal creates a deferred signal to a started process, whose assertion
+// checks how the process exits, e.g. that it handles SIGTERM promptly:
+//
+//	do.Signal("node", syscall.SIGTERM).T().ExitAfter(LessThan(time.Second)).Assert(help)
+//
+// A process still running after the shutdown timeout is killed. Restart
+// starts it again. Processes started manually are only asked to stop.
+func (do *Do) Signal(name string, sig syscall.Signal) *SignalPromise {
+	return &SignalPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		name:   name,
+		signal: sig,
+		send: func() processExit {
+			return do.signal(name, sig)
+		},
+	}
+}
+
+// WaitExit creates a deferred wait for a started process to exit, whose
+// assertion checks how it exited, e.g. that it shuts down cleanly:
+//
+//	do.Stop("node")
+//	do.WaitExit("node").T().ExitCode(Is(0)).Assert(help)
+//
+// A process that already exited, e.g. with Stop, is checked right away.
+// Otherwise the wait is up to the shutdown timeout, and the process exiting
+// by itself meanwhile isn't an unexpected exit. Processes started manually
+// and in-process servers have no exit code, so they aren't checked.
+func (do *Do) WaitExit(name string) *ExitPromise {
+	return &ExitPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		name: name,
+		wait: func() processExit {
+			return do.waitExit(name)
+		},
+	}
+}
+
+// waitExit waits up to the shutdown timeout for the process to exit.
+func (do *Do) waitExit(name string) processExit {
+	proc := do.getProcess(name)
+	switch {
+	case do.replaying() || proc.manual || proc.server != nil:
+		return processExit{unchecked: true}
+	case proc.cmd == nil || proc.cmd.Process == nil:
+		panic(fmt.Sprintf("%s wasn't started by the harness, so it can't be waited for", name))
+	}
+
+	stopping := proc.stopping.Swap(true)
+	select {
+	case <-proc.exited:
+	case <-time.After(do.config.ProcessShutdownTimeout):
+		// Exiting later is unexpected again
+		proc.stopping.Store(stopping)
+		return processExit{after: do.config.ProcessShutdownTimeout, running: true}
+	}
+
+	if proc.logFile != nil {
+		proc.logFile.Close()
+		proc.logFile = nil
+	}
+
+	return processExit{exitCode: proc.cmd.ProcessState.ExitCode(), how: describeExit(proc.cmd.ProcessState)}
+}
+
+// signal sends sig to the process and waits for it to exit, killing it after the shutdown timeout.
+func (do *Do) signal(name string, sig syscall.Signal) processExit {
+	proc := do.getProcess(name)
+	switch {
+	case do.replaying():
+		return processExit{unchecked: true}
+	case proc.manual:
+		do.stopManually(name, proc, fmt.Sprintf("send %s", signalName(sig)))
+		return processExit{unchecked: true}
+	case proc.server != nil:
+		return do.signalInProcess(proc, sig)
+	case proc.cmd == nil || proc.cmd.Process == nil:
+		panic(fmt.Sprintf("%s wasn't started by the harness, so it can't be signalled", name))
+	}
+
+	proc.stopping.Store(true)
+
+	start := time.Now()
+	err := syscall.Kill(-proc.cmd.Process.Pid, sig)
+	if err != nil && !errors.Is(err, syscall.ESRCH) {
+		panic(fmt.Sprintf("Failed to send %s to %s: %v", signalName(sig), name, err))
+	}
+	proc.wake()
+
+	var exit processExit
+	select {
+	case <-proc.exited:
+	case <-time.After(do.config.ProcessShutdownTimeout):
+		exit.killed = true
+		do.kill(name)
+		<-proc.exited
+	}
+	exit.after = time.Since(start)
+	exit.exitCode = proc.cmd.ProcessState.ExitCode()
+
+	if proc.logFile != nil {
+		proc.logFile.Close()
+		proc.logFile = nil
+	}
+
+	return exit
+}
+
+// Done cleans up all running processes.
+func (do *Do) Done() {
+	do.stopChaos()
+	do.cancel()
+
+	// Processes started manually are left for the user to stop
+	var processNames []string
+	do.processes.Range(func(name string, proc *Process) bool {
+		if !proc.manual {
+			processNames = append(processNames, name)
+		}
+
+		return true
+	})
+
+	for _, name := range processNames {
+		do.stop(name)
+	}
+
+	do.interactive.Range(func(_ string, session *InteractiveSession) bool {
+		session.Close()
+		return true
+	})
+	do.killStreams()
+	do.network.close()
+	do.grpcTransports.close()
+	do.restoreDisk()
+	do.unmountDataDirs()
+
+	do.trace.close()
+	do.exchanges.close()
+}
+
+// beginTest marks the current end of every process log so log assertions
+// only consider output produced during the test.
+func (do *Do) beginTest() {
+	do.deferMu.Lock()
+	do.deferred = nil
+	do.deferMu.Unlock()
+	do.lastHTTP.take()
+
+	// Commands and faults left by a test that failed before it ended
+	do.killStreams()
+	do.restoreDisk()
+	do.stopChaos()
+	do.chaos = nil
+	do.resumePaused()
+	do.network.heal()
+
+	do.processes.Range(func(name string, _ *Process) bool {
+		info, err := os.Stat(do.logPath(name))
+		if err == nil {
+			do.logMarks.Set(name, info.Size())
+		}
+
+		return true
+	})
+}
+
+// endTest runs assertions deferred until the end of the current test.
+func (do *Do) endTest() {
+	do.deferMu.Lock()
+	deferred := do.deferred
+	do.deferred = nil
+	do.deferMu.Unlock()
+
+	for _, fn := range deferred {
+		fn()
+	}
+
+	do.killStreams()
+	do.restoreDisk()
+	do.resumePaused()
+	do.network.heal()
+	if err := do.stopChaos(); err != nil {
+		panic(err)
+	}
+	do.watchdog.check()
+}
+
+// skipSignal is panicked by SkipIf and recovered by the suite.
+type skipSignal struct {
+	reason string
+}
+
+// SkipIf stops the current test and marks it as skipped if cond holds.
+// Called during setup, it skips every test. It must be called from the
+// goroutine running the test, not from inside Concurrently.
+func (do *Do) SkipIf(cond bool, reason string) {
+	if cond {
+		panic(skipSignal{reason: reason})
+	}
+}
+
+// atEnd defers fn until the end of the current test.
+func (do *Do) atEnd(fn func()) {
+	do.deferMu.Lock()
+	defer do.deferMu.Unlock()
+
+	do.deferred = append(do.deferred, fn)
+}
+
+// probe reports whether the process implements the endpoint, i.e. it responds
+// with anything other than 404 Not Found, 405 Method Not Allowed or 501 Not Implemented.
+func (do *Do) probe(name string, endpoint Endpoint) bool {
+	proc := do.getProcess(name)
+
+	client := newHTTPClient(do.config.ExecuteTimeout, proc.socketPath, proc.tls, false)
+
+	req, err := http.NewRequestWithContext(do.ctx, endpoint.Method, proc.url(endpoint.Path), strings.NewReader(endpoint.Body))
+	if err != nil {
+		return false
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return false
+	default:
+		return true
+	}
+}
+
+// Variant returns the value of the named matrix dimension the suite is
+// running under, e.g. Variant[syscall.Signal](do, "signal").
+func Variant[T any](do *Do, name string) T {
+	value, ok := do.variant[name]
+	if !ok {
+		panic(fmt.Sprintf("Variant %q isn't a matrix dimension of this suite", name))
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		panic(fmt.Sprintf("Variant %q is a %T, not a %T", name, value, typed))
+	}
+
+	return typed
+}
+
+// Concurrently runs multiple functions in parallel and waits for completion.
+// Each is passed a copy of do for its operations, including those of any
+// goroutines it starts, so they're canceled along with the group. If any of
+// them fail, the distinct failures are reported together with how
+// many functions hit each one. If a process exits unexpectedly meanwhile,
+// operations still in flight are canceled rather than left to time out, as
+// they are when the run itself is canceled.
+func (do *Do) Concurrently(fns ...func(do *Do)) {
+	ctx, cancel := context.WithCancel(do.ctx)
+	group := do.withContext(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var failures []any
+	var canceled int
+	var failuresMu sync.Mutex
+
+	for _, fn := range fns {
+		wg.Add(1)
+		go func(f func(do *Do)) {
+			defer wg.Done()
+			defer func() {
+				err := recover()
+				if err != nil {
+					failuresMu.Lock()
+					if ctx.Err() != nil {
+						canceled++
+					} else {
+						failures = append(failures, err)
+					}
+					failuresMu.Unlock()
+				}
+			}()
+
+			f(group)
+		}(fn)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Watch for crashes until every function has returned
+	var crashed string
+	ticker := time.NewTicker(do.config.RetryPollInterval)
+	defer ticker.Stop()
+
+watch:
+	for {
+		select {
+		case <-done:
+			break watch
+		case <-ticker.C:
+			if crashed == "" {
+				crashed = do.crashedProcess()
+				if crashed != "" {
+					cancel()
+				}
+			}
+		}
+	}
+
+	var msg string
+	switch {
+	case len(failures) == 1 && canceled == 0:
+		panic(failures[0])
+	case len(failures) == 1:
+		msg = fmt.Sprint(failures[0]) + "\n\n"
+	case len(failures) > 1:
+		msg = summarizeFailures(failures, len(fns)) + "\n\n"
+	}
+
+	switch {
+	case canceled > 0 && crashed != "":
+		msg += fmt.Sprintf("%d/%d concurrent operations were canceled after process %s exited unexpectedly.\n\n  Last log lines:\n%s",
+			canceled, len(fns), crashed, indent(do.tailLog(crashed, 20), "    "))
+	case canceled > 0:
+		// The run itself was canceled, e.g. interrupted, so no process is to blame
+		msg += fmt.Sprintf("%d/%d concurrent operations were canceled because the run was canceled (%v)",
+			canceled, len(fns), context.Cause(do.ctx))
+	}
+
+	if msg != "" {
+		panic(strings.TrimSuffix(msg, "\n\n"))
+	}
+}
+
+// withContext returns a copy of do whose operations run under ctx and are
+// marked as concurrent.
+func (do *Do) withContext(ctx context.Context) *Do {
+	return &Do{runState: do.runState, ctx: ctx, concurrent: true}
+}
+
+// crashedProcess returns the name of a started process that has exited
+// without being stopped by the harness, or "" if there's none.
+func (do *Do) crashedProcess() string {
+	var crashed string
+	do.processes.Range(func(name string, proc *Process) bool {
+		select {
+		case <-proc.exited:
+			if !proc.stopping.Load() {
+				crashed = name
+			}
+		default:
+		}
+
+		return crashed == ""
+	})
+
+	return crashed
+}
+
+// failureGroup is a distinct failure and how many times it occurred.
+type failureGroup struct {
+	sample string
+	count  int
+}
+
+// digitRuns matches the parts of a failure that vary between otherwise identical ones, e.g. keys.
+var digitRuns = regexp.MustCompile(`[0-9]+`)
+
+// summarizeFailures groups failures that differ only in their trace ID or in
+// their numbers (e.g. the key in a URL and its value), most frequent first.
+// Each group is shown with its first failure.
+func summarizeFailures(failures []any, total int) string {
+	var groups []*failureGroup
+	index := make(map[string]*failureGroup)
+
+	for _, failure := range failures {
+		sample := fmt.Sprint(failure)
+
+		var lines []string
+		for line := range strings.SplitSeq(sample, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "Trace: ") {
+				continue
+			}
+
+			lines = append(lines, digitRuns.ReplaceAllString(line, "#"))
+		}
+		key := strings.Join(lines, "\n")
+
+		group, exists := index[key]
+		if !exists {
+			group = &failureGroup{sample: sample}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		group.count++
+	}
+
+	slices.SortStableFunc(groups, func(a, b *failureGroup) int {
+		return b.count - a.count
+	})
+
+	msg := fmt.Sprintf("%d/%d concurrent operations failed", len(failures), total)
+	for _, group := range groups {
+		msg += fmt.Sprintf("\n\n%d/%d failed with:\n%s", group.count, total, group.sample)
+	}
+
+	return msg
+}
+
+// newPromiseBase creates the common state shared by all promises.
+func (do *Do) newPromiseBase() PromiseBase {
+	return PromiseBase{
+		timing:      TimingImmediate,
+		ctx:         do.ctx,
+		limiter:     do.limiter,
+		jitter:      do.jitter,
+		trace:       do.trace,
+		breadcrumbs: do.breadcrumbs,
+		metrics:     do.metrics,
+		watchdog:    do.watchdog,
+		config:      do.config,
+	}
+}
+
+// URL returns the URL of path on the named process, e.g. to check that a
+// redirect points at it.
+func (do *Do) URL(name, path string) string {
+	return do.getProcess(name).url(path)
+}
+
+// HTTP creates a deferred HTTP request. The optional body is a string,
+// []byte or io.Reader, and may be followed by headers (H).
+func (do *Do) HTTP(name, method, path string, args ...any) *HTTPPromise {
+	proc := do.getProcess(name)
+	url := proc.url(path)
+
+	var body []byte
+	var bodyReader io.Reader
+	if len(args) >= 1 {
+		switch b := args[0].(type) {
+		case string:
+			body = []byte(b)
+		case []byte:
+			body = b
+		case io.Reader:
+			bodyReader = b
+		default:
+			panic(fmt.Sprintf("unsupported request body type %T", args[0]))
+		}
+	}
+
+	var headers H
+	if len(args) >= 2 {
+		headers = args[1].(H)
+	}
+
+	return &HTTPPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		method:     method,
+		path:       path,
+		url:        url,
+		socketPath: proc.socketPath,
+		tls:        proc.tls,
+		ports:      proc.ports,
+		headers:    headers,
+		body:       body,
+		bodyReader: bodyReader,
+		sessions:   do.sessions,
+		process:    name,
+		exchanges:  do.exchanges,
+		last:       do.lastHTTP,
+		oracle:     do.oracle,
+		concurrent: do.concurrent,
+	}
+}
+
+// GRPC creates a deferred unary gRPC call with a JSON request.
+// The method's descriptors must be registered with Suite.GRPC.
+func (do *Do) GRPC(name, service, method, reqJSON string) *GRPCPromise {
+	proc := do.getProcess(name)
+
+	path := fmt.Sprintf("/%s/%s", service, method)
+	desc, exists := do.grpcMethods[path]
+	if !exists {
+		panic(fmt.Sprintf("gRPC method %s/%s isn't registered", service, method))
+	}
+
+	message, err := desc.Request.encodeJSON(reqJSON)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid gRPC request: %v", err))
+	}
+
+	return &GRPCPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		method:    desc,
+		url:       proc.url(path),
+		transport: do.grpcTransports.get(proc.socketPath, proc.tls),
+		request:   reqJSON,
+		message:   message,
+	}
+}
+
+// SSE creates a deferred subscription to a server-sent event stream at path.
+func (do *Do) SSE(name, path string) *SSEPromise {
+	proc := do.getProcess(name)
+
+	return &SSEPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		url:        proc.url(path),
+		socketPath: proc.socketPath,
+		tls:        proc.tls,
+		window:     defaultSSEWindow,
+	}
+}
+
+// Fuzz creates a deferred batch of raw malformed inputs sent to the process.
+// Each input is written on its own connection.
+func (do *Do) Fuzz(name string, inputs ...[]byte) *FuzzPromise {
+	proc := do.getProcess(name)
+
+	return &FuzzPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		network: proc.network(),
+		addr:    proc.address(),
+		inputs:  inputs,
+	}
+}
+
+// TCP creates a deferred exchange of raw bytes with the process.
+func (do *Do) TCP(name string) *TCPPromise {
+	proc := do.getProcess(name)
+
+	return &TCPPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		network: proc.network(),
+		addr:    proc.address(),
+	}
+}
+
+// HTTPRaw creates a deferred HTTP exchange that writes request to the
+// process verbatim, e.g. to send malformed requests net/http never would.
+func (do *Do) HTTPRaw(name string, request []byte) *HTTPRawPromise {
+	proc := do.getProcess(name)
+
+	return &HTTPRawPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		network: proc.network(),
+		addr:    proc.address(),
+		request: request,
+	}
+}
+
+// HTTPConn creates a deferred series of HTTP exchanges that writes each
+// request to the process verbatim over one connection, e.g. to check that
+// keep-alive connections are reused and Connection: close is honored.
+func (do *Do) HTTPConn(name string, requests ...[]byte) *HTTPConnPromise {
+	proc := do.getProcess(name)
+
+	return &HTTPConnPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		network:  proc.network(),
+		addr:     proc.address(),
+		requests: requests,
+	}
+}
+
+// RESP creates a deferred Redis protocol command sent to the process.
+func (do *Do) RESP(name string, args ...string) *RESPPromise {
+	proc := do.getProcess(name)
+
+	return &RESPPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		network: proc.network(),
+		addr:    proc.address(),
+		args:    args,
+	}
+}
+
+// UDP creates a deferred exchange of datagrams with the process.
+func (do *Do) UDP(name string, payload []byte) *UDPPromise {
+	proc := do.getProcess(name)
+
+	return &UDPPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		addr:         proc.address(),
+		payload:      payload,
+		replyTimeout: defaultUDPReplyTimeout,
+	}
+}
+
+// DNS creates a deferred DNS query for qname's records of type qtype,
+// e.g. "A", "AAAA" or "MX", sent over UDP to the process.
+func (do *Do) DNS(name, qtype, qname string) *DNSPromise {
+	proc := do.getProcess(name)
+
+	code, ok := dnsTypes[qtype]
+	if !ok {
+		panic(fmt.Sprintf("DNS record type %q isn't supported", qtype))
+	}
+
+	// Each attempt sets its own ID
+	query, err := encodeDNSQuery(0, code, qname)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid DNS query: %v", err))
+	}
+
+	return &DNSPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		addr:         proc.address(),
+		qtype:        qtype,
+		qname:        qname,
+		query:        query,
+		replyTimeout: defaultUDPReplyTimeout,
+	}
+}
+
+// WS creates a deferred websocket session with the process at path.
+func (do *Do) WS(name, path string) *WSPromise {
+	proc := do.getProcess(name)
+
+	host := proc.address()
+	if proc.socketPath != "" {
+		host = "localhost"
+	}
+
+	return &WSPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		network:      proc.network(),
+		addr:         proc.address(),
+		host:         host,
+		path:         path,
+		replyTimeout: defaultWSReplyTimeout,
+	}
+}
+
+// Logs creates a deferred check of the process's log output produced during the current test.
+func (do *Do) Logs(name string) *LogsPromise {
+	mark, _ := do.logMarks.Get(name)
+
+	return &LogsPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		name:   name,
+		path:   do.logPath(name),
+		offset: mark,
+		atEnd:  do.atEnd,
+	}
+}
+
+// FDCount creates a deferred count of the file descriptors the process has
+// open, e.g. sockets and files, to check it doesn't leak them:
+//
+//	fds := do.FDCount("node")
+//	// ... thousands of requests ...
+//	fds.Eventually().T().Growth(LessThan(10)).Assert(help)
+//
+// Processes in its process group are counted too, e.g. one run by a script.
+// Counts are read from /proc, so the test is skipped where it isn't available.
+func (do *Do) FDCount(name string) *FDPromise {
+	proc := do.getProcess(name)
+	if !do.replaying() && (proc.manual || proc.server != nil || proc.cmd == nil) {
+		panic(fmt.Sprintf("%s isn't a process started by the harness, so its file descriptors can't be counted", name))
+	}
+
+	_, err := os.Stat("/proc/self/fd")
+	do.SkipIf(err != nil, "file descriptors are counted through /proc, which isn't available on this system")
+
+	count := func() (int, error) {
+		if do.replaying() {
+			return 0, nil
+		}
+
+		return countFDs(do.getProcess(name).cmd.Process.Pid)
+	}
+
+	baseline, err := count()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to count the file descriptors of %s: %v", name, err))
+	}
+
+	return &FDPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		name:     name,
+		count:    count,
+		baseline: baseline,
+	}
+}
+
+// countFDs returns how many file descriptors the processes in group pgid
+// have open, skipping processes that exit while they're counted.
+func countFDs(pgid int) (int, error) {
+	pids, err := groupPIDs(pgid)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	for _, pid := range pids {
+		fds, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return 0, err
+		}
+		total += len(fds)
+	}
+
+	return total, nil
+}
+
+// groupPIDs lists the processes in group pgid.
+func groupPIDs(pgid int) ([]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// The process group is the third field after the command name
+		fields, err := procStat(fmt.Sprintf("/proc/%d/stat", pid))
+		if err == nil && len(fields) >= 3 && fields[2] == strconv.Itoa(pgid) {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+}
+
+// procStat returns the fields of a /proc stat file after the command name,
+// which may contain spaces, starting with the state.
+func procStat(path string) ([]string, error) {
+	stat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:])), nil
+}
+
+// ExecAgainst creates a deferred CLI command execution whose command talks to
+// the named process, e.g. a client for a daemon. The process's address is
+// prepended to args as --socket=<path> for Unix domain sockets and
+// --addr=<host:port> otherwise.
+func (do *Do) ExecAgainst(name string, args ...string) *CLIPromise {
+	proc := do.getProcess(name)
+
+	addrArg := fmt.Sprintf("--addr=%s", proc.address())
+	if proc.socketPath != "" {
+		addrArg = fmt.Sprintf("--socket=%s", proc.socketPath)
+	}
+
+	return do.Exec(append([]string{addrArg}, args...)...)
+}
+
+// Exec creates a deferred CLI command execution.
+func (do *Do) Exec(args ...string) *CLIPromise {
+	return &CLIPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		command:   do.config.Command,
+		args:      args,
+		dir:       do.config.Dir,
+		runDir:    do.workingDir,
+		state:     do.state,
+		addStream: do.addStream,
 	}
 }
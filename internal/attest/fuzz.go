@@ -0,0 +1,55 @@
+package attest
+
+import "strings"
+
+// MalformedHTTPRequests returns raw HTTP requests against path that violate the
+// protocol in common ways: bad request lines, broken headers and truncated frames.
+func MalformedHTTPRequests(path string) [][]byte {
+	return [][]byte{
+		// Garbage request line
+		[]byte("GARBAGE\r\n\r\n"),
+		// Missing protocol version
+		[]byte("GET " + path + "\r\n\r\n"),
+		// Unsupported protocol version
+		[]byte("GET " + path + " HTTP/9.9\r\nHost: localhost\r\n\r\n"),
+		// Header without a colon
+		[]byte("GET " + path + " HTTP/1.1\r\nHost: localhost\r\nBroken-Header\r\n\r\n"),
+		// Non-numeric Content-Length
+		[]byte("PUT " + path + " HTTP/1.1\r\nHost: localhost\r\nContent-Length: abc\r\n\r\nvalue"),
+		// Body shorter than Content-Length
+		[]byte("PUT " + path + " HTTP/1.1\r\nHost: localhost\r\nContent-Length: 100\r\n\r\ntruncated"),
+		// Invalid chunk size
+		[]byte("PUT " + path + " HTTP/1.1\r\nHost: localhost\r\nTransfer-Encoding: chunked\r\n\r\nzz\r\nvalue\r\n"),
+		// Header line without a terminator
+		[]byte("GET " + path + " HTTP/1.1\r\nHost: localhost\r\nX-Fuzz: " + strings.Repeat("a", 1<<10)),
+		// Truncated frame
+		[]byte("GET " + path + " HTTP/1.1\r\nHost: localh"),
+		// Binary noise
+		{0x00, 0x01, 0x02, 0xff, 0xfe, '\r', '\n', '\r', '\n'},
+	}
+}
+
+// InvalidUTF8Requests returns well-formed raw HTTP requests whose path embeds
+// a key under prefix that is not valid UTF-8.
+func InvalidUTF8Requests(method, prefix string) [][]byte {
+	keys := []string{
+		"\xff\xfe",
+		"key\xc3\x28",
+		"\xe2\x28\xa1",
+		"\xf0\x28\x8c\xbc",
+		"%ff%fe",
+		"%c3%28",
+	}
+
+	requests := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		request := method + " " + prefix + key + " HTTP/1.1\r\n" +
+			"Host: localhost\r\n" +
+			"Content-Length: 5\r\n" +
+			"Connection: close\r\n\r\n" +
+			"value"
+		requests = append(requests, []byte(request))
+	}
+
+	return requests
+}
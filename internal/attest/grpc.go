@@ -0,0 +1,186 @@
+package attest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// GRPCCode is a gRPC status code.
+type GRPCCode int
+
+const (
+	GRPCOK GRPCCode = iota
+	GRPCCanceled
+	GRPCUnknown
+	GRPCInvalidArgument
+	GRPCDeadlineExceeded
+	GRPCNotFound
+	GRPCAlreadyExists
+	GRPCPermissionDenied
+	GRPCResourceExhausted
+	GRPCFailedPrecondition
+	GRPCAborted
+	GRPCOutOfRange
+	GRPCUnimplemented
+	GRPCInternal
+	GRPCUnavailable
+	GRPCDataLoss
+	GRPCUnauthenticated
+)
+
+var grpcCodeNames = []string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED", "NOT_FOUND",
+	"ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED", "FAILED_PRECONDITION",
+	"ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED", "INTERNAL", "UNAVAILABLE", "DATA_LOSS",
+	"UNAUTHENTICATED",
+}
+
+func (c GRPCCode) String() string {
+	if c >= 0 && int(c) < len(grpcCodeNames) {
+		return grpcCodeNames[c]
+	}
+
+	return fmt.Sprintf("CODE(%d)", int(c))
+}
+
+// GRPCMethod describes a unary gRPC method and its message types.
+type GRPCMethod struct {
+	// Service is the fully-qualified service name, e.g. "lock.v1.LockService"
+	Service  string
+	Method   string
+	Request  *ProtoDescriptor
+	Response *ProtoDescriptor
+}
+
+func (m GRPCMethod) path() string {
+	return fmt.Sprintf("/%s/%s", m.Service, m.Method)
+}
+
+// grpcTransports keeps one HTTP/2 transport per target, so calls reuse
+// connections instead of opening a new one each time.
+type grpcTransports struct {
+	mu         sync.Mutex
+	transports map[grpcTarget]*http.Transport
+}
+
+// grpcTarget is what a transport dials.
+type grpcTarget struct {
+	socketPath string
+	tls        bool
+}
+
+// get returns the transport for a target, creating it if needed.
+func (g *grpcTransports) get(socketPath string, useTLS bool) *http.Transport {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	target := grpcTarget{socketPath: socketPath, tls: useTLS}
+	transport, ok := g.transports[target]
+	if !ok {
+		transport = newGRPCTransport(socketPath, useTLS)
+		if g.transports == nil {
+			g.transports = make(map[grpcTarget]*http.Transport)
+		}
+		g.transports[target] = transport
+	}
+
+	return transport
+}
+
+// close closes every transport's connections.
+func (g *grpcTransports) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, transport := range g.transports {
+		transport.CloseIdleConnections()
+	}
+	g.transports = nil
+}
+
+// newGRPCTransport creates a transport that speaks HTTP/2, over TLS if useTLS
+// is set and without it (h2c) otherwise, dialing socketPath instead of TCP if set.
+func newGRPCTransport(socketPath string, useTLS bool) *http.Transport {
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(!useTLS)
+	protocols.SetHTTP2(useTLS)
+
+	transport := &http.Transport{Protocols: protocols}
+	if useTLS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	if socketPath != "" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+		}
+	}
+
+	return transport
+}
+
+// grpcCall issues a unary call with an already-encoded request message and
+// returns the raw response message along with the call's status.
+func grpcCall(ctx context.Context, client *http.Client, target string, message []byte) ([]byte, GRPCCode, string, error) {
+	frame := []byte{0}
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(message)))
+	frame = append(frame, message...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(frame))
+	if err != nil {
+		return nil, GRPCUnknown, "", err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, GRPCUnknown, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, GRPCUnknown, "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, GRPCUnknown, fmt.Sprintf("HTTP status %d", resp.StatusCode), nil
+	}
+
+	// Trailers-only responses carry the status in the headers
+	status := resp.Trailer.Get("Grpc-Status")
+	statusMessage := resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status = resp.Header.Get("Grpc-Status")
+		statusMessage = resp.Header.Get("Grpc-Message")
+	}
+
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return nil, GRPCUnknown, fmt.Sprintf("missing or invalid grpc-status %q", status), nil
+	}
+	statusMessage, _ = url.PathUnescape(statusMessage)
+
+	if len(body) == 0 {
+		return nil, GRPCCode(code), statusMessage, nil
+	}
+
+	if len(body) < 5 || uint32(len(body)-5) < binary.BigEndian.Uint32(body[1:5]) {
+		return nil, GRPCCode(code), statusMessage, fmt.Errorf("malformed response frame")
+	}
+	if body[0] != 0 {
+		return nil, GRPCCode(code), statusMessage, fmt.Errorf("compressed responses aren't supported")
+	}
+
+	return body[5 : 5+binary.BigEndian.Uint32(body[1:5])], GRPCCode(code), statusMessage, nil
+}
@@ -0,0 +1,102 @@
+package attest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Server creates an implementation's HTTP handler from the arguments its
+// process would be started with, e.g. --port=<port> and --working-dir=<path>,
+// so a suite can run against it without starting the configured command.
+type Server func(args []string) (http.Handler, error)
+
+// InProcess serves every process the suite starts with a handler from server,
+// inside the harness's own process, e.g. when running suites with go test.
+// The harness listens on the process's port itself. Stopping the process
+// shuts its server down and closes the handler if it's an io.Closer, while
+// killing it drops connections and abandons the handler without closing it.
+func (s *Suite) InProcess(server Server) *Suite {
+	s.server = server
+	return s
+}
+
+// startInProcess serves the handler server creates for args on the process's port or socket.
+func (do *Do) startInProcess(name string, proc *Process, args []string) {
+	if proc.udp || proc.tls {
+		panic(fmt.Sprintf("%s can't run in-process, only plain HTTP processes can", name))
+	}
+
+	handler, err := do.server(args)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create %s: %v", name, err))
+	}
+
+	listener, err := net.Listen(proc.network(), proc.address())
+	if err != nil {
+		panic(fmt.Sprintf("Failed to listen on %s: %v", proc.address(), err))
+	}
+	do.metrics.processes.Add(1)
+	// A process that picks its own port gets whichever the harness listened on
+	if addr, ok := listener.Addr().(*net.TCPAddr); ok && proc.realPort == 0 {
+		proc.realPort = addr.Port
+	}
+
+	proc.server = &http.Server{Handler: handler}
+	proc.exited = make(chan struct{})
+	go func() {
+		proc.server.Serve(listener)
+		close(proc.exited)
+	}()
+
+	do.processes.Set(name, proc)
+}
+
+// stopInProcess shuts the process's server down gracefully, then closes its
+// handler. It reports whether the server shut down within the timeout.
+func (do *Do) stopInProcess(proc *Process) bool {
+	select {
+	case <-proc.exited:
+		// Already stopped or killed, a killed handler stays abandoned
+		return true
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), do.config.ProcessShutdownTimeout)
+	defer cancel()
+
+	err := proc.server.Shutdown(ctx)
+	if err != nil {
+		proc.server.Close()
+	}
+	<-proc.exited
+
+	if closer, ok := proc.server.Handler.(io.Closer); ok {
+		closer.Close()
+	}
+
+	return err == nil
+}
+
+// signalInProcess stops the process's server the way sig would stop a
+// process: SIGKILL abandons it, any other signal shuts it down gracefully.
+func (do *Do) signalInProcess(proc *Process, sig syscall.Signal) processExit {
+	start := time.Now()
+	if sig == syscall.SIGKILL {
+		proc.server.Close()
+		<-proc.exited
+		return processExit{after: time.Since(start), exitCode: -1}
+	}
+
+	graceful := do.stopInProcess(proc)
+	exit := processExit{after: time.Since(start), killed: !graceful}
+	if !graceful {
+		exit.exitCode = -1
+	}
+
+	return exit
+}
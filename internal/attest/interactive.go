@@ -0,0 +1,296 @@
+package attest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// InteractiveSession is a program running under a pseudo-terminal, driven
+// step by step the way a user at a keyboard would, e.g. a shell or a REPL.
+type InteractiveSession struct {
+	name    string
+	cmd     *exec.Cmd
+	ptmx    *os.File
+	timeout time.Duration
+
+	ctx         context.Context
+	config      *Config
+	breadcrumbs *breadcrumbs
+	state       *processState
+
+	mu     sync.Mutex
+	output []byte
+	// consumed is how much of the output earlier expectations have matched
+	consumed int
+	// changed is closed and replaced whenever output arrives
+	changed chan struct{}
+	// sent holds the inputs since the last met expectation, for failures
+	sent []string
+
+	// exited is closed once the program has exited, with exitErr holding the result of Wait
+	exited  chan struct{}
+	exitErr error
+}
+
+// Interactive starts the program with args under a pseudo-terminal. Unlike
+// processes, it isn't passed a port or working directory. The session's
+// transcript is logged to <name>.log in the run's working directory.
+func (do *Do) Interactive(name string, args ...string) *InteractiveSession {
+	do.breadcrumbs.add("INTERACTIVE %s", name)
+
+	ptmx, tty, err := openPTY()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to open a pseudo-terminal: %v", err))
+	}
+	defer tty.Close()
+
+	setWindowSize(ptmx.Fd(), 24, 80)
+
+	logFile, err := os.OpenFile(do.logPath(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		ptmx.Close()
+		panic(fmt.Sprintf("failed to create log file: %v", err))
+	}
+
+	// A dumb terminal keeps programs from decorating output with escape sequences
+	cmd := exec.CommandContext(do.runCtx, do.config.Command, args...)
+	cmd.Dir = do.config.Dir
+	cmd.Env = append(os.Environ(), "TERM=dumb")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+
+	err = cmd.Start()
+	if err != nil {
+		ptmx.Close()
+		logFile.Close()
+		panic(err.Error())
+	}
+	do.metrics.processes.Add(1)
+	do.state.add(ProcessRecord{
+		Name:    name,
+		PID:     cmd.Process.Pid,
+		Started: time.Now().UTC().Truncate(time.Second),
+		RunDir:  do.workingDir,
+	})
+
+	s := &InteractiveSession{
+		name:        name,
+		cmd:         cmd,
+		ptmx:        ptmx,
+		timeout:     do.config.DefaultRetryTimeout,
+		ctx:         do.runCtx,
+		config:      do.config,
+		breadcrumbs: do.breadcrumbs,
+		state:       do.state,
+		changed:     make(chan struct{}),
+		exited:      make(chan struct{}),
+	}
+
+	go s.read(logFile)
+	go s.wait()
+
+	do.interactive.Set(name, s)
+	return s
+}
+
+// read collects the program's output until the terminal is closed.
+func (s *InteractiveSession) read(logFile *os.File) {
+	defer logFile.Close()
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := s.ptmx.Read(buf)
+		if n > 0 {
+			logFile.Write(buf[:n])
+
+			s.mu.Lock()
+			s.output = append(s.output, buf[:n]...)
+			close(s.changed)
+			s.changed = make(chan struct{})
+			s.mu.Unlock()
+		}
+
+		// Reads fail with EIO once the program and its children have exited
+		if err != nil {
+			return
+		}
+	}
+}
+
+// wait reaps the program.
+func (s *InteractiveSession) wait() {
+	s.exitErr = s.cmd.Wait()
+	s.state.remove(s.cmd.Process.Pid)
+	close(s.exited)
+}
+
+// Timeout sets how long each following expectation may wait, instead of
+// the default retry timeout.
+func (s *InteractiveSession) Timeout(timeout time.Duration) *InteractiveSession {
+	s.timeout = scaleDuration(timeout, s.config.TimeoutMultiplier)
+	return s
+}
+
+// Send types input into the terminal. Include "\n" to press enter.
+func (s *InteractiveSession) Send(input string) *InteractiveSession {
+	s.breadcrumbs.add("SEND %s %q", s.name, truncateValue(input, 40))
+
+	s.mu.Lock()
+	s.sent = append(s.sent, input)
+	s.mu.Unlock()
+
+	_, err := s.ptmx.Write([]byte(input))
+	if err != nil {
+		panic(fmt.Sprintf("%s\n  Failed to send %q: %v", s.describe(), input, err))
+	}
+
+	return s
+}
+
+// Expect waits until the output since the last met expectation passes all
+// checkers, then consumes it. Terminals end lines with "\r\n", which is
+// checked as "\n". The input a terminal echoes back is part of the output.
+func (s *InteractiveSession) Expect(checkers ...Checker[string]) *InteractiveSession {
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		end := len(s.output)
+		pending := normalizeTerminalOutput(s.output[s.consumed:end])
+		changed := s.changed
+		s.mu.Unlock()
+
+		if checkAll(pending, checkers, nil) {
+			s.mu.Lock()
+			s.consumed, s.sent = end, nil
+			s.mu.Unlock()
+
+			return s
+		}
+
+		select {
+		case <-changed:
+			continue
+		case <-s.exited:
+			// Output written just before exiting may still be in flight
+			time.Sleep(s.config.RetryPollInterval)
+			if s.pendingPasses(checkers) {
+				return s
+			}
+
+			s.fail(checkers, fmt.Sprintf("Actual: the program exited (%s)", s.exitStatus()))
+		case <-timer.C:
+			s.fail(checkers, fmt.Sprintf("Waited: %s", s.timeout))
+		case <-s.ctx.Done():
+			panic(fmt.Sprintf("%s\n  Cancelled while waiting for output", s.describe()))
+		}
+	}
+}
+
+// pendingPasses checks the unconsumed output once more, consuming it if it
+// passes.
+func (s *InteractiveSession) pendingPasses(checkers []Checker[string]) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkAll(normalizeTerminalOutput(s.output[s.consumed:]), checkers, nil) {
+		return false
+	}
+
+	s.consumed, s.sent = len(s.output), nil
+	return true
+}
+
+// ExpectExit waits until the program exits, then checks its exit code.
+func (s *InteractiveSession) ExpectExit(checkers ...Checker[int]) *InteractiveSession {
+	select {
+	case <-s.exited:
+	case <-time.After(s.timeout):
+		panic(fmt.Sprintf("%s\n  Expected the program to exit\n  Actual: still running after %s%s",
+			s.describe(), s.timeout, s.formatPending()))
+	case <-s.ctx.Done():
+		panic(fmt.Sprintf("%s\n  Cancelled while waiting for the program to exit", s.describe()))
+	}
+
+	code := s.cmd.ProcessState.ExitCode()
+	msg := mismatch(code, checkers, "exit code", fmt.Sprintf("Actual exit code: %d", code))
+	if msg != "" {
+		panic(fmt.Sprintf("%s\n  %s%s", s.describe(), msg, s.formatPending()))
+	}
+
+	return s
+}
+
+// Close kills the program and everything it started.
+func (s *InteractiveSession) Close() {
+	select {
+	case <-s.exited:
+	default:
+		err := syscall.Kill(-s.cmd.Process.Pid, syscall.SIGKILL)
+		if err != nil && !errors.Is(err, syscall.ESRCH) {
+			fmt.Println(red("Error killing interactive session"), red(s.name))
+		}
+
+		<-s.exited
+	}
+
+	s.ptmx.Close()
+}
+
+// fail reports an unmet expectation on the output.
+func (s *InteractiveSession) fail(checkers []Checker[string], detail string) {
+	s.mu.Lock()
+	pending := normalizeTerminalOutput(s.output[s.consumed:])
+	s.mu.Unlock()
+
+	msg := bodyMismatch(pending, checkers, "output", s.config)
+	panic(fmt.Sprintf("%s\n  %s\n  %s", s.describe(), msg, detail))
+}
+
+// describe names the session and the inputs sent since the last met expectation.
+func (s *InteractiveSession) describe() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := fmt.Sprintf("Interactive %s (%s)", s.name, strings.Join(append([]string{s.config.Command}, s.cmd.Args[1:]...), " "))
+	if len(s.sent) > 0 {
+		msg += fmt.Sprintf("\n  Sent: %q", truncateInput([]byte(strings.Join(s.sent, ""))))
+	}
+
+	return msg
+}
+
+// formatPending renders the unconsumed output, if any.
+func (s *InteractiveSession) formatPending() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := normalizeTerminalOutput(s.output[s.consumed:])
+	if pending == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("\n  Output: %q", pending)
+}
+
+// exitStatus describes how the program exited.
+func (s *InteractiveSession) exitStatus() string {
+	if s.exitErr != nil {
+		return s.exitErr.Error()
+	}
+
+	return "exit status 0"
+}
+
+// normalizeTerminalOutput turns the terminal's line endings into "\n".
+func normalizeTerminalOutput(output []byte) string {
+	return strings.ReplaceAll(string(output), "\r\n", "\n")
+}
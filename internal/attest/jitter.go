@@ -0,0 +1,43 @@
+package attest
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// jitter delays each outgoing request by a random duration so implementations
+// that only pass because operations arrive in lockstep get flushed out.
+// Delays come from a seeded generator so a failing run can be reproduced.
+type jitter struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+	max time.Duration
+}
+
+// newJitter creates jitter of up to max per request, drawn from seed.
+// It returns nil, which never delays, if max is not positive.
+func newJitter(max time.Duration, seed uint64) *jitter {
+	if max <= 0 {
+		return nil
+	}
+
+	return &jitter{rng: rand.New(rand.NewPCG(seed, seed)), max: max}
+}
+
+// wait blocks for the next random delay or until the context is cancelled.
+func (j *jitter) wait(ctx context.Context) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	delay := time.Duration(j.rng.Int64N(int64(j.max)))
+	j.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
@@ -0,0 +1,94 @@
+package attest
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Limit caps a resource of a process started with StartWith, e.g.
+// do.StartWith("node", []StartOption{WithMemoryLimit(256 << 20)}). Limits
+// are set with rlimits, so they're enforced on processes the harness runs
+// itself, not in-process servers or processes started manually. They apply
+// to everything the command runs, so a run.sh that builds the server first,
+// e.g. with go run, builds under them too; build ahead of time to leave the
+// whole limit to the server.
+type Limit struct {
+	memory int64
+	cpu    time.Duration
+	disk   int64
+}
+
+// WithMemoryLimit caps the memory the process can allocate at bytes. Going
+// over it makes allocations fail, which most runtimes exit on, with an error
+// status or after SIGSEGV or SIGABRT. Any such exit is reported against the
+// limit.
+func WithMemoryLimit(bytes int64) Limit {
+	if bytes <= 0 {
+		panic(fmt.Sprintf("WithMemoryLimit() requires a positive limit, got %d", bytes))
+	}
+
+	return Limit{memory: bytes}
+}
+
+// WithCPULimit caps the CPU time the process can use in total at d,
+// rounded up to a second, after which it's killed with SIGXCPU.
+func WithCPULimit(d time.Duration) Limit {
+	if d <= 0 {
+		panic(fmt.Sprintf("WithCPULimit() requires a positive limit, got %s", d))
+	}
+
+	return Limit{cpu: d}
+}
+
+// WithDiskLimit caps the space of the process's DataDir at bytes, by
+// mounting a tmpfs of that size there before it first starts, so FillDisk
+// can fill it. Mounting needs root; elsewhere the DataDir is left as is.
+func WithDiskLimit(bytes int64) Limit {
+	if bytes <= 0 {
+		panic(fmt.Sprintf("WithDiskLimit() requires a positive limit, got %d", bytes))
+	}
+
+	return Limit{disk: bytes}
+}
+
+// merge returns the limits of both, with l's taking precedence.
+func (l Limit) merge(other Limit) Limit {
+	if l.memory == 0 {
+		l.memory = other.memory
+	}
+	if l.cpu == 0 {
+		l.cpu = other.cpu
+	}
+	if l.disk == 0 {
+		l.disk = other.disk
+	}
+
+	return l
+}
+
+func (l Limit) apply(_ string, proc *Process) {
+	proc.limits = l.merge(proc.limits)
+}
+
+// set reports whether there are rlimits to set.
+func (l Limit) set() bool {
+	return l.memory > 0 || l.cpu > 0
+}
+
+// command returns a command that runs name with args under the limits, by
+// setting them in a shell that then replaces itself with the process.
+func (l Limit) command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	var script []string
+	if l.memory > 0 {
+		script = append(script, fmt.Sprintf("ulimit -d %d", (l.memory+1023)/1024))
+	}
+	if l.cpu > 0 {
+		script = append(script, fmt.Sprintf("ulimit -t %d", int64((l.cpu+time.Second-1)/time.Second)))
+	}
+	script = append(script, `exec "$0" "$@"`)
+
+	return exec.CommandContext(ctx, "/bin/sh", append([]string{"-c", strings.Join(script, " && "), name}, args...)...)
+}
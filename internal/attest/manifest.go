@@ -0,0 +1,125 @@
+package attest
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+// manifestFile describes a run so its working directory is self-describing,
+// e.g. when it's shared in a support request.
+const manifestFile = "manifest.json"
+
+// Version is the version of lsfr, from the build info unless it's set at
+// link time with -X.
+var Version = buildVersion()
+
+// buildVersion returns the version of the main module, e.g. v0.4.0 when
+// installed with go install, or "dev" for local builds.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" || info.Main.Version == "(devel)" {
+		return "dev"
+	}
+
+	return info.Main.Version
+}
+
+// Manifest describes a run and the files it left in its working directory.
+type Manifest struct {
+	Version   string         `json:"version"`
+	Challenge string         `json:"challenge,omitempty"`
+	Stage     string         `json:"stage,omitempty"`
+	Variant   map[string]any `json:"variant,omitempty"`
+	// Seed is the seed request delays were drawn from, if jittered
+	Seed     uint64         `json:"seed,omitempty"`
+	Started  time.Time      `json:"started"`
+	Duration string         `json:"duration"`
+	Passed   bool           `json:"passed"`
+	Config   manifestConfig `json:"config"`
+	Files    []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a file in a run's working directory.
+type ManifestFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// manifestConfig is a snapshot of the Config a run used, after scaling, with
+// readable durations.
+type manifestConfig struct {
+	Command                string   `json:"command"`
+	ProcessStartTimeout    string   `json:"process_start_timeout"`
+	ProcessShutdownTimeout string   `json:"process_shutdown_timeout"`
+	ProcessRestartDelay    string   `json:"process_restart_delay"`
+	DefaultRetryTimeout    string   `json:"default_retry_timeout"`
+	RetryPollInterval      string   `json:"retry_poll_interval"`
+	ExecuteTimeout         string   `json:"execute_timeout"`
+	TimeoutMultiplier      float64  `json:"timeout_multiplier,omitempty"`
+	MaxRequestsPerSecond   int      `json:"max_requests_per_second"`
+	ProcessArgs            []string `json:"process_args,omitempty"`
+	Jitter                 string   `json:"jitter,omitempty"`
+	MaxBodyLength          int      `json:"max_body_length"`
+	LineWidth              int      `json:"line_width,omitempty"`
+}
+
+// writeManifest writes the run's manifest to its working directory. It's
+// written last, so the file inventory includes everything else the run left.
+func (do *Do) writeManifest(manifest Manifest) error {
+	manifest.Version = Version
+	manifest.Variant = do.variant
+
+	config := do.config
+	manifest.Config = manifestConfig{
+		Command:                config.Command,
+		ProcessStartTimeout:    config.ProcessStartTimeout.String(),
+		ProcessShutdownTimeout: config.ProcessShutdownTimeout.String(),
+		ProcessRestartDelay:    config.ProcessRestartDelay.String(),
+		DefaultRetryTimeout:    config.DefaultRetryTimeout.String(),
+		RetryPollInterval:      config.RetryPollInterval.String(),
+		ExecuteTimeout:         config.ExecuteTimeout.String(),
+		TimeoutMultiplier:      config.TimeoutMultiplier,
+		MaxRequestsPerSecond:   config.MaxRequestsPerSecond,
+		ProcessArgs:            do.processArgs,
+		MaxBodyLength:          config.MaxBodyLength,
+		LineWidth:              config.LineWidth,
+	}
+
+	if do.jitter != nil && do.jitter.max > 0 {
+		manifest.Config.Jitter = do.jitter.max.String()
+	}
+
+	manifest.Files = []ManifestFile{}
+	err := filepath.WalkDir(do.workingDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || entry.Type()&fs.ModeSocket != 0 {
+			return err
+		}
+
+		rel, err := filepath.Rel(do.workingDir, path)
+		if err != nil || rel == manifestFile {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, ManifestFile{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(do.workingDir, manifestFile), append(data, '\n'), 0644)
+}
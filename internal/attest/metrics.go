@@ -0,0 +1,143 @@
+package attest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+)
+
+// metrics counts the work done by the harness itself, which helps tell
+// whether a slow run is down to the server under test or the machine.
+type metrics struct {
+	assertions atomic.Int64
+	// requests counts every request issued, including retries
+	requests atomic.Int64
+	// retries counts the attempts after the first of Eventually assertions
+	retries   atomic.Int64
+	processes atomic.Int64
+
+	bytesSent     atomic.Int64
+	bytesReceived atomic.Int64
+}
+
+// retried wraps execute to count the attempts after the first as retries.
+func (m *metrics) retried(execute func() bool) func() bool {
+	attempts := 0
+	return func() bool {
+		attempts++
+		if attempts > 1 {
+			m.retries.Add(1)
+		}
+
+		return execute()
+	}
+}
+
+// conn wraps conn to count the bytes transferred over it.
+func (m *metrics) conn(conn net.Conn) net.Conn {
+	return &countingConn{Conn: conn, metrics: m}
+}
+
+// transport wraps next to count the bytes of request and response bodies.
+func (m *metrics) transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil && req.Body != http.NoBody {
+			req.Body = &countingBody{ReadCloser: req.Body, count: &m.bytesSent}
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Body = &countingBody{ReadCloser: resp.Body, count: &m.bytesReceived}
+		return resp, nil
+	})
+}
+
+// String summarizes the counters for the end of a run.
+func (m *metrics) String() string {
+	return fmt.Sprintf(Marks.render("%d requests (%d retries) · %d processes started · %s sent · %s received"),
+		m.requests.Load(), m.retries.Load(), m.processes.Load(),
+		formatBytes(m.bytesSent.Load()), formatBytes(m.bytesReceived.Load()))
+}
+
+// writeJSON writes the counters to path so other tools can pick them up.
+func (m *metrics) writeJSON(path string) error {
+	data, err := json.MarshalIndent(map[string]int64{
+		"assertions":        m.assertions.Load(),
+		"requests":          m.requests.Load(),
+		"retries":           m.retries.Load(),
+		"processes_started": m.processes.Load(),
+		"bytes_sent":        m.bytesSent.Load(),
+		"bytes_received":    m.bytesReceived.Load(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// formatBytes renders n using binary units, e.g. 1.5 KiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// countingConn counts the bytes read from and written to a connection.
+type countingConn struct {
+	net.Conn
+	metrics *metrics
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.metrics.bytesReceived.Add(int64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.metrics.bytesSent.Add(int64(n))
+	return n, err
+}
+
+// CloseWrite half-closes the connection if it supports it.
+func (c *countingConn) CloseWrite() error {
+	if halfCloser, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return halfCloser.CloseWrite()
+	}
+
+	return fmt.Errorf("%T doesn't support half-closing", c.Conn)
+}
+
+// countingBody counts the bytes read from a request or response body.
+type countingBody struct {
+	io.ReadCloser
+	count *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.count.Add(int64(n))
+	return n, err
+}
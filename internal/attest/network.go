@@ -0,0 +1,407 @@
+package attest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// network routes traffic between processes through proxies the harness
+// controls, one per direction of each link, so it can partition them, slow
+// them down and throttle them the same way whatever the implementation.
+type network struct {
+	mu sync.Mutex
+	// resolve finds a process by name when a connection to it is made
+	resolve func(name string) (*Process, bool)
+	links   map[[2]string]*link
+	// groups assigns partitioned processes to sides, 0 being everyone else
+	groups map[string]int
+	// faults are injected into traffic to and from each process
+	faults map[string]faults
+}
+
+// faults degrade a process's links.
+type faults struct {
+	latency time.Duration
+	// loss is the chance of losing each connection attempt and each chunk
+	loss float64
+	// throttle caps the bytes per second sent over all its links, if set
+	throttle *throttle
+}
+
+// linkFaults are the faults of both ends of a link combined.
+type linkFaults struct {
+	latency   time.Duration
+	loss      float64
+	throttles []*throttle
+}
+
+// chunkSize returns how much to forward at once, little enough when the
+// link is throttled that the rate stays smooth.
+func (f linkFaults) chunkSize(limit int) int {
+	size := limit
+	for _, t := range f.throttles {
+		size = min(size, max(t.rate/20, 512))
+	}
+
+	return size
+}
+
+// throttle spaces out sends so they average at most rate bytes per second.
+type throttle struct {
+	mu   sync.Mutex
+	rate int
+	// next is when the bytes reserved so far will have been sent
+	next time.Time
+}
+
+// reserve schedules sending n bytes and returns how long to wait until
+// they've been sent at the throttled rate.
+func (t *throttle) reserve(n int) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.next = later(t.next, now).Add(time.Duration(n) * time.Second / time.Duration(t.rate))
+	return t.next.Sub(now)
+}
+
+// later returns the later of two times.
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+// retransmitDelay is how much later a lost chunk arrives, as TCP would
+// resend it rather than lose it.
+const retransmitDelay = 200 * time.Millisecond
+
+// link is the proxy that carries connections from one process to another.
+type link struct {
+	from, to string
+	listener net.Listener
+	// conns holds both ends of every open connection, guarded by network.mu
+	conns map[net.Conn]struct{}
+}
+
+func newNetwork(resolve func(name string) (*Process, bool)) *network {
+	return &network{
+		resolve: resolve,
+		links:   make(map[[2]string]*link),
+		groups:  make(map[string]int),
+		faults:  make(map[string]faults),
+	}
+}
+
+// address returns the address of the proxy from from to to, starting it the
+// first time it's needed. The process behind to is looked up on each
+// connection, so it may not have started yet and may restart on a new port.
+func (n *network) address(from, to string) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if l, ok := n.links[[2]string{from, to}]; ok {
+		return l.listener.Addr().String(), nil
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+
+	l := &link{from: from, to: to, listener: listener, conns: make(map[net.Conn]struct{})}
+	n.links[[2]string{from, to}] = l
+	go n.serve(l)
+
+	return listener.Addr().String(), nil
+}
+
+// connected reports whether from can reach to. The caller holds n.mu.
+func (n *network) connected(from, to string) bool {
+	return n.groups[from] == n.groups[to]
+}
+
+// linkFaults combines the faults of both ends of a link.
+func (n *network) linkFaults(l *link) linkFaults {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	from, to := n.faults[l.from], n.faults[l.to]
+	f := linkFaults{
+		latency: from.latency + to.latency,
+		loss:    1 - (1-from.loss)*(1-to.loss),
+	}
+	for _, t := range []*throttle{from.throttle, to.throttle} {
+		if t != nil {
+			f.throttles = append(f.throttles, t)
+		}
+	}
+
+	return f
+}
+
+func (n *network) serve(l *link) {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go n.forward(l, conn)
+	}
+}
+
+// forward carries conn to the link's target until either side closes it, or
+// refuses it if the two processes are partitioned or the attempt is lost.
+func (n *network) forward(l *link, conn net.Conn) {
+	n.mu.Lock()
+	cut := !n.connected(l.from, l.to)
+	n.mu.Unlock()
+
+	lost := rand.Float64() < n.linkFaults(l).loss
+	proc, ok := n.resolve(l.to)
+	if cut || lost || !ok || proc.network() == "udp" {
+		conn.Close()
+		return
+	}
+
+	upstream, err := net.Dial(proc.network(), proc.address())
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	// The processes may have been partitioned while dialing
+	n.mu.Lock()
+	if !n.connected(l.from, l.to) {
+		n.mu.Unlock()
+		conn.Close()
+		upstream.Close()
+		return
+	}
+	l.conns[conn] = struct{}{}
+	l.conns[upstream] = struct{}{}
+	n.mu.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Go(func() { n.pipe(l, upstream, conn) })
+	wg.Go(func() { n.pipe(l, conn, upstream) })
+	wg.Wait()
+
+	conn.Close()
+	upstream.Close()
+
+	n.mu.Lock()
+	delete(l.conns, conn)
+	delete(l.conns, upstream)
+	n.mu.Unlock()
+}
+
+// chunk is data read from one end of a link, due at the other end at due.
+type chunk struct {
+	data []byte
+	due  time.Time
+}
+
+// pipe copies src to dst. Each chunk is due the time it takes to send at a
+// throttled rate after it's read, plus the link's latency and, if it's
+// lost, the time it takes to resend. Chunks are queued and delivered in
+// order once due, so latency delays data without limiting how much is in
+// flight. Faults changed while the connection is open apply to the next
+// chunk. Once src ends, dst is closed for writing so the other side sees
+// the end of the stream while still being able to respond.
+func (n *network) pipe(l *link, dst, src net.Conn) {
+	queue := make(chan chunk, 64)
+	done := make(chan struct{})
+	go func() {
+		defer close(queue)
+
+		for {
+			buf := make([]byte, n.linkFaults(l).chunkSize(32*1024))
+			read, err := src.Read(buf)
+			if read > 0 {
+				f := n.linkFaults(l)
+				delay := f.latency
+				if rand.Float64() < f.loss {
+					delay += retransmitDelay
+				}
+
+				// A link is as slow as the slower of its ends
+				var sending time.Duration
+				for _, t := range f.throttles {
+					sending = max(sending, t.reserve(read))
+				}
+
+				select {
+				case queue <- chunk{data: buf[:read], due: time.Now().Add(sending + delay)}:
+				case <-done:
+					return
+				}
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for c := range queue {
+		time.Sleep(time.Until(c.due))
+
+		_, err := dst.Write(c.data)
+		if err != nil {
+			// src is closed once both directions end, which stops the reader
+			close(done)
+			break
+		}
+	}
+
+	if c, ok := dst.(interface{ CloseWrite() error }); ok {
+		c.CloseWrite()
+	} else {
+		dst.Close()
+	}
+}
+
+// partition puts names on a side of their own and closes the connections
+// between processes that can no longer reach each other.
+func (n *network) partition(names []string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	group := 1
+	for _, g := range n.groups {
+		group = max(group, g+1)
+	}
+
+	for _, name := range names {
+		n.groups[name] = group
+	}
+
+	for _, l := range n.links {
+		if !n.connected(l.from, l.to) {
+			for conn := range l.conns {
+				conn.Close()
+			}
+		}
+	}
+}
+
+// heal reconnects every process and removes their faults.
+func (n *network) heal() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	clear(n.groups)
+	clear(n.faults)
+}
+
+// degrade changes the faults injected into a process's links.
+func (n *network) degrade(name string, change func(f *faults)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f := n.faults[name]
+	change(&f)
+	n.faults[name] = f
+}
+
+// close stops every proxy and closes its connections.
+func (n *network) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, l := range n.links {
+		l.listener.Close()
+		for conn := range l.conns {
+			conn.Close()
+		}
+	}
+}
+
+// PeerAddress returns the address process from should use to connect to
+// process to, e.g. in the --peers it's started with. It's a proxy the
+// harness controls, so Partition can cut the two off from each other.
+// Only TCP processes can be reached through it.
+func (do *Do) PeerAddress(from, to string) string {
+	addr, err := do.network.address(from, to)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to start proxy from %s to %s: %v", from, to, err))
+	}
+
+	return addr
+}
+
+// Partition cuts the named processes off from every other process, while
+// they can still reach each other. Connections between the two sides made
+// through PeerAddress are closed, and new ones are refused until Heal or
+// the end of the test.
+// Calling it again splits off another side, e.g. Partition("node-1") then
+// Partition("node-2") leaves three. The harness's own requests, e.g. do.HTTP,
+// are unaffected.
+func (do *Do) Partition(names ...string) {
+	if len(names) == 0 {
+		panic("Partition() requires at least one process")
+	}
+
+	do.breadcrumbs.add("PARTITION %s", strings.Join(slices.Sorted(slices.Values(names)), ", "))
+	do.network.partition(names)
+}
+
+// AddLatency delays traffic to and from the named process through
+// PeerAddress proxies by d more in each direction, on top of any latency
+// added before. A link between two slowed processes is delayed by both.
+func (do *Do) AddLatency(name string, d time.Duration) {
+	if d < 0 {
+		panic(fmt.Sprintf("AddLatency() requires a duration >= 0, got %s", d))
+	}
+
+	do.breadcrumbs.add("LATENCY %s +%s", name, d)
+	do.network.degrade(name, func(f *faults) { f.latency += d })
+}
+
+// DropPackets makes the named process's links through PeerAddress proxies
+// lossy. It drops connections rather than data: each connection attempt is
+// refused with probability rate. Data on an open connection is never lost,
+// as TCP would resend it, so each chunk arrives late with probability rate
+// instead.
+func (do *Do) DropPackets(name string, rate float64) {
+	if rate < 0 || rate > 1 {
+		panic(fmt.Sprintf("DropPackets() requires a rate between 0 and 1, got %v", rate))
+	}
+
+	do.breadcrumbs.add("DROP %s %.0f%%", name, rate*100)
+	do.network.degrade(name, func(f *faults) { f.loss = rate })
+}
+
+// Throttle caps the bytes per second the named process sends and receives,
+// over all its links through PeerAddress proxies together, e.g. to slow
+// down replication or snapshot transfers. A rate of 0 removes the cap.
+func (do *Do) Throttle(name string, bytesPerSecond int) {
+	if bytesPerSecond < 0 {
+		panic(fmt.Sprintf("Throttle() requires a rate >= 0, got %d", bytesPerSecond))
+	}
+
+	do.breadcrumbs.add("THROTTLE %s %d B/s", name, bytesPerSecond)
+	do.network.degrade(name, func(f *faults) {
+		f.throttle = nil
+		if bytesPerSecond > 0 {
+			f.throttle = &throttle{rate: bytesPerSecond}
+		}
+	})
+}
+
+// Heal undoes every Partition and removes latency, packet loss and
+// throttling, so all processes can reach each other normally again. The
+// network is healed after every test too.
+func (do *Do) Heal() {
+	do.breadcrumbs.add("HEAL")
+	do.network.heal()
+}
@@ -0,0 +1,52 @@
+package attest
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Operation is an HTTP operation and the response the system under test gave.
+type Operation struct {
+	Method string
+	Path   string
+	Body   string
+
+	Status   int
+	Response string
+
+	// Concurrent is set for operations issued inside Concurrently, whose
+	// order relative to each other isn't known
+	Concurrent bool
+}
+
+// Oracle is a known-good model of the system under test. In oracle mode every
+// HTTP operation is mirrored to it so the run fails at the first operation
+// where the system's responses diverge from the model's.
+type Oracle interface {
+	// Check applies the operation to the model and describes how the response
+	// differs from the model's, or returns "" if it doesn't or isn't modeled.
+	Check(op Operation) string
+}
+
+// shadowOracle numbers the operations mirrored to an oracle.
+type shadowOracle struct {
+	mu         sync.Mutex
+	model      Oracle
+	operations int
+}
+
+// mirror checks the operation against the model and returns a failure
+// message naming the operation if they diverge.
+func (o *shadowOracle) mirror(op Operation) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.operations++
+	divergence := o.model.Check(op)
+	if divergence == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("Your server diverged from the reference model at operation #%d: %s %s\n  %s",
+		o.operations, op.Method, op.Path, divergence)
+}
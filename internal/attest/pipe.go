@@ -0,0 +1,117 @@
+package attest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Pipe creates a deferred pipeline of commands, connecting the stdout of each
+// to the stdin of the next the way a shell does, e.g.
+// do.Pipe(do.Exec("cat", "words.txt"), do.Exec("sort"), do.Exec("uniq")).
+// Each command keeps its own directory and environment, and only the first
+// one's stdin is used. The output and exit code checked are the last
+// command's, as in a shell without pipefail.
+func (do *Do) Pipe(commands ...*CLIPromise) *CLIPromise {
+	if len(commands) < 2 {
+		panic("Pipe() needs at least two commands")
+	}
+
+	for _, c := range commands {
+		if len(c.pipeline) > 0 || len(c.signals) > 0 {
+			panic("Pipe() can't take pipelines or commands with SignalWhen()")
+		}
+	}
+
+	last := commands[len(commands)-1]
+	return &CLIPromise{
+		PromiseBase: do.newPromiseBase(),
+
+		command:  last.command,
+		runDir:   do.workingDir,
+		pipeline: commands,
+	}
+}
+
+// commandLine renders the command, or each command of a pipeline, for breadcrumbs.
+func (p *CLIPromise) commandLine() string {
+	if len(p.pipeline) == 0 {
+		return formatCommand(append([]string{p.command}, p.args...))
+	}
+
+	stages := make([]string, len(p.pipeline))
+	for i, stage := range p.pipeline {
+		stages[i] = stage.commandLine()
+	}
+
+	return strings.Join(stages, " | ")
+}
+
+// outputPipeline runs the pipeline like Output runs a single command,
+// returning the last command's stdout and error.
+func (p *CLIPromise) outputPipeline(ctx context.Context) ([]byte, error) {
+	cmds := make([]*exec.Cmd, len(p.pipeline))
+	for i, stage := range p.pipeline {
+		cmds[i] = stage.build(ctx)
+	}
+
+	// The parent's copies of the pipe ends are closed once the commands have
+	// started, so each command sees EOF when the one before it exits
+	var pipeEnds []*os.File
+	defer func() {
+		for _, f := range pipeEnds {
+			f.Close()
+		}
+	}()
+
+	for i := range len(cmds) - 1 {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return nil, err
+		}
+		pipeEnds = append(pipeEnds, r, w)
+
+		cmds[i].Stdout = w
+		cmds[i+1].Stdin = r
+	}
+
+	last := cmds[len(cmds)-1]
+	var stdout, stderr bytes.Buffer
+	last.Stdout, last.Stderr = &stdout, &stderr
+
+	for i, cmd := range cmds {
+		err := cmd.Start()
+		if err != nil {
+			for _, started := range cmds[:i] {
+				started.Process.Kill()
+				started.Wait()
+			}
+
+			return nil, fmt.Errorf("failed to start %s: %w", p.pipeline[i].commandLine(), err)
+		}
+	}
+
+	for _, f := range pipeEnds {
+		f.Close()
+	}
+	pipeEnds = nil
+
+	var err error
+	for i, cmd := range cmds {
+		waitErr := cmd.Wait()
+		if i == len(cmds)-1 {
+			err = waitErr
+		}
+	}
+
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		exitError.Stderr = stderr.Bytes()
+	}
+
+	return stdout.Bytes(), err
+}
@@ -0,0 +1,121 @@
+package attest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PortPassing is how a process is told the port or socket to listen on,
+// its extra ports and its data directory.
+type PortPassing int
+
+const (
+	// PortFlag passes them as arguments, e.g. --port=43215,
+	// --working-dir=<dir> and --peer-port=43216.
+	PortFlag PortPassing = iota
+	// PortEnv passes them as environment variables, e.g. PORT=43215,
+	// WORKING_DIR=<dir> and PEER_PORT=43216.
+	PortEnv
+	// PortFile writes them to portsFile in the data directory, e.g.
+	// {"peer_port": 43216, "port": 43215, "working_dir": "<dir>"}, which
+	// is passed as --working-dir=<dir>.
+	PortFile
+)
+
+// portsFile is the file PortFile writes in each process's data directory.
+const portsFile = "ports.json"
+
+// ParsePortPassing parses a convention by name: flag, env or file.
+func ParsePortPassing(name string) (PortPassing, error) {
+	switch name {
+	case "", "flag":
+		return PortFlag, nil
+	case "env":
+		return PortEnv, nil
+	case "file":
+		return PortFile, nil
+	default:
+		return PortFlag, fmt.Errorf("Unknown port convention %q, expected flag, env or file", name)
+	}
+}
+
+func (p PortPassing) String() string {
+	switch p {
+	case PortFlag:
+		return "flag"
+	case PortEnv:
+		return "env"
+	case PortFile:
+		return "file"
+	default:
+		return fmt.Sprintf("PortPassing(%d)", int(p))
+	}
+}
+
+// WithPortPassing sets how processes are told their ports, PortFlag by
+// default, for implementations that read them from elsewhere.
+func (s *Suite) WithPortPassing(p PortPassing) *Suite {
+	s.portPassing = p
+	return s
+}
+
+// setting is a value pass tells the process, keyed in snake_case.
+type setting struct {
+	key   string
+	value any
+}
+
+// pass returns the arguments and environment variables that tell the
+// process where to listen and keep its data in dataDir, writing portsFile
+// there first for PortFile.
+func (p PortPassing) pass(proc *Process, dataDir string) ([]string, []string, error) {
+	var settings []setting
+	if proc.socketPath != "" {
+		settings = append(settings, setting{"socket", proc.socketPath})
+	} else {
+		settings = append(settings, setting{"port", proc.realPort})
+	}
+	settings = append(settings, setting{"working_dir", dataDir})
+	for _, portName := range slices.Sorted(maps.Keys(proc.ports)) {
+		settings = append(settings, setting{strings.ReplaceAll(portName, "-", "_") + "_port", proc.ports[portName]})
+	}
+
+	switch p {
+	case PortEnv:
+		var env []string
+		for _, s := range settings {
+			env = append(env, fmt.Sprintf("%s=%v", strings.ToUpper(s.key), s.value))
+		}
+
+		return nil, env, nil
+	case PortFile:
+		values := make(map[string]any, len(settings))
+		for _, s := range settings {
+			values[s.key] = s.value
+		}
+
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = os.WriteFile(filepath.Join(dataDir, portsFile), append(data, '\n'), 0644)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []string{"--working-dir=" + dataDir}, nil, nil
+	default:
+		var args []string
+		for _, s := range settings {
+			args = append(args, fmt.Sprintf("--%s=%v", strings.ReplaceAll(s.key, "_", "-"), s.value))
+		}
+
+		return args, nil, nil
+	}
+}
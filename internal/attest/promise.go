@@ -1,7 +1,19 @@
 package attest
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 )
 
@@ -24,19 +36,45 @@ type Promise[P any, A any] interface {
 	Consistently() P
 	// For sets a custom timeout for Consistently operations.
 	For(time.Duration) P
+	// MinSamples sets how many times Consistently operations check at least.
+	MinSamples(int) P
 	// T creates an assertion to validate the operation's result.
 	T() A
 }
 
 var _ Promise[*HTTPPromise, *HTTPAssert] = (*HTTPPromise)(nil)
 var _ Promise[*CLIPromise, *CLIAssert] = (*CLIPromise)(nil)
+var _ Promise[*FuzzPromise, *FuzzAssert] = (*FuzzPromise)(nil)
+var _ Promise[*LogsPromise, *LogsAssert] = (*LogsPromise)(nil)
+var _ Promise[*TCPPromise, *TCPAssert] = (*TCPPromise)(nil)
+var _ Promise[*HTTPRawPromise, *HTTPRawAssert] = (*HTTPRawPromise)(nil)
+var _ Promise[*HTTPConnPromise, *HTTPConnAssert] = (*HTTPConnPromise)(nil)
+var _ Promise[*UDPPromise, *UDPAssert] = (*UDPPromise)(nil)
+var _ Promise[*DNSPromise, *DNSAssert] = (*DNSPromise)(nil)
+var _ Promise[*WSPromise, *WSAssert] = (*WSPromise)(nil)
+var _ Promise[*GRPCPromise, *GRPCAssert] = (*GRPCPromise)(nil)
+var _ Promise[*RESPPromise, *RESPAssert] = (*RESPPromise)(nil)
+var _ Promise[*SSEPromise, *SSEAssert] = (*SSEPromise)(nil)
+var _ Promise[*FDPromise, *FDAssert] = (*FDPromise)(nil)
 
 // PromiseBase provides common promise functionality.
 type PromiseBase struct {
 	timing  timing
 	timeout time.Duration
+	// minSamples is the least number of samples Consistently takes
+	minSamples int
+	// executeTimeout overrides the configured ExecuteTimeout for each attempt, if it's set
+	executeTimeout time.Duration
 
-	ctx context.Context
+	ctx      context.Context
+	limiter  *rateLimiter
+	jitter   *jitter
+	trace    *traceLog
+	watchdog *watchdog
+
+	breadcrumbs *breadcrumbs
+	// metrics counts the work done by the suite
+	metrics *metrics
 
 	config *Config
 }
@@ -51,7 +89,7 @@ func (b *PromiseBase) setWithin(timeout time.Duration) {
 		panic("Within() can only be called after Eventually()")
 	}
 
-	b.timeout = timeout
+	b.timeout = scaleDuration(timeout, b.config.TimeoutMultiplier)
 }
 
 func (b *PromiseBase) setConsistently() {
@@ -67,6 +105,67 @@ func (b *PromiseBase) setFor(timeout time.Duration) {
 	b.timeout = timeout
 }
 
+func (b *PromiseBase) setMinSamples(n int) {
+	if b.timing != TimingConsistently {
+		panic("MinSamples() can only be called after Consistently()")
+	}
+
+	if n < 1 {
+		panic("MinSamples() requires at least one sample")
+	}
+
+	b.minSamples = n
+}
+
+func (b *PromiseBase) setExecuteTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		panic("WithTimeout() requires a positive timeout")
+	}
+
+	b.executeTimeout = scaleDuration(timeout, b.config.TimeoutMultiplier)
+}
+
+// attemptTimeout returns how long a single attempt of the operation may take.
+func (b *PromiseBase) attemptTimeout() time.Duration {
+	if b.executeTimeout > 0 {
+		return b.executeTimeout
+	}
+
+	return b.config.ExecuteTimeout
+}
+
+// issue is called just before a request goes out. It counts the request and
+// waits for the rate limiter and any jitter.
+func (b *PromiseBase) issue() {
+	b.metrics.requests.Add(1)
+	b.limiter.wait(b.ctx)
+	b.jitter.wait(b.ctx)
+}
+
+// run executes the operation according to the promise's timing. If a
+// Consistently check fails, it returns how it was sampled for the message.
+func (b *PromiseBase) run(execute func() bool) string {
+	b.metrics.assertions.Add(1)
+
+	switch b.timing {
+	case TimingEventually:
+		eventually(b.ctx, b.metrics.retried(execute), b.timeout, b.config.RetryPollInterval)
+	case TimingConsistently:
+		minSamples := max(b.minSamples, 1)
+
+		start := time.Now()
+		samples, ok := consistently(b.ctx, execute, b.timeout, b.config.RetryPollInterval, minSamples)
+		if !ok {
+			return fmt.Sprintf("Consistently: sample %d of at least %d failed after %s",
+				samples, minSamples, time.Since(start).Round(time.Millisecond))
+		}
+	default:
+		execute()
+	}
+
+	return ""
+}
+
 // H is a convenience type for HTTP headers.
 type H map[string]string
 
@@ -74,10 +173,252 @@ type H map[string]string
 type HTTPPromise struct {
 	PromiseBase
 
-	method  string
-	url     string
-	headers H
-	body    []byte
+	method     string
+	path       string
+	url        string
+	socketPath string
+	tls        bool
+	http2      bool
+	// ports are the process's extra ports, by name
+	ports map[string]int
+	// noRedirects returns redirect responses instead of following them
+	noRedirects bool
+	headers     H
+	body        []byte
+	// bodyReader and bodyFile replace body when set
+	bodyReader io.Reader
+	bodyFile   string
+	// bodyRead is set once bodyReader has been sent, as it can't be sent again
+	bodyRead bool
+	// form holds the parts of a multipart/form-data body, encoded into body
+	form []formPart
+
+	sessions *cookieSessions
+	jar      http.CookieJar
+
+	// process and exchanges identify requests being recorded or replayed
+	process   string
+	exchanges *exchanges
+	// last keeps the latest exchange in full, for failures
+	last *lastExchange
+
+	// oracle mirrors the request, which is concurrent if made inside Concurrently
+	oracle     *shadowOracle
+	concurrent bool
+}
+
+// TLS makes the request over HTTPS, e.g. to a process that wasn't started with StartTLS.
+func (p *HTTPPromise) TLS() *HTTPPromise {
+	p.tls = true
+	p.url = strings.Replace(p.url, "http://", "https://", 1)
+	return p
+}
+
+// OnPort sends the request to one of the process's extra ports instead of
+// its main one, e.g. OnPort("peer") for a process started with WithPort("peer").
+func (p *HTTPPromise) OnPort(name string) *HTTPPromise {
+	port, ok := p.ports[name]
+	if !ok {
+		panic(fmt.Sprintf("%s wasn't started with WithPort(%q)", p.process, name))
+	}
+
+	u, err := url.Parse(p.url)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid request URL %q: %v", p.url, err))
+	}
+	u.Host = fmt.Sprintf("127.0.0.1:%d", port)
+
+	// Extra ports are always TCP
+	p.socketPath = ""
+	p.url = u.String()
+	return p
+}
+
+// HTTP2 makes the request over HTTP/2 only, negotiated with ALPN over TLS
+// and with prior knowledge (h2c) over plaintext.
+func (p *HTTPPromise) HTTP2() *HTTPPromise {
+	p.http2 = true
+	return p
+}
+
+// NoFollowRedirects returns redirect responses as they are instead of
+// following them, so their status and Location can be checked.
+func (p *HTTPPromise) NoFollowRedirects() *HTTPPromise {
+	p.noRedirects = true
+	return p
+}
+
+// WithTimeout gives each attempt of the request up to timeout instead of
+// the configured ExecuteTimeout, e.g. for a slow snapshot. It's scaled like
+// the other timeouts.
+func (p *HTTPPromise) WithTimeout(timeout time.Duration) *HTTPPromise {
+	p.setExecuteTimeout(timeout)
+	return p
+}
+
+// Query adds a query parameter to the request URL, escaping it as needed.
+func (p *HTTPPromise) Query(key, value string) *HTTPPromise {
+	u, err := url.Parse(p.url)
+	if err != nil {
+		panic(fmt.Sprintf("Invalid request URL %q: %v", p.url, err))
+	}
+
+	query := u.Query()
+	query.Add(key, value)
+	u.RawQuery = query.Encode()
+
+	p.url = u.String()
+	return p
+}
+
+// Header sets a request header.
+func (p *HTTPPromise) Header(key, value string) *HTTPPromise {
+	if p.headers == nil {
+		p.headers = make(H)
+	}
+
+	p.headers[key] = value
+	return p
+}
+
+// Body sets the request body.
+func (p *HTTPPromise) Body(body string) *HTTPPromise {
+	p.setBody([]byte(body), nil, "")
+	return p
+}
+
+// BodyFromFile streams the file at path as the request body, reopening it
+// for every attempt, so large or binary files aren't loaded into memory.
+func (p *HTTPPromise) BodyFromFile(path string) *HTTPPromise {
+	p.setBody(nil, nil, path)
+	return p
+}
+
+func (p *HTTPPromise) setBody(body []byte, reader io.Reader, path string) {
+	p.body, p.bodyReader, p.bodyFile, p.bodyRead = body, reader, path, false
+	p.form = nil
+}
+
+// requestBody returns the body to send on the next attempt and its length,
+// or -1 if it isn't known up front.
+func (p *HTTPPromise) requestBody() (io.Reader, int64, error) {
+	switch {
+	case p.bodyFile != "":
+		file, err := os.Open(p.bodyFile)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+
+		return file, info.Size(), nil
+	case p.bodyReader != nil:
+		if seeker, ok := p.bodyReader.(io.Seeker); ok {
+			_, err := seeker.Seek(0, io.SeekStart)
+			return p.bodyReader, -1, err
+		}
+
+		if p.bodyRead {
+			return nil, 0, fmt.Errorf("request body from an io.Reader can't be sent again, use []byte or BodyFromFile to retry")
+		}
+
+		p.bodyRead = true
+		return p.bodyReader, -1, nil
+	default:
+		return bytes.NewReader(p.body), int64(len(p.body)), nil
+	}
+}
+
+// BodyJSON sets the request body to v encoded as JSON, along with the
+// Content-Type header unless it's already set.
+func (p *HTTPPromise) BodyJSON(v any) *HTTPPromise {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to encode request body: %v", err))
+	}
+
+	p.setBody(body, nil, "")
+	for key := range p.headers {
+		if strings.EqualFold(key, "Content-Type") {
+			return p
+		}
+	}
+
+	return p.Header("Content-Type", "application/json")
+}
+
+// formPart is a single field or file of a multipart/form-data body.
+type formPart struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+// FormField adds a field to the request's multipart/form-data body.
+func (p *HTTPPromise) FormField(name, value string) *HTTPPromise {
+	return p.addFormPart(formPart{field: name, content: []byte(value)})
+}
+
+// FormFile adds a file upload to the request's multipart/form-data body.
+func (p *HTTPPromise) FormFile(field, filename string, content []byte) *HTTPPromise {
+	return p.addFormPart(formPart{field: field, filename: filename, content: content})
+}
+
+// addFormPart re-encodes the body with the part added and sets the
+// Content-Type header, which must carry the body's boundary.
+func (p *HTTPPromise) addFormPart(part formPart) *HTTPPromise {
+	form := append(p.form, part)
+
+	// Derive the boundary from the parts so the same form always encodes
+	// to the same body, e.g. when recording and replaying
+	hash := sha256.New()
+	for _, part := range form {
+		fmt.Fprintf(hash, "%s\x00%s\x00%d\x00", part.field, part.filename, len(part.content))
+		hash.Write(part.content)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.SetBoundary(fmt.Sprintf("lsfr%x", hash.Sum(nil)[:16]))
+
+	for _, part := range form {
+		var w io.Writer
+		var err error
+		if part.filename != "" {
+			w, err = writer.CreateFormFile(part.field, part.filename)
+		} else {
+			w, err = writer.CreateFormField(part.field)
+		}
+		if err != nil {
+			panic(fmt.Sprintf("Failed to encode form: %v", err))
+		}
+
+		w.Write(part.content)
+	}
+	writer.Close()
+
+	p.setBody(body.Bytes(), nil, "")
+	p.form = form
+
+	for key := range p.headers {
+		if strings.EqualFold(key, "Content-Type") {
+			delete(p.headers, key)
+		}
+	}
+
+	return p.Header("Content-Type", writer.FormDataContentType())
+}
+
+// Session sends the request as part of the named session: cookies set by
+// earlier responses in the session are sent, and cookies set by this
+// response are kept for later requests.
+func (p *HTTPPromise) Session(name string) *HTTPPromise {
+	p.jar = p.sessions.jar(name)
+	return p
 }
 
 func (p *HTTPPromise) Eventually() *HTTPPromise {
@@ -100,10 +441,19 @@ func (p *HTTPPromise) For(timeout time.Duration) *HTTPPromise {
 	return p
 }
 
+func (p *HTTPPromise) MinSamples(n int) *HTTPPromise {
+	p.setMinSamples(n)
+	return p
+}
+
 func (p *HTTPPromise) T() *HTTPAssert {
 	return &HTTPAssert{
-		AssertBase: AssertBase{config: p.config},
-		promise:    p,
+		AssertBase:        AssertBase{config: p.config},
+		promise:           p,
+		traceID:           newTraceID(),
+		headerCheckers:    make(map[string][]Checker[string]),
+		cookieCheckers:    make(map[string][]Checker[string]),
+		setCookieCheckers: make(map[string][]Checker[string]),
 	}
 }
 
@@ -113,6 +463,125 @@ type CLIPromise struct {
 
 	command string
 	args    []string
+
+	// Input piped to the command, from stdinReader if it's set
+	stdin       []byte
+	stdinReader io.Reader
+	stdinRead   bool
+
+	// Variables set on top of the harness's environment, as KEY=VALUE
+	env []string
+
+	// dir is where the command runs, the harness's working directory if empty
+	dir    string
+	runDir string
+
+	// For commands OutputEventually leaves running
+	state     *processState
+	addStream func(*execStream)
+
+	// Signals sent to the command while it runs, in order
+	signals []signalStep
+
+	// Commands whose stdout is piped to the next one's stdin, if this is a pipeline
+	pipeline []*CLIPromise
+}
+
+// signalStep sends signal to a running command once its output passes checker.
+type signalStep struct {
+	checker Checker[string]
+	signal  syscall.Signal
+}
+
+// SignalWhen sends sig to the command once its stdout so far passes checker,
+// e.g. SignalWhen(Contains("ready"), syscall.SIGHUP). Chained calls are sent
+// in order, each waiting up to the default retry timeout for its output. The
+// assertion's checkers apply once the command exits.
+func (p *CLIPromise) SignalWhen(checker Checker[string], sig syscall.Signal) *CLIPromise {
+	p.signals = append(p.signals, signalStep{checker: checker, signal: sig})
+	return p
+}
+
+// WithTimeout gives each run of the command up to timeout instead of the
+// configured ExecuteTimeout. It's scaled like the other timeouts.
+func (p *CLIPromise) WithTimeout(timeout time.Duration) *CLIPromise {
+	p.setExecuteTimeout(timeout)
+	return p
+}
+
+// InDir runs the command in dir instead of the harness's working directory.
+// A relative dir is taken relative to the run's working directory, and dir
+// is created if it doesn't exist, e.g. InDir("repo-1") for a fresh directory
+// per test.
+func (p *CLIPromise) InDir(dir string) *CLIPromise {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(p.runDir, dir)
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create directory %s: %v", dir, err))
+	}
+
+	p.dir = dir
+	return p
+}
+
+// WithEnv sets environment variables, each as KEY=VALUE, for the command,
+// e.g. WithEnv("TZ=UTC", "LANG=C"). They override the variables the command
+// inherits from the harness, which is left unchanged.
+func (p *CLIPromise) WithEnv(vars ...string) *CLIPromise {
+	for _, v := range vars {
+		if !strings.Contains(v, "=") || strings.HasPrefix(v, "=") {
+			panic(fmt.Sprintf("invalid environment variable %q, expected KEY=VALUE", v))
+		}
+	}
+
+	p.env = append(p.env, vars...)
+	return p
+}
+
+// WithStdin pipes input, a string, []byte or io.Reader, to the command's
+// stdin. An io.Reader is rewound for every attempt if it's an io.Seeker,
+// otherwise it can only be read once.
+func (p *CLIPromise) WithStdin(input any) *CLIPromise {
+	p.stdin, p.stdinReader, p.stdinRead = nil, nil, false
+
+	switch in := input.(type) {
+	case string:
+		p.stdin = []byte(in)
+	case []byte:
+		p.stdin = in
+	case io.Reader:
+		p.stdinReader = in
+	default:
+		panic(fmt.Sprintf("unsupported stdin type %T", input))
+	}
+
+	return p
+}
+
+// stdinFor returns the input to pipe to the command on the next attempt.
+func (p *CLIPromise) stdinFor() (io.Reader, error) {
+	if p.stdinReader == nil {
+		if p.stdin == nil {
+			return nil, nil
+		}
+
+		return bytes.NewReader(p.stdin), nil
+	}
+
+	if seeker, ok := p.stdinReader.(io.Seeker); ok {
+		_, err := seeker.Seek(0, io.SeekStart)
+		return p.stdinReader, err
+	}
+
+	if p.stdinRead {
+		return nil, fmt.Errorf("stdin from an io.Reader can't be piped again, use a string or []byte to retry")
+	}
+
+	p.stdinRead = true
+	return p.stdinReader, nil
 }
 
 func (p *CLIPromise) Eventually() *CLIPromise {
@@ -135,9 +604,644 @@ func (p *CLIPromise) For(timeout time.Duration) *CLIPromise {
 	return p
 }
 
+func (p *CLIPromise) MinSamples(n int) *CLIPromise {
+	p.setMinSamples(n)
+	return p
+}
+
 func (p *CLIPromise) T() *CLIAssert {
 	return &CLIAssert{
 		AssertBase: AssertBase{config: p.config},
 		promise:    p,
 	}
 }
+
+// FuzzPromise represents a deferred batch of malformed inputs sent to a process.
+type FuzzPromise struct {
+	PromiseBase
+
+	network string
+	addr    string
+	inputs  [][]byte
+}
+
+func (p *FuzzPromise) Eventually() *FuzzPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *FuzzPromise) Within(timeout time.Duration) *FuzzPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *FuzzPromise) Consistently() *FuzzPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *FuzzPromise) For(timeout time.Duration) *FuzzPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *FuzzPromise) MinSamples(n int) *FuzzPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *FuzzPromise) T() *FuzzAssert {
+	return &FuzzAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// LogsPromise represents a deferred check of a process's log output.
+type LogsPromise struct {
+	PromiseBase
+
+	name   string
+	path   string
+	offset int64
+	atEnd  func(func())
+}
+
+func (p *LogsPromise) Eventually() *LogsPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *LogsPromise) Within(timeout time.Duration) *LogsPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *LogsPromise) Consistently() *LogsPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *LogsPromise) For(timeout time.Duration) *LogsPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *LogsPromise) MinSamples(n int) *LogsPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *LogsPromise) T() *LogsAssert {
+	return &LogsAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// TCPPromise represents a deferred exchange of raw bytes over a TCP connection.
+type TCPPromise struct {
+	PromiseBase
+
+	network string
+	addr    string
+	data    []byte
+	until   []byte
+}
+
+// Send appends data to the bytes written once the connection is open.
+func (p *TCPPromise) Send(data []byte) *TCPPromise {
+	p.data = append(p.data, data...)
+	return p
+}
+
+// Until stops reading the response once it contains delim.
+// By default, the response is read until the connection is closed or goes idle.
+func (p *TCPPromise) Until(delim []byte) *TCPPromise {
+	p.until = delim
+	return p
+}
+
+func (p *TCPPromise) Eventually() *TCPPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *TCPPromise) Within(timeout time.Duration) *TCPPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *TCPPromise) Consistently() *TCPPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *TCPPromise) For(timeout time.Duration) *TCPPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *TCPPromise) MinSamples(n int) *TCPPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *TCPPromise) T() *TCPAssert {
+	return &TCPAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// HTTPRawPromise represents a deferred HTTP exchange over a raw connection.
+type HTTPRawPromise struct {
+	PromiseBase
+
+	network string
+	addr    string
+	request []byte
+}
+
+func (p *HTTPRawPromise) Eventually() *HTTPRawPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *HTTPRawPromise) Within(timeout time.Duration) *HTTPRawPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *HTTPRawPromise) Consistently() *HTTPRawPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *HTTPRawPromise) For(timeout time.Duration) *HTTPRawPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *HTTPRawPromise) MinSamples(n int) *HTTPRawPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *HTTPRawPromise) T() *HTTPRawAssert {
+	return &HTTPRawAssert{
+		AssertBase:     AssertBase{config: p.config},
+		promise:        p,
+		headerCheckers: make(map[string][]Checker[string]),
+	}
+}
+
+// HTTPConnPromise represents a deferred series of HTTP exchanges over a
+// single connection.
+type HTTPConnPromise struct {
+	PromiseBase
+
+	network   string
+	addr      string
+	requests  [][]byte
+	pipelined bool
+}
+
+// Pipelined writes every request before reading any response, instead of
+// waiting for each response before sending the next request.
+func (p *HTTPConnPromise) Pipelined() *HTTPConnPromise {
+	p.pipelined = true
+	return p
+}
+
+func (p *HTTPConnPromise) Eventually() *HTTPConnPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *HTTPConnPromise) Within(timeout time.Duration) *HTTPConnPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *HTTPConnPromise) Consistently() *HTTPConnPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *HTTPConnPromise) For(timeout time.Duration) *HTTPConnPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *HTTPConnPromise) MinSamples(n int) *HTTPConnPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *HTTPConnPromise) T() *HTTPConnAssert {
+	return &HTTPConnAssert{
+		AssertBase:     AssertBase{config: p.config},
+		promise:        p,
+		statusCheckers: make(map[int][]Checker[int]),
+		headerCheckers: make(map[int]map[string][]Checker[string]),
+		bodyCheckers:   make(map[int][]Checker[string]),
+	}
+}
+
+// defaultUDPReplyTimeout is how long to wait for the first reply datagram.
+const defaultUDPReplyTimeout = time.Second
+
+// UDPPromise represents a deferred datagram exchange.
+type UDPPromise struct {
+	PromiseBase
+
+	addr         string
+	payload      []byte
+	replyTimeout time.Duration
+}
+
+// ReplyTimeout sets how long to wait for the first reply datagram.
+func (p *UDPPromise) ReplyTimeout(timeout time.Duration) *UDPPromise {
+	p.replyTimeout = timeout
+	return p
+}
+
+func (p *UDPPromise) Eventually() *UDPPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *UDPPromise) Within(timeout time.Duration) *UDPPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *UDPPromise) Consistently() *UDPPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *UDPPromise) For(timeout time.Duration) *UDPPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *UDPPromise) MinSamples(n int) *UDPPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *UDPPromise) T() *UDPAssert {
+	return &UDPAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// DNSPromise represents a deferred DNS query over UDP.
+type DNSPromise struct {
+	PromiseBase
+
+	addr         string
+	qtype        string
+	qname        string
+	query        []byte
+	replyTimeout time.Duration
+}
+
+// ReplyTimeout sets how long to wait for the response.
+func (p *DNSPromise) ReplyTimeout(timeout time.Duration) *DNSPromise {
+	p.replyTimeout = timeout
+	return p
+}
+
+func (p *DNSPromise) Eventually() *DNSPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *DNSPromise) Within(timeout time.Duration) *DNSPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *DNSPromise) Consistently() *DNSPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *DNSPromise) For(timeout time.Duration) *DNSPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *DNSPromise) MinSamples(n int) *DNSPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *DNSPromise) T() *DNSAssert {
+	return &DNSAssert{
+		AssertBase:     AssertBase{config: p.config},
+		promise:        p,
+		answerCheckers: make(map[int][]Checker[string]),
+		ttlCheckers:    make(map[int][]Checker[int]),
+	}
+}
+
+// defaultWSReplyTimeout is how long to wait for the expected websocket messages.
+const defaultWSReplyTimeout = 5 * time.Second
+
+// WSPromise represents a deferred websocket session.
+type WSPromise struct {
+	PromiseBase
+
+	network      string
+	addr         string
+	host         string
+	path         string
+	messages     []string
+	replyTimeout time.Duration
+}
+
+// Send queues a text message to send once the connection is open.
+// Messages are sent in order before any replies are read.
+func (p *WSPromise) Send(message string) *WSPromise {
+	p.messages = append(p.messages, message)
+	return p
+}
+
+// ReplyTimeout sets how long to wait for the expected messages to arrive.
+func (p *WSPromise) ReplyTimeout(timeout time.Duration) *WSPromise {
+	p.replyTimeout = timeout
+	return p
+}
+
+func (p *WSPromise) Eventually() *WSPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *WSPromise) Within(timeout time.Duration) *WSPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *WSPromise) Consistently() *WSPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *WSPromise) For(timeout time.Duration) *WSPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *WSPromise) MinSamples(n int) *WSPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *WSPromise) T() *WSAssert {
+	return &WSAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// GRPCPromise represents a deferred unary gRPC call.
+type GRPCPromise struct {
+	PromiseBase
+
+	method GRPCMethod
+	url    string
+	// transport is shared with other calls to the same process
+	transport *http.Transport
+	request   string
+	message   []byte
+}
+
+func (p *GRPCPromise) Eventually() *GRPCPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *GRPCPromise) Within(timeout time.Duration) *GRPCPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *GRPCPromise) Consistently() *GRPCPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *GRPCPromise) For(timeout time.Duration) *GRPCPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *GRPCPromise) MinSamples(n int) *GRPCPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *GRPCPromise) T() *GRPCAssert {
+	return &GRPCAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// RESPPromise represents a deferred Redis protocol command.
+type RESPPromise struct {
+	PromiseBase
+
+	network string
+	addr    string
+	args    []string
+}
+
+func (p *RESPPromise) Eventually() *RESPPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *RESPPromise) Within(timeout time.Duration) *RESPPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *RESPPromise) Consistently() *RESPPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *RESPPromise) For(timeout time.Duration) *RESPPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *RESPPromise) MinSamples(n int) *RESPPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *RESPPromise) T() *RESPAssert {
+	return &RESPAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// defaultSSEWindow is how long to collect server-sent events for.
+const defaultSSEWindow = time.Second
+
+// SSEPromise represents a deferred subscription to a server-sent event stream.
+type SSEPromise struct {
+	PromiseBase
+
+	url        string
+	socketPath string
+	tls        bool
+	window     time.Duration
+	during     func()
+}
+
+// Listen sets how long to collect events for. Collection stops early once every
+// checked event has arrived, unless the total count or ordering is checked.
+func (p *SSEPromise) Listen(window time.Duration) *SSEPromise {
+	p.window = window
+	return p
+}
+
+// During sets a function to run once the stream is open, e.g. to write the keys
+// whose change notifications are expected. It runs again on every attempt.
+func (p *SSEPromise) During(fn func()) *SSEPromise {
+	p.during = fn
+	return p
+}
+
+func (p *SSEPromise) Eventually() *SSEPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *SSEPromise) Within(timeout time.Duration) *SSEPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *SSEPromise) Consistently() *SSEPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *SSEPromise) For(timeout time.Duration) *SSEPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *SSEPromise) MinSamples(n int) *SSEPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *SSEPromise) T() *SSEAssert {
+	return &SSEAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// FDPromise represents a deferred count of a process's open file descriptors.
+type FDPromise struct {
+	PromiseBase
+
+	name string
+	// count returns how many file descriptors the process has open
+	count func() (int, error)
+	// baseline is the count when the promise was created
+	baseline int
+}
+
+func (p *FDPromise) Eventually() *FDPromise {
+	p.setEventually()
+	return p
+}
+
+func (p *FDPromise) Within(timeout time.Duration) *FDPromise {
+	p.setWithin(timeout)
+	return p
+}
+
+func (p *FDPromise) Consistently() *FDPromise {
+	p.setConsistently()
+	return p
+}
+
+func (p *FDPromise) For(timeout time.Duration) *FDPromise {
+	p.setFor(timeout)
+	return p
+}
+
+func (p *FDPromise) MinSamples(n int) *FDPromise {
+	p.setMinSamples(n)
+	return p
+}
+
+func (p *FDPromise) T() *FDAssert {
+	return &FDAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// SignalPromise represents a deferred signal sent to a started process.
+type SignalPromise struct {
+	PromiseBase
+
+	name   string
+	signal syscall.Signal
+	// send signals the process and waits for it to exit
+	send func() processExit
+}
+
+// ExitPromise represents waiting for a started process to exit.
+type ExitPromise struct {
+	PromiseBase
+
+	name string
+	// wait waits for the process to exit
+	wait func() processExit
+}
+
+func (p *ExitPromise) T() *ExitAssert {
+	return &ExitAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
+
+// processExit describes how a process exited after a signal.
+type processExit struct {
+	// after is how long the process took to exit after the signal
+	after    time.Duration
+	exitCode int
+	// how says how it exited, e.g. "with status 2" or "after SIGKILL"
+	how string
+	// killed is set when the process didn't exit within the shutdown timeout
+	killed bool
+	// running is set when the process still hadn't exited when waiting for it timed out
+	running bool
+	// unchecked is set when the harness doesn't manage the process, e.g. one started manually
+	unchecked bool
+}
+
+func (p *SignalPromise) T() *SignalAssert {
+	return &SignalAssert{
+		AssertBase: AssertBase{config: p.config},
+		promise:    p,
+	}
+}
@@ -0,0 +1,363 @@
+package attest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ProtoType is the type of a protobuf field.
+type ProtoType int
+
+const (
+	ProtoString ProtoType = iota
+	ProtoBytes
+	ProtoBool
+	ProtoInt32
+	ProtoInt64
+	ProtoUint32
+	ProtoUint64
+	ProtoFloat
+	ProtoDouble
+	// ProtoMessage fields hold a nested message described by ProtoField.Message
+	ProtoMessage
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ProtoField describes a single field of a protobuf message.
+// Enums can be described as ProtoInt32.
+type ProtoField struct {
+	Name     string
+	Number   int
+	Type     ProtoType
+	Repeated bool
+	Message  *ProtoDescriptor
+}
+
+// ProtoDescriptor describes the fields of a protobuf message, which is enough
+// to translate between its JSON and wire representations.
+type ProtoDescriptor struct {
+	Name   string
+	Fields []ProtoField
+}
+
+func (d *ProtoDescriptor) field(number int) *ProtoField {
+	for i := range d.Fields {
+		if d.Fields[i].Number == number {
+			return &d.Fields[i]
+		}
+	}
+
+	return nil
+}
+
+func (f *ProtoField) wireType() int {
+	switch f.Type {
+	case ProtoString, ProtoBytes, ProtoMessage:
+		return wireBytes
+	case ProtoFloat:
+		return wireFixed32
+	case ProtoDouble:
+		return wireFixed64
+	default:
+		return wireVarint
+	}
+}
+
+// encodeJSON encodes a JSON object as a protobuf message described by d.
+func (d *ProtoDescriptor) encodeJSON(data string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, fmt.Errorf("invalid JSON for %s: %w", d.Name, err)
+	}
+
+	return d.encode(object)
+}
+
+func (d *ProtoDescriptor) encode(object map[string]any) ([]byte, error) {
+	for key := range object {
+		found := false
+		for _, f := range d.Fields {
+			found = found || f.Name == key
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%s has no field %q", d.Name, key)
+		}
+	}
+
+	var buf []byte
+	for _, f := range d.Fields {
+		value, exists := object[f.Name]
+		if !exists || value == nil {
+			continue
+		}
+
+		values := []any{value}
+		if f.Repeated {
+			list, ok := value.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s.%s: expected a list, got %v", d.Name, f.Name, value)
+			}
+			values = list
+		}
+
+		for _, v := range values {
+			var err error
+			buf = binary.AppendUvarint(buf, uint64(f.Number<<3|f.wireType()))
+			buf, err = f.encodeValue(buf, v)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", d.Name, f.Name, err)
+			}
+		}
+	}
+
+	return buf, nil
+}
+
+func (f *ProtoField) encodeValue(buf []byte, value any) ([]byte, error) {
+	switch f.Type {
+	case ProtoString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %v", value)
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		return append(buf, s...), nil
+	case ProtoBytes:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected base64 bytes, got %v", value)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected base64 bytes: %w", err)
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(b)))
+		return append(buf, b...), nil
+	case ProtoBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a bool, got %v", value)
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case ProtoInt32, ProtoInt64:
+		n, err := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got %v", value)
+		}
+		return binary.AppendUvarint(buf, uint64(n)), nil
+	case ProtoUint32, ProtoUint64:
+		n, err := strconv.ParseUint(fmt.Sprint(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected an unsigned integer, got %v", value)
+		}
+		return binary.AppendUvarint(buf, n), nil
+	case ProtoFloat:
+		n, err := strconv.ParseFloat(fmt.Sprint(value), 32)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %v", value)
+		}
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(n))), nil
+	case ProtoDouble:
+		n, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %v", value)
+		}
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(n)), nil
+	case ProtoMessage:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object, got %v", value)
+		}
+		nested, err := f.Message.encode(object)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(nested)))
+		return append(buf, nested...), nil
+	}
+
+	return nil, fmt.Errorf("unsupported field type %d", f.Type)
+}
+
+// decodeJSON decodes a protobuf message described by d into JSON.
+// Unset scalar fields are included with their default values.
+func (d *ProtoDescriptor) decodeJSON(data []byte) (string, error) {
+	object, err := d.decode(data)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+func (d *ProtoDescriptor) decode(data []byte) (map[string]any, error) {
+	object := make(map[string]any)
+
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("%s: malformed field tag", d.Name)
+		}
+		data = data[n:]
+
+		number, wire := int(tag>>3), int(tag&7)
+
+		var raw []byte
+		var scalar uint64
+		switch wire {
+		case wireVarint:
+			scalar, n = binary.Uvarint(data)
+			if n <= 0 {
+				return nil, fmt.Errorf("%s: malformed varint in field %d", d.Name, number)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return nil, fmt.Errorf("%s: truncated field %d", d.Name, number)
+			}
+			scalar, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return nil, fmt.Errorf("%s: truncated field %d", d.Name, number)
+			}
+			scalar, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return nil, fmt.Errorf("%s: truncated field %d", d.Name, number)
+			}
+			raw, data = data[n:n+int(length)], data[n+int(length):]
+		default:
+			return nil, fmt.Errorf("%s: unsupported wire type %d in field %d", d.Name, wire, number)
+		}
+
+		f := d.field(number)
+		if f == nil {
+			// Unknown fields are skipped
+			continue
+		}
+
+		var values []any
+		if wire == wireBytes && f.wireType() != wireBytes {
+			// Packed repeated scalars
+			for len(raw) > 0 {
+				var v uint64
+				switch f.wireType() {
+				case wireVarint:
+					v, n = binary.Uvarint(raw)
+					if n <= 0 {
+						return nil, fmt.Errorf("%s.%s: malformed packed varint", d.Name, f.Name)
+					}
+					raw = raw[n:]
+				case wireFixed32:
+					if len(raw) < 4 {
+						return nil, fmt.Errorf("%s.%s: truncated packed value", d.Name, f.Name)
+					}
+					v, raw = uint64(binary.LittleEndian.Uint32(raw)), raw[4:]
+				case wireFixed64:
+					if len(raw) < 8 {
+						return nil, fmt.Errorf("%s.%s: truncated packed value", d.Name, f.Name)
+					}
+					v, raw = binary.LittleEndian.Uint64(raw), raw[8:]
+				}
+				values = append(values, f.decodeScalar(v))
+			}
+		} else if wire != f.wireType() {
+			return nil, fmt.Errorf("%s.%s: unexpected wire type %d", d.Name, f.Name, wire)
+		} else if wire == wireBytes {
+			v, err := f.decodeBytes(raw)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		} else {
+			values = append(values, f.decodeScalar(scalar))
+		}
+
+		if f.Repeated {
+			list, _ := object[f.Name].([]any)
+			object[f.Name] = append(list, values...)
+		} else if len(values) > 0 {
+			object[f.Name] = values[len(values)-1]
+		}
+	}
+
+	for _, f := range d.Fields {
+		if _, exists := object[f.Name]; !exists {
+			object[f.Name] = f.defaultValue()
+		}
+	}
+
+	return object, nil
+}
+
+func (f *ProtoField) decodeScalar(v uint64) any {
+	switch f.Type {
+	case ProtoBool:
+		return v != 0
+	case ProtoInt32:
+		return int32(v)
+	case ProtoInt64:
+		return int64(v)
+	case ProtoUint32:
+		return uint32(v)
+	case ProtoFloat:
+		return math.Float32frombits(uint32(v))
+	case ProtoDouble:
+		return math.Float64frombits(v)
+	default:
+		return v
+	}
+}
+
+func (f *ProtoField) decodeBytes(raw []byte) (any, error) {
+	switch f.Type {
+	case ProtoString:
+		return string(raw), nil
+	case ProtoBytes:
+		return base64.StdEncoding.EncodeToString(raw), nil
+	default:
+		return f.Message.decode(raw)
+	}
+}
+
+func (f *ProtoField) defaultValue() any {
+	if f.Repeated {
+		return []any{}
+	}
+
+	switch f.Type {
+	case ProtoString, ProtoBytes:
+		return ""
+	case ProtoBool:
+		return false
+	case ProtoMessage:
+		return nil
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin
+
+package attest
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// ioctl performs a device-specific operation on the file descriptor.
+func ioctl(fd, req, arg uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, arg)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// setWindowSize sets the terminal's size in characters, since some programs
+// misbehave on a terminal without one.
+func setWindowSize(fd uintptr, rows, cols uint16) error {
+	size := struct{ rows, cols, x, y uint16 }{rows, cols, 0, 0}
+	return ioctl(fd, syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&size)))
+}
@@ -0,0 +1,43 @@
+package attest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// openPTY opens a pseudo-terminal pair. The program runs with the terminal
+// end as its controlling terminal while the harness reads and writes the
+// other end.
+func openPTY() (*os.File, *os.File, error) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, req := range []uintptr{syscall.TIOCPTYGRANT, syscall.TIOCPTYUNLK} {
+		err = ioctl(ptmx.Fd(), req, 0)
+		if err != nil {
+			ptmx.Close()
+			return nil, nil, fmt.Errorf("unlocking pseudo-terminal: %w", err)
+		}
+	}
+
+	name := make([]byte, 128)
+	err = ioctl(ptmx.Fd(), syscall.TIOCPTYGNAME, uintptr(unsafe.Pointer(&name[0])))
+	if err != nil {
+		ptmx.Close()
+		return nil, nil, fmt.Errorf("naming pseudo-terminal: %w", err)
+	}
+
+	name, _, _ = bytes.Cut(name, []byte{0})
+	tty, err := os.OpenFile(string(name), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		ptmx.Close()
+		return nil, nil, err
+	}
+
+	return ptmx, tty, nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !darwin
+
+package attest
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+func openPTY() (*os.File, *os.File, error) {
+	return nil, nil, fmt.Errorf("pseudo-terminals aren't supported on %s", runtime.GOOS)
+}
+
+func setWindowSize(fd uintptr, rows, cols uint16) error {
+	return nil
+}
@@ -0,0 +1,98 @@
+package attest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that caps the rate of outgoing requests so
+// large verification loops don't exhaust local ephemeral ports.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	warnOnce sync.Once
+}
+
+// timeWait is how long a closed connection holds on to its local port.
+const timeWait = 60 * time.Second
+
+// newRateLimiter creates a limiter allowing rps requests per second.
+// It returns nil, which never throttles, if rps is negative. If rps is zero,
+// it only throttles once requests could have used up the ephemeral port
+// range, and then to the rate at which closed connections free their ports.
+func newRateLimiter(rps int) *rateLimiter {
+	switch {
+	case rps < 0:
+		return nil
+	case rps == 0:
+		ports := float64(ephemeralPorts())
+		return &rateLimiter{
+			rate:   ports / timeWait.Seconds(),
+			burst:  ports,
+			tokens: ports,
+			last:   time.Now(),
+		}
+	}
+
+	return &rateLimiter{
+		rate:   float64(rps),
+		burst:  float64(rps),
+		tokens: float64(rps),
+		last:   time.Now(),
+	}
+}
+
+// ephemeralPorts returns how many local ports outgoing connections can use,
+// falling back to the size of the IANA range where Linux's isn't available.
+func ephemeralPorts() int {
+	data, err := os.ReadFile("/proc/sys/net/ipv4/ip_local_port_range")
+	if err == nil {
+		var low, high int
+		_, err = fmt.Sscan(string(data), &low, &high)
+		if err == nil && high > low {
+			return high - low + 1
+		}
+	}
+
+	return 65535 - 49152 + 1
+}
+
+// wait blocks until a request may be issued or the context is cancelled.
+func (l *rateLimiter) wait(ctx context.Context) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	// Reserve a token, possibly going into debt
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return
+	}
+
+	l.warnOnce.Do(func() {
+		fmt.Println(yellow(fmt.Sprintf("Throttling requests to %.0f/s to avoid exhausting local ports", l.rate)))
+	})
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
@@ -0,0 +1,190 @@
+package attest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Readiness tells when a process started with StartWith is ready for the
+// tests, e.g. do.StartWith("worker", []StartOption{ReadyWhenLogMatches("started consumer")}). By
+// default, it's ready once it accepts connections on its port. In-process
+// servers are ready as soon as they're created.
+type Readiness struct {
+	logPattern *regexp.Regexp
+	// portPattern captures the port the process announces it chose, if set
+	portPattern *regexp.Regexp
+	// healthPath is probed with GET until it returns 200 OK, if set
+	healthPath string
+}
+
+// merge returns the conditions of both, with r's taking precedence.
+func (r Readiness) merge(other Readiness) Readiness {
+	if r.logPattern == nil {
+		r.logPattern = other.logPattern
+	}
+	if r.portPattern == nil {
+		r.portPattern = other.portPattern
+	}
+	if r.healthPath == "" {
+		r.healthPath = other.healthPath
+	}
+
+	return r
+}
+
+func (r Readiness) apply(_ string, proc *Process) {
+	proc.ready = r.merge(proc.ready)
+}
+
+// ReadyWhenLogMatches makes the process ready once it logs a line matching
+// pattern, a regular expression, for processes that don't listen on a port,
+// e.g. batch workers and crawlers.
+func ReadyWhenLogMatches(pattern string) Readiness {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("ReadyWhenLogMatches() requires a valid regular expression: %v", err))
+	}
+
+	return Readiness{logPattern: re}
+}
+
+// ReadyWhenPortAnnounced lets the process pick its own port, for frameworks
+// that insist on binding port 0, and reads it from the first log line
+// matching pattern, whose only group captures the port, e.g.
+// ReadyWhenPortAnnounced(`LISTENING ON (\d+)`). The process is passed port
+// 0 and is ready once it accepts connections on the port it announced. It
+// may pick another one each time it starts.
+func ReadyWhenPortAnnounced(pattern string) Readiness {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("ReadyWhenPortAnnounced() requires a valid regular expression: %v", err))
+	}
+	if re.NumSubexp() != 1 {
+		panic(fmt.Sprintf("ReadyWhenPortAnnounced() requires a pattern with one group capturing the port, got %q", pattern))
+	}
+
+	return Readiness{portPattern: re}
+}
+
+// ReadyWhenHealthy makes the process ready once GET path returns 200 OK,
+// e.g. ReadyWhenHealthy("/healthz"), for processes that accept connections
+// before they can serve requests. Combined with ReadyWhenLogMatches, the
+// log is waited for first.
+func ReadyWhenHealthy(path string) Readiness {
+	if path == "" || path[0] != '/' {
+		panic(fmt.Sprintf("ReadyWhenHealthy() requires a path starting with /, got %q", path))
+	}
+
+	return Readiness{healthPath: path}
+}
+
+// healthCheckTimeout bounds each probe of a health check.
+const healthCheckTimeout = time.Second
+
+// waitForHealthy probes the process's health check until it returns 200 OK,
+// failing early if the process exits first.
+func (do *Do) waitForHealthy(name string, proc *Process) {
+	client := newHTTPClient(healthCheckTimeout, proc.socketPath, proc.tls, false)
+
+	var last string
+	var exited bool
+	healthy := func() bool {
+		select {
+		case <-proc.exited:
+			exited = true
+			return true
+		default:
+		}
+
+		req, err := http.NewRequestWithContext(do.ctx, "GET", proc.url(proc.ready.healthPath), nil)
+		if err != nil {
+			last = err.Error()
+			return false
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			last = err.Error()
+			return false
+		}
+		resp.Body.Close()
+
+		last = resp.Status
+		return resp.StatusCode == http.StatusOK
+	}
+
+	if eventually(do.ctx, healthy, do.config.ProcessStartTimeout, do.config.RetryPollInterval) && !exited {
+		return
+	}
+
+	if do.ctx.Err() != nil {
+		return
+	}
+
+	tail := indent(do.tailLog(name, crashLogLines), "    ")
+	if exited {
+		panic(fmt.Sprintf("Process %s exited %s before GET %s returned 200 OK.\n\n  Last log lines:\n%s",
+			name, describeExit(proc.cmd.ProcessState), proc.ready.healthPath, tail))
+	}
+
+	panic(fmt.Sprintf("Process %s didn't become healthy within %s, GET %s last returned %s.\n\n  Last log lines:\n%s",
+		name, do.config.ProcessStartTimeout, proc.ready.healthPath, last, tail))
+}
+
+// waitForLog waits for the process to log a line matching pattern since it
+// started, failing early if it exits first, and returns the line's submatches.
+func (do *Do) waitForLog(name string, proc *Process, pattern *regexp.Regexp) []string {
+	var match []string
+	var exited bool
+	done := func() bool {
+		select {
+		case <-proc.exited:
+			exited = true
+		default:
+		}
+
+		data, _ := do.readLog(name, proc.logStart)
+		for line := range bytes.Lines(data) {
+			match = pattern.FindStringSubmatch(string(bytes.TrimRight(line, "\r\n")))
+			if match != nil {
+				return true
+			}
+		}
+
+		return exited
+	}
+
+	eventually(do.ctx, done, do.config.ProcessStartTimeout, do.config.RetryPollInterval)
+	if match != nil || do.ctx.Err() != nil {
+		return match
+	}
+
+	tail := indent(do.tailLog(name, crashLogLines), "    ")
+	if exited {
+		panic(fmt.Sprintf("Process %s exited %s before logging a line matching %q.\n\n  Last log lines:\n%s",
+			name, describeExit(proc.cmd.ProcessState), pattern, tail))
+	}
+
+	panic(fmt.Sprintf("Process %s didn't log a line matching %q within %s.\n\n  Last log lines:\n%s",
+		name, pattern, do.config.ProcessStartTimeout, tail))
+}
+
+// waitForAnnouncedPort waits for the process to log the port it picked and
+// listens on it from then on.
+func (do *Do) waitForAnnouncedPort(name string, proc *Process) {
+	match := do.waitForLog(name, proc, proc.ready.portPattern)
+	if match == nil {
+		return
+	}
+
+	port, err := strconv.Atoi(match[1])
+	if err != nil || port <= 0 || port > 65535 {
+		panic(fmt.Sprintf("Process %s announced %q, which isn't a valid port.", name, match[1]))
+	}
+
+	proc.realPort = port
+}
@@ -0,0 +1,203 @@
+package attest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// exchange is a recorded HTTP request and the response it got.
+type exchange struct {
+	Process     string      `json:"process"`
+	Method      string      `json:"method"`
+	Path        string      `json:"path"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Proto       string      `json:"proto,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body,omitempty"`
+}
+
+func (e exchange) key() string {
+	return strings.Join([]string{e.Process, e.Method, e.Path, e.RequestBody}, "\x00")
+}
+
+// exchanges records the HTTP exchanges of a run against a reference
+// implementation, or replays them so a stage can be dry-run without
+// starting any processes to check that its assertions are satisfiable.
+type exchanges struct {
+	mu sync.Mutex
+
+	// file receives recorded exchanges, one JSON object per line
+	file *os.File
+
+	// replaying is set when responses come from a recording.
+	// Each request gets the next recorded response for the same request,
+	// and the last one once they run out, e.g. while retrying.
+	replaying bool
+	recorded  map[string][]exchange
+}
+
+// newRecorder records exchanges to the file at path.
+func newRecorder(path string) (*exchanges, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exchanges{file: file}, nil
+}
+
+// newReplayer loads exchanges recorded to the file at path.
+func newReplayer(path string) (*exchanges, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	e := &exchanges{replaying: true, recorded: make(map[string][]exchange)}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var recorded exchange
+		err := json.Unmarshal(scanner.Bytes(), &recorded)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+
+		e.recorded[recorded.key()] = append(e.recorded[recorded.key()], recorded)
+	}
+
+	return e, scanner.Err()
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// transport wraps next so requests to the named process are recorded or replayed.
+func (e *exchanges) transport(process string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body []byte
+		if req.Body != nil {
+			var err error
+			body, err = io.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		request := exchange{
+			Process:     process,
+			Method:      req.Method,
+			Path:        req.URL.RequestURI(),
+			RequestBody: string(body),
+		}
+
+		if e.replaying {
+			return e.replay(req, request)
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+		request.Status, request.Proto, request.Header, request.Body = resp.StatusCode, resp.Proto, resp.Header, string(respBody)
+		e.record(request)
+
+		return resp, nil
+	})
+}
+
+// record appends the exchange to the recording.
+func (e *exchanges) record(recorded exchange) {
+	line, err := json.Marshal(recorded)
+	if err != nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.file != nil {
+		e.file.Write(append(line, '\n'))
+	}
+}
+
+// replay answers the request with its next recorded response.
+func (e *exchanges) replay(req *http.Request, request exchange) (*http.Response, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	queue := e.recorded[request.key()]
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s on %s", request.Method, request.Path, request.Process)
+	}
+
+	recorded := queue[0]
+	if len(queue) > 1 {
+		e.recorded[request.key()] = queue[1:]
+	}
+
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	proto := recorded.Proto
+	major, minor, ok := http.ParseHTTPVersion(proto)
+	if !ok {
+		proto, major, minor = "HTTP/1.1", 1, 1
+	}
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode: recorded.Status,
+		Proto:      proto,
+		ProtoMajor: major,
+		ProtoMinor: minor,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(recorded.Body)),
+		Request:    req,
+	}, nil
+}
+
+// close closes the recording, if any.
+func (e *exchanges) close() {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.file != nil {
+		e.file.Close()
+		e.file = nil
+	}
+}
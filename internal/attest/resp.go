@@ -0,0 +1,148 @@
+package attest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RESP reply types, identified by their type byte
+const (
+	respSimpleString = '+'
+	respError        = '-'
+	respInteger      = ':'
+	respBulkString   = '$'
+	respArray        = '*'
+	respNull         = '_'
+)
+
+// respReply is a single decoded RESP reply.
+type respReply struct {
+	kind    byte
+	str     string
+	integer int64
+	array   []respReply
+	null    bool
+}
+
+// String renders the reply the way redis-cli does.
+func (r respReply) String() string {
+	if r.null {
+		return "(nil)"
+	}
+
+	switch r.kind {
+	case respError:
+		return fmt.Sprintf("(error) %s", r.str)
+	case respInteger:
+		return fmt.Sprintf("(integer) %d", r.integer)
+	case respArray:
+		if len(r.array) == 0 {
+			return "(empty array)"
+		}
+
+		var lines []string
+		for i, element := range r.array {
+			lines = append(lines, fmt.Sprintf("%d) %s", i+1, element))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return strconv.Quote(r.str)
+	}
+}
+
+// formatCommand renders a command for failure messages, quoting arguments
+// that are empty or contain spaces or control characters.
+func formatCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = arg
+		if arg == "" || strings.ContainsFunc(arg, func(r rune) bool { return r <= ' ' || r == '"' }) {
+			quoted[i] = strconv.Quote(arg)
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// encodeRESP encodes a command as an array of bulk strings.
+func encodeRESP(args []string) []byte {
+	buf := fmt.Appendf(nil, "*%d\r\n", len(args))
+	for _, arg := range args {
+		buf = fmt.Appendf(buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	return buf
+}
+
+// readRESP reads a single reply.
+func readRESP(reader *bufio.Reader) (respReply, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return respReply{}, err
+	}
+
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return respReply{}, fmt.Errorf("malformed reply %q", line)
+	}
+
+	kind, payload := line[0], line[1:len(line)-2]
+	reply := respReply{kind: kind}
+
+	switch kind {
+	case respSimpleString, respError:
+		reply.str = payload
+	case respInteger:
+		reply.integer, err = strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			return respReply{}, fmt.Errorf("malformed integer reply %q", line)
+		}
+	case respNull:
+		reply.null = true
+	case respBulkString:
+		length, err := strconv.Atoi(payload)
+		if err != nil {
+			return respReply{}, fmt.Errorf("malformed bulk string length %q", line)
+		}
+
+		if length < 0 {
+			reply.null = true
+			break
+		}
+
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return respReply{}, err
+		}
+
+		if string(data[length:]) != "\r\n" {
+			return respReply{}, fmt.Errorf("bulk string is longer than its length %d", length)
+		}
+		reply.str = string(data[:length])
+	case respArray:
+		length, err := strconv.Atoi(payload)
+		if err != nil {
+			return respReply{}, fmt.Errorf("malformed array length %q", line)
+		}
+
+		if length < 0 {
+			reply.null = true
+			break
+		}
+
+		for range length {
+			element, err := readRESP(reader)
+			if err != nil {
+				return respReply{}, err
+			}
+
+			reply.array = append(reply.array, element)
+		}
+	default:
+		return respReply{}, fmt.Errorf("unknown reply type %q", line)
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,184 @@
+package attest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// resultsFile holds the outcome of a run's tests, so failures can be
+// explained in more detail later without re-running them.
+const resultsFile = "results.json"
+
+// logExcerptLines is how many lines of each process log are kept for a failure.
+const logExcerptLines = 20
+
+// Results are the outcome of a run's tests.
+type Results struct {
+	Challenge string       `json:"challenge,omitempty"`
+	Stage     string       `json:"stage,omitempty"`
+	Passed    bool         `json:"passed"`
+	Tests     []TestResult `json:"tests"`
+
+	// RunDir is the run's working directory, set when the results are loaded
+	RunDir string `json:"-"`
+}
+
+// TestResult is the outcome of a single test.
+type TestResult struct {
+	Name string `json:"name"`
+	// Status is one of passed, failed, skipped, xfailed or xpassed
+	Status  string   `json:"status"`
+	Failure *Failure `json:"failure,omitempty"`
+}
+
+// Failure is what's known about a failed test, beyond what's printed.
+type Failure struct {
+	Message string `json:"message"`
+	Steps   string `json:"steps,omitempty"`
+	// Exchange is the test's last HTTP request and response, if any
+	Exchange *HTTPExchange `json:"exchange,omitempty"`
+	// Logs are the last lines each process logged during the test, keyed by process name
+	Logs map[string]string `json:"logs,omitempty"`
+}
+
+// HTTPExchange is an HTTP request and its response, in full.
+type HTTPExchange struct {
+	Trace          string      `json:"trace"`
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeader  http.Header `json:"request_header,omitempty"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	Proto          string      `json:"proto,omitempty"`
+	Status         int         `json:"status,omitempty"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+	// Error is why no response was received, if it wasn't
+	Error string `json:"error,omitempty"`
+}
+
+// lastExchange holds the latest HTTP exchange of the current test.
+type lastExchange struct {
+	mu       sync.Mutex
+	exchange *HTTPExchange
+}
+
+// set replaces the latest exchange.
+func (l *lastExchange) set(exchange *HTTPExchange) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.exchange = exchange
+}
+
+// take returns the latest exchange and forgets it.
+func (l *lastExchange) take() *HTTPExchange {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	exchange := l.exchange
+	l.exchange = nil
+	return exchange
+}
+
+// newFailure collects what's known about the current test's failure.
+func (do *Do) newFailure(failure any) *Failure {
+	return &Failure{
+		Message:  fmt.Sprint(failure),
+		Steps:    do.breadcrumbs.String(),
+		Exchange: do.lastHTTP.take(),
+		Logs:     do.logExcerpts(),
+	}
+}
+
+// logExcerpts returns the last lines each process logged during the current test.
+func (do *Do) logExcerpts() map[string]string {
+	excerpts := make(map[string]string)
+	do.processes.Range(func(name string, _ *Process) bool {
+		data, err := os.ReadFile(do.logPath(name))
+		if err != nil {
+			return true
+		}
+
+		mark, _ := do.logMarks.Get(name)
+		data = data[min(mark, int64(len(data))):]
+
+		lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		if len(lines) > logExcerptLines {
+			lines = lines[len(lines)-logExcerptLines:]
+		}
+
+		if excerpt := strings.Join(lines, "\n"); excerpt != "" {
+			excerpts[name] = excerpt
+		}
+
+		return true
+	})
+
+	return excerpts
+}
+
+// writeResults writes the run's results to its working directory.
+func (do *Do) writeResults(results Results) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(do.workingDir, resultsFile), append(data, '\n'), 0644)
+}
+
+// LatestResults loads the results of the most recent run in workingDir.
+func LatestResults(workingDir string) (*Results, error) {
+	paths, err := filepath.Glob(filepath.Join(workingDir, "run-*", resultsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var latest string
+	var latestInfo os.FileInfo
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if latestInfo == nil || info.ModTime().After(latestInfo.ModTime()) {
+			latest, latestInfo = path, info
+		}
+	}
+
+	if latest == "" {
+		return nil, errors.New("no test results found")
+	}
+
+	data, err := os.ReadFile(latest)
+	if err != nil {
+		return nil, err
+	}
+
+	var results Results
+	err = json.Unmarshal(data, &results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", latest, err)
+	}
+
+	results.RunDir = filepath.Dir(latest)
+	return &results, nil
+}
+
+// FirstFailure returns the first failed test, or nil if none failed.
+func (r *Results) FirstFailure() *TestResult {
+	for i := range r.Tests {
+		if r.Tests[i].Failure != nil && r.Tests[i].Status == "failed" {
+			return &r.Tests[i]
+		}
+	}
+
+	return nil
+}
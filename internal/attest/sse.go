@@ -0,0 +1,61 @@
+package attest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// sseEvent is a single server-sent event.
+type sseEvent struct {
+	name string
+	data string
+	id   string
+}
+
+// readSSE parses events from r and calls onEvent for each one until r
+// is exhausted or onEvent returns false.
+func readSSE(r io.Reader, onEvent func(sseEvent) bool) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event sseEvent
+	var data []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		// A blank line dispatches the event
+		if line == "" {
+			if len(data) > 0 {
+				event.data = strings.Join(data, "\n")
+				if event.name == "" {
+					event.name = "message"
+				}
+
+				if !onEvent(event) {
+					return
+				}
+			}
+
+			event, data = sseEvent{id: event.id}, nil
+			continue
+		}
+
+		// Comments, e.g. keep-alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event.name = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.id = value
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package attest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// processStateFile lists the processes the harness is running, so ones left
+// behind by an interrupted run can be found and cleaned up.
+const processStateFile = "processes.json"
+
+// ProcessRecord describes a process the harness started.
+type ProcessRecord struct {
+	Name    string    `json:"name"`
+	PID     int       `json:"pid"`
+	Port    int       `json:"port,omitempty"`
+	Socket  string    `json:"socket,omitempty"`
+	Started time.Time `json:"started"`
+	// RunDir is the working directory of the run that started it
+	RunDir string `json:"run_dir"`
+	// Identity is the operating system's start time for the process, checked
+	// before it's signalled in case its pid has since been reused
+	Identity string `json:"identity,omitempty"`
+}
+
+// processState is the state file of a working directory. Runs in the same
+// process share it, so updates are serialized.
+type processState struct {
+	path string
+}
+
+var processStateMu sync.Mutex
+
+// add records a started process.
+func (s *processState) add(record ProcessRecord) {
+	record.Identity, _ = processIdentity(record.PID)
+	s.update(func(records []ProcessRecord) []ProcessRecord {
+		return append(records, record)
+	})
+}
+
+// remove forgets the process with the given pid once it and its process
+// group have exited.
+func (s *processState) remove(pid int) {
+	if groupAlive(pid) {
+		return
+	}
+
+	s.update(func(records []ProcessRecord) []ProcessRecord {
+		return slices.DeleteFunc(records, func(r ProcessRecord) bool {
+			return r.PID == pid
+		})
+	})
+}
+
+// update rewrites the state file with fn applied to its records. The state
+// file is best effort, so errors are ignored.
+func (s *processState) update(fn func([]ProcessRecord) []ProcessRecord) {
+	processStateMu.Lock()
+	defer processStateMu.Unlock()
+
+	records, _ := readProcessState(s.path)
+	writeProcessState(s.path, fn(records))
+}
+
+// RunningProcesses lists the processes harness runs in workingDir started
+// that are still running, forgetting those that have exited.
+func RunningProcesses(workingDir string) ([]ProcessRecord, error) {
+	processStateMu.Lock()
+	defer processStateMu.Unlock()
+
+	path := filepath.Join(workingDir, processStateFile)
+	records, err := readProcessState(path)
+	if err != nil {
+		return nil, err
+	}
+
+	running := slices.DeleteFunc(records, func(r ProcessRecord) bool {
+		return !r.running()
+	})
+
+	return running, writeProcessState(path, running)
+}
+
+// KillProcesses kills the process groups of the processes harness runs in
+// workingDir left running and returns them.
+func KillProcesses(workingDir string) ([]ProcessRecord, error) {
+	running, err := RunningProcesses(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var killed []ProcessRecord
+	for _, record := range running {
+		// Processes are started in their own group, which also holds their children
+		err := syscall.Kill(-record.PID, syscall.SIGKILL)
+		if err != nil && !errors.Is(err, syscall.ESRCH) {
+			return killed, err
+		}
+
+		killed = append(killed, record)
+	}
+
+	processStateMu.Lock()
+	defer processStateMu.Unlock()
+
+	return killed, writeProcessState(filepath.Join(workingDir, processStateFile), nil)
+}
+
+// running reports whether the process group the harness started is still
+// running, and not some other process that has since been given its pid.
+func (r ProcessRecord) running() bool {
+	if r.Identity == "" || !groupAlive(r.PID) {
+		return false
+	}
+
+	identity, err := processIdentity(r.PID)
+	if err != nil {
+		// The leader has exited, but a pid isn't reused while its group is left
+		return true
+	}
+
+	return identity == r.Identity
+}
+
+// processIdentity returns the start time of the process with the given pid,
+// which tells it apart from a later process given the same pid.
+func processIdentity(pid int) (string, error) {
+	fields, err := procStat(fmt.Sprintf("/proc/%d/stat", pid))
+	if err == nil {
+		if len(fields) < 20 {
+			return "", fmt.Errorf("Malformed stat for process %d", pid)
+		}
+
+		// starttime, the 22nd field, in clock ticks since boot
+		return fields[19], nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	if _, statErr := os.Stat("/proc/self/stat"); statErr == nil {
+		return "", err
+	}
+
+	// No /proc, e.g. on macOS
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", fmt.Sprint(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// groupAlive reports whether any process is left in the process group led
+// by pid, e.g. a server run.sh started without exec.
+func groupAlive(pid int) bool {
+	err := syscall.Kill(-pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// readProcessState reads the records in the state file, if there is one.
+func readProcessState(path string) ([]ProcessRecord, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var records []ProcessRecord
+	err = json.Unmarshal(data, &records)
+	return records, err
+}
+
+// writeProcessState replaces the records in the state file, removing it if
+// there are none.
+func writeProcessState(path string, records []ProcessRecord) error {
+	if len(records) == 0 {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
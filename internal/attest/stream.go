@@ -0,0 +1,187 @@
+package attest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// execStream collects the output of a running command, e.g. one started by
+// OutputEventually that's left running until the end of the test.
+type execStream struct {
+	pid   int
+	state *processState
+
+	mu     sync.Mutex
+	output []byte
+	// changed is closed and replaced whenever output arrives
+	changed chan struct{}
+
+	// exited is closed once the command has exited, with exitErr holding the result of Wait
+	exited  chan struct{}
+	exitErr error
+}
+
+// Write collects the command's stdout and stderr.
+func (s *execStream) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.output = append(s.output, b...)
+	close(s.changed)
+	s.changed = make(chan struct{})
+
+	return len(b), nil
+}
+
+// snapshot returns the output so far and a channel that's closed when more arrives.
+func (s *execStream) snapshot() (string, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return string(s.output), s.changed
+}
+
+// exitStatus describes how the command exited.
+func (s *execStream) exitStatus() string {
+	if s.exitErr != nil {
+		return s.exitErr.Error()
+	}
+
+	return "exit status 0"
+}
+
+// kill stops the command and everything it started.
+func (s *execStream) kill() {
+	select {
+	case <-s.exited:
+		return
+	default:
+	}
+
+	err := syscall.Kill(-s.pid, syscall.SIGKILL)
+	if err != nil && !errors.Is(err, syscall.ESRCH) {
+		fmt.Println(red("Error killing command"), red(fmt.Sprint(s.pid)))
+	}
+
+	<-s.exited
+}
+
+// addStream tracks a command to kill at the end of the current test.
+func (do *Do) addStream(s *execStream) {
+	do.streamMu.Lock()
+	defer do.streamMu.Unlock()
+
+	do.streams = append(do.streams, s)
+}
+
+// killStreams kills the commands started by OutputEventually.
+func (do *Do) killStreams() {
+	do.streamMu.Lock()
+	streams := do.streams
+	do.streams = nil
+	do.streamMu.Unlock()
+
+	for _, s := range streams {
+		s.kill()
+	}
+}
+
+// outputWithSignals runs cmd like Output, sending the promise's signals as
+// its stdout passes each one's checker. If a checker never passes, the
+// command is killed and the step is kept in unmetSignal.
+func (a *CLIAssert) outputWithSignals(cmd *exec.Cmd) ([]byte, error) {
+	s := &execStream{
+		changed: make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = s, &stderr
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		s.exitErr = cmd.Wait()
+		close(s.exited)
+	}()
+
+	var signalled time.Time
+	for i := range a.promise.signals {
+		step := &a.promise.signals[i]
+		if !s.waitFor(step.checker, a.config.DefaultRetryTimeout, a.config.RetryPollInterval) {
+			a.unmetSignal = step
+			cmd.Process.Kill()
+			<-s.exited
+
+			output, _ := s.snapshot()
+			return []byte(output), nil
+		}
+
+		// The command may exit before the signal is delivered, which its exit code shows
+		cmd.Process.Signal(step.signal)
+		signalled = time.Now()
+	}
+
+	<-s.exited
+	a.exitAfter = time.Since(signalled)
+
+	var exitError *exec.ExitError
+	if errors.As(s.exitErr, &exitError) {
+		exitError.Stderr = stderr.Bytes()
+	}
+
+	output, _ := s.snapshot()
+	return []byte(output), s.exitErr
+}
+
+// waitFor waits until the output so far passes checker, for up to timeout or
+// until the command exits.
+func (s *execStream) waitFor(checker Checker[string], timeout, pollInterval time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		output, changed := s.snapshot()
+		if checker.Check(output) {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-s.exited:
+			// Output written just before exiting may still be in flight
+			time.Sleep(pollInterval)
+			output, _ = s.snapshot()
+			return checker.Check(output)
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+// signalName names sig the way it's usually written, e.g. SIGHUP.
+func signalName(sig syscall.Signal) string {
+	names := map[syscall.Signal]string{
+		syscall.SIGHUP:  "SIGHUP",
+		syscall.SIGINT:  "SIGINT",
+		syscall.SIGQUIT: "SIGQUIT",
+		syscall.SIGKILL: "SIGKILL",
+		syscall.SIGUSR1: "SIGUSR1",
+		syscall.SIGUSR2: "SIGUSR2",
+		syscall.SIGTERM: "SIGTERM",
+	}
+
+	if name, ok := names[sig]; ok {
+		return name
+	}
+
+	return sig.String()
+}
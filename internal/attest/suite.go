@@ -3,6 +3,11 @@ package attest
 import (
 	"context"
 	"fmt"
+	"maps"
+	"math/rand/v2"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/fatih/color"
 )
@@ -10,9 +15,12 @@ import (
 var (
 	green     = color.New(color.FgGreen).SprintFunc()
 	red       = color.New(color.FgRed).SprintFunc()
+	yellow    = color.New(color.FgYellow).SprintFunc()
 	bold      = color.New(color.Bold).SprintFunc()
-	checkMark = green("✓")
-	crossMark = red("✗")
+	checkMark = green(Marks.Check)
+	crossMark = red(Marks.Cross)
+	skipMark  = yellow(Marks.Skip)
+	xfailMark = yellow(Marks.Cross)
 )
 
 // Suite represents a test suite with setup and test functions.
@@ -20,6 +28,71 @@ type Suite struct {
 	setupFn func(*Do)
 	tests   []TestFunc
 	config  *Config
+	strict  bool
+
+	// Challenge and stage the suite tests, for the run's manifest
+	challenge string
+	stage     string
+
+	// Reasons for skipping tests, keyed by test name
+	skips map[string]string
+	// Reasons tests are expected to fail, keyed by test name
+	xfails map[string]string
+
+	processArgs []string
+	manualStart bool
+	portPassing PortPassing
+	// server serves processes in-process instead of starting the command, if set
+	server Server
+
+	preflightProcess   string
+	preflightEndpoints []Endpoint
+
+	grpcMethods []GRPCMethod
+
+	// Files HTTP exchanges are recorded to or replayed from
+	recordPath string
+	replayPath string
+
+	// Creates the model HTTP operations are mirrored to in oracle mode
+	newOracle func() Oracle
+
+	// How processes serve stored data, for VerifyDataset
+	dataset *DatasetSpec
+
+	// Longest random delay added to each request and the seed delays are drawn from
+	jitterMax  time.Duration
+	jitterSeed uint64
+
+	// Harness configurations the suite must pass under every combination of
+	matrix []dimension
+}
+
+// Endpoint describes an HTTP endpoint a stage requires.
+type Endpoint struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+func (e Endpoint) String() string {
+	return fmt.Sprintf("%s %s", e.Method, e.Path)
+}
+
+// dimension is a harness configuration, e.g. the cluster size, and the
+// values a suite must pass under.
+type dimension struct {
+	name   string
+	values []any
+}
+
+// variant is one combination of matrix dimension values.
+type variant struct {
+	label  string
+	values map[string]any
+	// suffix tells the variant's run directory apart, since several may
+	// start within the same second
+	suffix string
 }
 
 // TestFunc represents a single test case with name and function.
@@ -41,6 +114,10 @@ func (s *Suite) WithConfig(config *Config) *Suite {
 		merged.Command = config.Command
 	}
 
+	if config.Dir != "" {
+		merged.Dir = config.Dir
+	}
+
 	if config.WorkingDir != "" {
 		merged.WorkingDir = config.WorkingDir
 	}
@@ -69,10 +146,178 @@ func (s *Suite) WithConfig(config *Config) *Suite {
 		merged.ExecuteTimeout = config.ExecuteTimeout
 	}
 
+	if config.MaxRequestsPerSecond != 0 {
+		merged.MaxRequestsPerSecond = config.MaxRequestsPerSecond
+	}
+
+	if config.TimeoutMultiplier != 0 {
+		merged.TimeoutMultiplier = config.TimeoutMultiplier
+	}
+
+	if config.MaxBodyLength != 0 {
+		merged.MaxBodyLength = config.MaxBodyLength
+	}
+
+	if config.LineWidth != 0 {
+		merged.LineWidth = config.LineWidth
+	}
+
 	s.config = merged
 	return s
 }
 
+// InDir runs the command in dir, keeping the rest of the configuration.
+// A relative WorkingDir is taken relative to dir too.
+func (s *Suite) InDir(dir string) *Suite {
+	if s.config == nil {
+		s.config = DefaultConfig()
+	}
+
+	s.config.Dir = dir
+	if !filepath.IsAbs(s.config.WorkingDir) {
+		s.config.WorkingDir = filepath.Join(dir, s.config.WorkingDir)
+	}
+
+	return s
+}
+
+// WithMaxRequestsPerSecond caps outgoing requests at rps, keeping the rest of
+// the configuration. A negative rps disables throttling.
+func (s *Suite) WithMaxRequestsPerSecond(rps int) *Suite {
+	if s.config == nil {
+		s.config = DefaultConfig()
+	}
+
+	s.config.MaxRequestsPerSecond = rps
+	return s
+}
+
+// WithStage names the challenge and stage the suite tests, which are
+// recorded in each run's manifest.
+func (s *Suite) WithStage(challenge, stage string) *Suite {
+	s.challenge = challenge
+	s.stage = stage
+	return s
+}
+
+// WithProcessArgs appends extra arguments to every process started during the run.
+func (s *Suite) WithProcessArgs(args ...string) *Suite {
+	s.processArgs = append(s.processArgs, args...)
+	return s
+}
+
+// ManualStart makes the harness print the arguments for each process and wait
+// for the user to start it themselves, e.g. under a debugger, instead of starting it.
+func (s *Suite) ManualStart() *Suite {
+	s.manualStart = true
+	return s
+}
+
+// Strict makes stack traces in process logs fail the current test
+// immediately, with the captured output, as unexpected exits always do.
+func (s *Suite) Strict() *Suite {
+	s.strict = true
+	return s
+}
+
+// Preflight probes the required endpoints on the named process after setup.
+// If none of them are implemented, the suite fails with a checklist
+// instead of failing the first deep assertion. Every endpoint is sent, in
+// order, so later ones can undo what earlier ones did, e.g. a DELETE after
+// a PUT; probes aren't mirrored to an Oracle, so they should leave no trace.
+func (s *Suite) Preflight(name string, endpoints ...Endpoint) *Suite {
+	s.preflightProcess = name
+	s.preflightEndpoints = endpoints
+	return s
+}
+
+// GRPC registers the descriptors of the gRPC methods the tests call.
+func (s *Suite) GRPC(methods ...GRPCMethod) *Suite {
+	s.grpcMethods = append(s.grpcMethods, methods...)
+	return s
+}
+
+// Record saves every HTTP exchange of the run to path, e.g. while running
+// the stage against a reference implementation, for later dry runs.
+func (s *Suite) Record(path string) *Suite {
+	s.recordPath = path
+	return s
+}
+
+// Replay dry-runs the stage against HTTP exchanges saved with Record instead
+// of real processes, which are never started. This checks that the stage's
+// assertions can be satisfied at all, catching bugs like impossible expected
+// bodies. Only HTTP requests are replayed.
+func (s *Suite) Replay(path string) *Suite {
+	s.replayPath = path
+	return s
+}
+
+// Oracle mirrors every HTTP operation to a known-good model and fails the run
+// at the first operation whose response diverges from the model's. Streamed
+// request bodies aren't mirrored. newModel is called for every run, so each
+// Matrix variant starts from a fresh model.
+func (s *Suite) Oracle(newModel func() Oracle) *Suite {
+	s.newOracle = newModel
+	return s
+}
+
+// Dataset declares how processes serve the data they store, so tests can
+// check whole datasets with VerifyDataset.
+func (s *Suite) Dataset(spec DatasetSpec) *Suite {
+	s.dataset = &spec
+	return s
+}
+
+// Jitter delays every request by a random duration of up to max, so
+// implementations that only pass because operations arrive in lockstep fail.
+// Delays are drawn from seed, or a random seed that's printed if it's 0, so
+// a failing run can be reproduced.
+func (s *Suite) Jitter(max time.Duration, seed uint64) *Suite {
+	s.jitterMax = max
+	s.jitterSeed = seed
+	return s
+}
+
+// Matrix runs the whole suite once per value of the named dimension, e.g. the
+// signal used to restart processes or the cluster size, and reports which
+// variants passed. With several dimensions, every combination is run. Tests
+// read the current value with Variant.
+func (s *Suite) Matrix(name string, values ...any) *Suite {
+	s.matrix = append(s.matrix, dimension{name: name, values: values})
+	return s
+}
+
+// variants lists every combination of dimension values, varying the last
+// dimension fastest.
+func (s *Suite) variants() []variant {
+	variants := []variant{{values: map[string]any{}}}
+	for _, dim := range s.matrix {
+		var next []variant
+		for _, v := range variants {
+			for _, value := range dim.values {
+				values := maps.Clone(v.values)
+				values[dim.name] = value
+
+				label := fmt.Sprintf("%s=%v", dim.name, value)
+				if v.label != "" {
+					label = v.label + ", " + label
+				}
+
+				next = append(next, variant{label: label, values: values})
+			}
+		}
+
+		variants = next
+	}
+
+	for i := range variants {
+		variants[i].suffix = fmt.Sprintf("variant-%d", i+1)
+	}
+
+	return variants
+}
+
 // Setup adds a setup function that runs before all tests.
 func (s *Suite) Setup(fn func(*Do)) *Suite {
 	s.setupFn = fn
@@ -85,6 +330,29 @@ func (s *Suite) Test(name string, fn func(*Do)) *Suite {
 	return s
 }
 
+// Skip marks the named test as skipped, e.g. because it's platform-specific.
+// Skipped tests are listed with the reason instead of running.
+func (s *Suite) Skip(name, reason string) *Suite {
+	if s.skips == nil {
+		s.skips = make(map[string]string)
+	}
+
+	s.skips[name] = reason
+	return s
+}
+
+// XFail marks the named test as expected to fail, e.g. while its stage is
+// under development. Its failure is reported as XFAIL without failing the
+// suite, and the remaining tests still run.
+func (s *Suite) XFail(name, reason string) *Suite {
+	if s.xfails == nil {
+		s.xfails = make(map[string]string)
+	}
+
+	s.xfails[name] = reason
+	return s
+}
+
 // Run executes the test suite and returns results.
 func (s *Suite) Run(ctx context.Context) bool {
 	config := s.config
@@ -92,63 +360,280 @@ func (s *Suite) Run(ctx context.Context) bool {
 		config = DefaultConfig()
 	}
 
-	do := newDo(ctx, config)
-	defer do.Done()
+	multiplier, err := timeoutMultiplier(config)
+	if err != nil {
+		fmt.Printf("%s %s\n\n%s\n", crossMark, "TIMEOUTS", err)
+		return false
+	}
+
+	if multiplier != 1 {
+		fmt.Println(yellow(fmt.Sprintf("Scaling timeouts by %gx", multiplier)))
+	}
+	config = config.scaleTimeouts(multiplier)
+
+	// Every variant draws the same delays
+	jitterSeed := s.jitterSeed
+	if s.jitterMax > 0 {
+		if jitterSeed == 0 {
+			jitterSeed = rand.Uint64()
+		}
+
+		fmt.Println(yellow(fmt.Sprintf("Jittering requests by up to %s (seed %d)", s.jitterMax, jitterSeed)))
+	}
+
+	if len(s.matrix) == 0 {
+		return s.run(ctx, config, jitterSeed, variant{})
+	}
+
+	variants := s.variants()
+	passed := make([]bool, len(variants))
+	for i, variant := range variants {
+		fmt.Printf("%s\n\n", bold("Variant "+variant.label))
+		passed[i] = s.run(ctx, config, jitterSeed, variant)
+		fmt.Println()
+
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+
+	fmt.Println(bold("Matrix"))
+	for i, variant := range variants {
+		mark := checkMark
+		if !passed[i] {
+			mark = crossMark
+		}
+
+		fmt.Printf("  %s %s\n", mark, variant.label)
+	}
+
+	return !slices.Contains(passed, false)
+}
+
+// run executes the suite once, under the given matrix variant if any.
+func (s *Suite) run(ctx context.Context, config *Config, jitterSeed uint64, v variant) (ok bool) {
+	start := time.Now()
+	do := newDo(ctx, config, v.suffix)
+	do.variant = v.values
+	do.processArgs = s.processArgs
+	do.manualStart = s.manualStart
+	do.portPassing = s.portPassing
+	do.server = s.server
+	for _, method := range s.grpcMethods {
+		do.grpcMethods[method.path()] = method
+	}
+	defer func() {
+		do.Done()
+
+		manifest := Manifest{
+			Challenge: s.challenge,
+			Stage:     s.stage,
+			Started:   start.UTC().Truncate(time.Second),
+			Duration:  time.Since(start).Round(10 * time.Millisecond).String(),
+			Passed:    ok,
+		}
+		if s.jitterMax > 0 {
+			manifest.Seed = jitterSeed
+		}
+
+		do.writeManifest(manifest)
+	}()
+
+	if s.newOracle != nil {
+		do.oracle = &shadowOracle{model: s.newOracle()}
+	}
+
+	do.jitter = newJitter(s.jitterMax, jitterSeed)
+
+	if s.dataset != nil {
+		do.dataset = &datasetVerifier{spec: *s.dataset}
+	}
+
+	var err error
+	switch {
+	case s.replayPath != "":
+		do.exchanges, err = newReplayer(s.replayPath)
+	case s.recordPath != "":
+		do.exchanges, err = newRecorder(s.recordPath)
+	}
+
+	if err != nil {
+		fmt.Printf("%s %s\n\n%s\n", crossMark, "RECORDING", err)
+		return false
+	}
+
+	if s.strict {
+		do.enableStrict()
+	}
+
+	// Outcome of each test, for `lsfr explain`
+	results := Results{Challenge: s.challenge, Stage: s.stage}
 
 	// Run setup function if defined
 	var failed bool
+	var skipAll string
 	if s.setupFn != nil {
 		func() {
 			defer func() {
 				err := recover()
+				if skip, ok := err.(skipSignal); ok {
+					skipAll = skip.reason
+					return
+				}
+
 				if err != nil {
 					failed = true
+					results.Tests = append(results.Tests, TestResult{Name: "SETUP", Status: "failed", Failure: do.newFailure(err)})
 
 					fmt.Printf("%s %s\n", crossMark, "SETUP")
-					fmt.Printf("\n%s\n", err)
+					fmt.Printf("\n%s\n", fitLines(fmt.Sprint(err), config.LineWidth))
+
+					if steps := do.breadcrumbs.String(); steps != "" {
+						fmt.Printf("\n  Previous steps: %s\n", steps)
+					}
 				}
 			}()
 
+			do.beginTest()
 			s.setupFn(do)
+			do.endTest()
 		}()
 	}
 
+	// Check that at least some required endpoints exist
+	if !failed && skipAll == "" && len(s.preflightEndpoints) > 0 {
+		failed = !s.preflight(do)
+		if failed {
+			results.Tests = append(results.Tests, TestResult{
+				Name:    "PREFLIGHT",
+				Status:  "failed",
+				Failure: &Failure{Message: "None of the required endpoints are implemented yet"},
+			})
+		}
+	}
+
 	// Run each test, stopping on first failure or cancellation
+	var passed, failedTests, skipped, xfailed int
 	for _, test := range s.tests {
 		if failed {
 			break
 		}
 
+		reason, skip := s.skips[test.Name]
+		if skipAll != "" {
+			reason, skip = skipAll, true
+		}
+
+		if skip {
+			skipped++
+			results.Tests = append(results.Tests, TestResult{Name: test.Name, Status: "skipped"})
+			fmt.Printf("%s %s %s\n", skipMark, test.Name, yellow(fmt.Sprintf("(skipped: %s)", reason)))
+			continue
+		}
+
 		select {
 		case <-ctx.Done():
 			return false
 		default:
 		}
 
+		var failure any
+		var skippedReason string
 		func() {
 			defer func() {
 				err := recover()
-				if err != nil {
-					failed = true
-
-					fmt.Printf("%s %s\n", crossMark, test.Name)
-					fmt.Printf("\n%s\n", err)
+				if skip, ok := err.(skipSignal); ok {
+					skippedReason = skip.reason
+					return
 				}
+
+				failure = err
 			}()
 
+			do.beginTest()
 			test.Fn(do)
+			do.endTest()
 		}()
 
-		if !failed {
+		xfailReason, xfail := s.xfails[test.Name]
+		switch {
+		case skippedReason != "":
+			skipped++
+			results.Tests = append(results.Tests, TestResult{Name: test.Name, Status: "skipped"})
+			fmt.Printf("%s %s %s\n", skipMark, test.Name, yellow(fmt.Sprintf("(skipped: %s)", skippedReason)))
+		case failure != nil && xfail:
+			xfailed++
+			results.Tests = append(results.Tests, TestResult{Name: test.Name, Status: "xfailed"})
+			fmt.Printf("%s %s %s\n", xfailMark, test.Name, yellow(fmt.Sprintf("XFAIL (%s)", xfailReason)))
+		case failure != nil:
+			failed = true
+			failedTests++
+			results.Tests = append(results.Tests, TestResult{Name: test.Name, Status: "failed", Failure: do.newFailure(failure)})
+
+			fmt.Printf("%s %s\n", crossMark, test.Name)
+			fmt.Printf("\n%s\n", fitLines(fmt.Sprint(failure), config.LineWidth))
+
+			if steps := do.breadcrumbs.String(); steps != "" {
+				fmt.Printf("\n  Previous steps: %s\n", steps)
+			}
+			if note := do.chaosNote(); note != "" {
+				fmt.Printf("  %s\n", yellow(note))
+			}
+		case xfail:
+			passed++
+			results.Tests = append(results.Tests, TestResult{Name: test.Name, Status: "xpassed"})
+			fmt.Printf("%s %s %s\n", checkMark, test.Name, yellow(fmt.Sprintf("XPASS (expected to fail: %s)", xfailReason)))
+		default:
+			passed++
+			results.Tests = append(results.Tests, TestResult{Name: test.Name, Status: "passed"})
 			fmt.Printf("%s %s\n", checkMark, test.Name)
 		}
 	}
 
+	// Tests after a failure never run, so they count as skipped too
+	notRun := len(s.tests) - passed - failedTests - skipped - xfailed
+	counts := fmt.Sprintf("%d passed, %d failed, %d skipped", passed, failedTests, skipped+notRun)
+	if xfailed > 0 {
+		counts += fmt.Sprintf(", %d xfailed", xfailed)
+	}
+	stats := fmt.Sprintf(Marks.render("%s · %d assertions · %s · %s"), counts, do.metrics.assertions.Load(),
+		time.Since(start).Round(10*time.Millisecond), do.workingDir)
+
 	if failed {
-		fmt.Printf("\n%s %s\n", bold("FAILED"), crossMark)
+		fmt.Printf("\n%s %s  %s\n", bold("FAILED"), crossMark, stats)
 	} else {
-		fmt.Printf("\n%s %s\n", bold("PASSED"), checkMark)
+		fmt.Printf("\n%s %s  %s\n", bold("PASSED"), checkMark, stats)
 	}
+	fmt.Printf("Harness: %s\n", do.metrics)
+
+	do.metrics.writeJSON(filepath.Join(do.workingDir, "metrics.json"))
+
+	results.Passed = !failed
+	do.writeResults(results)
 
 	return !failed
 }
+
+// preflight probes the required endpoints and prints a checklist if none are implemented.
+func (s *Suite) preflight(do *Do) bool {
+	var implemented bool
+	for _, endpoint := range s.preflightEndpoints {
+		// Probing carries on past an implemented endpoint to run the ones cleaning up after it
+		if do.probe(s.preflightProcess, endpoint) {
+			implemented = true
+		}
+	}
+
+	if implemented {
+		return true
+	}
+
+	fmt.Printf("%s %s\n", crossMark, "PREFLIGHT")
+	fmt.Printf("\nNone of the required endpoints are implemented yet:\n")
+	for _, endpoint := range s.preflightEndpoints {
+		fmt.Printf("  %s %s\n", crossMark, endpoint)
+	}
+	fmt.Printf("\nImplement these endpoints, then run the tests again.\n")
+
+	return false
+}
@@ -0,0 +1,74 @@
+package attest
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Symbols are the markers that decorate output.
+type Symbols struct {
+	Unicode bool
+
+	Check     string
+	Cross     string
+	Skip      string
+	Arrow     string
+	Separator string
+	Ellipsis  string
+}
+
+var (
+	unicodeSymbols = Symbols{Unicode: true, Check: "✓", Cross: "✗", Skip: "○", Arrow: "→", Separator: "·", Ellipsis: "…"}
+	asciiSymbols   = Symbols{Check: "+", Cross: "x", Skip: "o", Arrow: "->", Separator: "|", Ellipsis: "..."}
+)
+
+// Marks are the symbols for the terminal lsfr is running in.
+var Marks = DetectSymbols()
+
+// DetectSymbols returns ASCII symbols if NO_COLOR is set or the terminal
+// can't render Unicode, e.g. a dumb terminal, a legacy Windows console or a
+// non-UTF-8 locale, and Unicode ones otherwise.
+func DetectSymbols() Symbols {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return asciiSymbols
+	}
+
+	// Windows Terminal sets WT_SESSION, the legacy console renders a code page
+	if runtime.GOOS == "windows" && os.Getenv("WT_SESSION") == "" {
+		return asciiSymbols
+	}
+
+	// The first locale variable that's set wins, an unset locale is assumed to be UTF-8
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := strings.ToLower(os.Getenv(name))
+		if locale == "" {
+			continue
+		}
+
+		if strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8") {
+			return unicodeSymbols
+		}
+
+		return asciiSymbols
+	}
+
+	return unicodeSymbols
+}
+
+// render replaces the Unicode symbols in text, e.g. a failure message,
+// with these symbols.
+func (s Symbols) render(text string) string {
+	if s.Unicode {
+		return text
+	}
+
+	return strings.NewReplacer(
+		unicodeSymbols.Check, s.Check,
+		unicodeSymbols.Cross, s.Cross,
+		unicodeSymbols.Skip, s.Skip,
+		unicodeSymbols.Arrow, s.Arrow,
+		unicodeSymbols.Separator, s.Separator,
+		unicodeSymbols.Ellipsis, s.Ellipsis,
+	).Replace(text)
+}
@@ -0,0 +1,90 @@
+package attest_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestAttach(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("attached"))
+	}))
+	defer server.Close()
+
+	socketPath := filepath.Join(t.TempDir(), "svc.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	socketServer := &http.Server{Handler: server.Config.Handler}
+	go socketServer.Serve(listener)
+	defer socketServer.Close()
+
+	tests := []struct {
+		name       string
+		addr       string
+		shouldPass bool
+	}{
+		{
+			name:       "TCP OK",
+			addr:       strings.TrimPrefix(server.URL, "http://"),
+			shouldPass: true,
+		},
+		{
+			name:       "Unix Socket OK",
+			addr:       "unix:" + socketPath,
+			shouldPass: true,
+		},
+		{
+			name:       "Unreachable",
+			addr:       "127.0.0.1:1",
+			shouldPass: false,
+		},
+		{
+			name:       "Invalid Address",
+			addr:       "not-an-address",
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				WorkingDir:          t.TempDir(),
+				ProcessStartTimeout: 500 * time.Millisecond,
+			}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.Attach("svc", tt.addr)
+				}).
+				Test(tt.name, func(do *Do) {
+					do.HTTP("svc", "GET", "/").T().
+						Status(Is(200)).
+						Body(Is("attached")).
+						Assert("Attached process should respond")
+
+					// Attached processes are never restarted by the harness
+					do.Restart("svc")
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package attest_test
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestBreadcrumbs(t *testing.T) {
+	config := &Config{
+		Command:    helperCommand(t),
+		WorkingDir: t.TempDir(),
+	}
+
+	output := captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.Start("svc")
+			}).
+			Test("Steps", func(do *Do) {
+				for _, key := range []string{"a", "b", "c", "d"} {
+					do.HTTP("svc", "PUT", "/kv/"+key, "1").T().
+						Status(Is(200)).
+						Assert("Writes should succeed")
+				}
+
+				do.Restart("svc", syscall.SIGKILL)
+
+				do.HTTP("svc", "GET", "/kv/a").T().
+					Status(Is(404)).
+					Assert("Should fail and show the previous steps")
+			}).
+			Run(context.Background())
+	})
+
+	// Only the last few steps are kept
+	expected := "Previous steps: PUT /kv/b → 200; PUT /kv/c → 200; PUT /kv/d → 200; " +
+		"RESTART svc (SIGKILL); GET /kv/a → 200"
+	if !strings.Contains(output, expected) {
+		t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+	}
+}
@@ -0,0 +1,80 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestChaos(t *testing.T) {
+	tests := []struct {
+		name       string
+		faults     []ChaosFault
+		fail       bool
+		shouldPass bool
+	}{
+		{name: "Recovers", faults: []ChaosFault{ChaosKill, ChaosPause}, shouldPass: true},
+		{name: "Restarts", faults: []ChaosFault{ChaosRestart}, shouldPass: true},
+		{name: "Prints Seed On Failure", faults: []ChaosFault{ChaosPause}, fail: true, shouldPass: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.Start("node-1")
+						do.Start("node-2")
+					}).
+					Test("Chaos", func(do *Do) {
+						chaos := do.Chaos(42, time.Second, tt.faults...)
+						if chaos.Seed() != 42 {
+							t.Errorf("expected seed 42, got %d", chaos.Seed())
+						}
+
+						if tt.fail {
+							time.Sleep(300 * time.Millisecond)
+							do.HTTP("node-1", "GET", "/").T().
+								Status(Is(999)).
+								Assert("Fail while faults are injected")
+						}
+
+						chaos.Wait()
+						if chaos.Running() {
+							t.Error("expected the run to be over after Wait")
+						}
+
+						for _, name := range []string{"node-1", "node-2"} {
+							client := &http.Client{Timeout: time.Second}
+							resp, err := client.Get(do.URL(name, "/"))
+							if err != nil {
+								t.Errorf("expected %s to recover after chaos: %v", name, err)
+								continue
+							}
+							resp.Body.Close()
+						}
+					}).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Fatalf("expected success=%v, got %v:\n%s", tt.shouldPass, success, output)
+			}
+
+			if !tt.shouldPass && !strings.Contains(output, "do.Chaos seed 42") {
+				t.Errorf("expected the seed to be printed, got:\n%s", output)
+			}
+
+			if !tt.shouldPass && !strings.Contains(output, "PAUSE node-") {
+				t.Errorf("expected a process to have been paused, got:\n%s", output)
+			}
+		})
+	}
+}
@@ -3,7 +3,13 @@ package attest_test
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -239,6 +245,48 @@ func TestCLI(t *testing.T) {
 			},
 			shouldPass: false,
 		},
+		{
+			name:   "YAML Output",
+			config: &Config{Command: "printf"},
+			testFunc: func(do *Do) {
+				do.Exec("node: node-1\\npeers:\\n  - node-2\\n  - node-3\\n").T().
+					YAML("node", Is("node-1")).
+					YAML("peers.1", Is("node-3")).
+					Assert("Should check fields of YAML output")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "ContainsAll - any order",
+			config: &Config{Command: "printf"},
+			testFunc: func(do *Do) {
+				do.Exec("started\\nelected leader\\nready\\n").T().
+					Output(ContainsAll("ready", "started", "elected")).
+					Output(ContainsInOrder("started", "elected", "ready")).
+					Assert("Should pass when every line appears in order")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "ContainsAll - missing substring",
+			config: &Config{Command: "printf"},
+			testFunc: func(do *Do) {
+				do.Exec("started\\nready\\n").T().
+					Output(ContainsAll("started", "elected", "ready")).
+					Assert("Should fail when a line is missing")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "ContainsInOrder - wrong order",
+			config: &Config{Command: "printf"},
+			testFunc: func(do *Do) {
+				do.Exec("ready\\nstarted\\n").T().
+					Output(ContainsInOrder("started", "ready")).
+					Assert("Should fail when lines appear out of order")
+			},
+			shouldPass: false,
+		},
 		{
 			name:   "Multiple Checkers - multiple exit code checkers",
 			config: &Config{Command: "echo"},
@@ -271,6 +319,283 @@ func TestCLI(t *testing.T) {
 			},
 			shouldPass: false,
 		},
+		{
+			name:   "Exec Against Socket",
+			config: &Config{Command: "echo"},
+			testFunc: func(do *Do) {
+				do.MockSocketProcess("daemon", "/run/daemon.sock")
+				do.ExecAgainst("daemon", "ps").T().
+					Output(Is("--socket=/run/daemon.sock ps\n")).
+					Assert("Client should be pointed at the daemon's socket")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Exec Against Port",
+			config: &Config{Command: "echo"},
+			testFunc: func(do *Do) {
+				do.MockProcess("daemon", "8080")
+				do.ExecAgainst("daemon", "ps").T().
+					Output(Is("--addr=127.0.0.1:8080 ps\n")).
+					Assert("Client should be pointed at the daemon's address")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Duration OK",
+			config: &Config{Command: "echo"},
+			testFunc: func(do *Do) {
+				do.Exec("fast").T().
+					Duration(LessThan(5 * time.Second)).
+					Assert("Command should finish quickly")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Duration Exceeded",
+			config: &Config{Command: "sleep"},
+			testFunc: func(do *Do) {
+				do.Exec("0.2").T().
+					ExitCode(Is(0)).
+					Duration(LessThan(50 * time.Millisecond)).
+					Assert("Should fail when the command is too slow")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Stdin String",
+			config: &Config{Command: "sort"},
+			testFunc: func(do *Do) {
+				do.Exec().WithStdin("banana\napple\ncherry\n").T().
+					ExitCode(Is(0)).
+					Output(Is("apple\nbanana\ncherry\n")).
+					Assert("Sort should read its input from stdin")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Stdin Reader Retried",
+			config: &Config{Command: "wc"},
+			testFunc: func(do *Do) {
+				do.Exec("-l").WithStdin(strings.NewReader("a\nb\nc\n")).Consistently().For(300 * time.Millisecond).T().
+					Output(Matches(`^\s*3\n$`)).
+					Assert("Seekable stdin should be rewound for every attempt")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Stdin Reader Not Rewindable",
+			config: &Config{Command: "cat"},
+			testFunc: func(do *Do) {
+				do.Exec().WithStdin(io.MultiReader(strings.NewReader("once"))).Consistently().For(300 * time.Millisecond).T().
+					Output(Is("once")).
+					Assert("Should fail when stdin can only be read once")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Stdin Output Mismatch",
+			config: &Config{Command: "cat"},
+			testFunc: func(do *Do) {
+				do.Exec().WithStdin([]byte("hello")).T().
+					Output(Is("goodbye")).
+					Assert("Should fail when the output does not match")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Env Override",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", `echo "$TZ $LSFR_FLAG"`).WithEnv("TZ=UTC", "LSFR_FLAG=on").T().
+					Output(Is("UTC on\n")).
+					Assert("Variables should be set for the command")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Env Not Leaked",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "true").WithEnv("LSFR_FLAG=on").T().
+					ExitCode(Is(0)).
+					Assert("First command")
+				do.Exec("-c", `echo "${LSFR_FLAG:-unset}"`).T().
+					Output(Is("unset\n")).
+					Assert("Variables should only be set for their command")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Env Mismatch",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", `echo "$LANG"`).WithEnv("LANG=C").T().
+					Output(Is("en_US.UTF-8\n")).
+					Assert("Should fail when the output does not match")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Env Invalid",
+			config: &Config{Command: "true"},
+			testFunc: func(do *Do) {
+				do.Exec().WithEnv("TZ").T().
+					ExitCode(Is(0)).
+					Assert("Should fail when a variable isn't KEY=VALUE")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Dir Relative",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "pwd").InDir("repo-1").T().
+					Output(Matches(`/repo-1\n$`)).
+					Assert("Relative directories should be created in the run's working directory")
+				do.Exec("-c", "touch HEAD && ls").InDir("repo-1").T().
+					Output(Is("HEAD\n")).
+					Assert("The directory should be reused by later commands")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Dir Absolute",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "pwd").InDir("/").T().
+					Output(Is("/\n")).
+					Assert("Absolute directories should be used as is")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Dir Mismatch",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "ls").InDir("empty").T().
+					Output(Contains(".git")).
+					Assert("Should fail when the output does not match")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Pipe",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Pipe(
+					do.Exec("-c", "printf 'b\\na\\nb\\n'"),
+					do.Exec("-c", "sort"),
+					do.Exec("-c", "uniq -c | wc -l"),
+				).T().
+					ExitCode(Is(0)).
+					Output(Matches(`^\s*2\n$`)).
+					Assert("Each command's stdout should be piped to the next one's stdin")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Pipe Stdin And Env",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Pipe(
+					do.Exec("-c", "cat").WithStdin("hello"),
+					do.Exec("-c", "tr a-z A-Z; echo \" $SUFFIX\"").WithEnv("SUFFIX=!"),
+				).T().
+					Output(Is("HELLO !\n")).
+					Assert("The first command's stdin and each command's environment should be used")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Pipe Exit Code Of Last",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Pipe(
+					do.Exec("-c", "echo data; exit 3"),
+					do.Exec("-c", "cat >/dev/null; echo 'bad input' >&2; exit 1"),
+				).T().
+					ExitCode(Is(0)).
+					Assert("Should fail when the last command fails")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Signal",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "trap 'echo reloaded' HUP; trap 'echo stopping; exit 0' INT; echo ready; while :; do sleep 0.05; done").
+					SignalWhen(Contains("ready"), syscall.SIGHUP).
+					SignalWhen(Contains("reloaded"), syscall.SIGINT).T().
+					ExitCode(Is(0)).
+					Output(Is("ready\nreloaded\nstopping\n")).
+					Assert("Command should handle the signals in order")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Signal Exit Code Mismatch",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "trap 'exit 130' INT; echo ready; while :; do sleep 0.05; done").
+					SignalWhen(Contains("ready"), syscall.SIGINT).T().
+					ExitCode(Is(0)).
+					Assert("Should fail when the command doesn't exit cleanly")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Signal Exit After",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "trap 'sleep 0.3; exit 0' TERM; echo ready; while :; do sleep 0.05; done").
+					SignalWhen(Contains("ready"), syscall.SIGTERM).T().
+					ExitAfter(LessThan(100 * time.Millisecond)).
+					Assert("Should fail when the command takes too long to exit")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Signal Output Never Matches",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "echo starting; exit 0").
+					SignalWhen(Contains("ready"), syscall.SIGINT).T().
+					ExitCode(Is(0)).
+					Assert("Should fail when the command exits before it's ready")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Output Eventually",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "sleep 0.2; echo 'listening on :8080' >&2; sleep 60").T().
+					OutputEventually(Contains("listening on")).
+					Assert("Output should be checked while the command is running")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Output Eventually Timeout",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "echo starting; sleep 60").Eventually().Within(200 * time.Millisecond).T().
+					OutputEventually(Contains("listening on")).
+					Assert("Should fail when the output never matches")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Output Eventually Exited",
+			config: &Config{Command: "sh"},
+			testFunc: func(do *Do) {
+				do.Exec("-c", "echo 'address in use'; exit 1").T().
+					OutputEventually(Contains("listening on")).
+					Assert("Should fail when the command exits first")
+			},
+			shouldPass: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -303,3 +628,28 @@ func TestCLI(t *testing.T) {
 		})
 	}
 }
+
+func TestCLIOutputEventuallyKilled(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+
+	success := New().WithConfig(&Config{Command: "sh", WorkingDir: t.TempDir()}).
+		Test("Daemon", func(do *Do) {
+			do.Exec("-c", fmt.Sprintf("echo $$ > %s; echo ready; sleep 60", pidFile)).T().
+				OutputEventually(Is("ready\n")).
+				Assert("Command should start")
+		}).
+		Run(context.Background())
+	if !success {
+		t.Fatal("test should pass but failed")
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	if syscall.Kill(pid, 0) == nil {
+		t.Errorf("command %d should be killed at the end of the test", pid)
+	}
+}
@@ -0,0 +1,61 @@
+package attest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestStartCluster(t *testing.T) {
+	var mu sync.Mutex
+	args := make(map[string][]string)
+	server := func(a []string) (http.Handler, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		args[a[2]] = a
+		return http.NotFoundHandler(), nil
+	}
+
+	var names []string
+	var peerAddr string
+	output := captureStdout(t, func() {
+		New().WithConfig(&Config{WorkingDir: t.TempDir()}).InProcess(server).
+			Setup(func(do *Do) {
+				names = do.StartCluster("node", 3, PeerTemplate("--id={{.Name}}"), PeerTemplate("--peers={{.Peers}}"), PeerTemplate("--cache"))
+				peerAddr = do.PeerAddress("node-2", "node-3")
+			}).
+			Run(context.Background())
+	})
+
+	if !slices.Equal(names, []string{"node-1", "node-2", "node-3"}) {
+		t.Fatalf("unexpected names %v:\n%s", names, output)
+	}
+
+	node2 := args["--id=node-2"]
+	if len(node2) != 5 || node2[4] != "--cache" {
+		t.Fatalf("unexpected arguments %v", node2)
+	}
+
+	peers := strings.TrimPrefix(node2[3], "--peers=")
+	if !strings.HasPrefix(peers, "node-1=") || !strings.HasSuffix(peers, fmt.Sprintf(",node-3=%s", peerAddr)) {
+		t.Errorf("expected node-2 to be passed node-1 and node-3, got %v", node2[3])
+	}
+}
+
+func TestPeerTemplateUnknownField(t *testing.T) {
+	defer func() {
+		err := recover()
+		if err == nil || !strings.Contains(fmt.Sprint(err), "PeerTemplate() requires a valid template") {
+			t.Errorf("expected an invalid template panic, got %v", err)
+		}
+	}()
+
+	PeerTemplate("--peers={{.Addresses}}")
+}
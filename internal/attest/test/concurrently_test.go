@@ -0,0 +1,252 @@
+package attest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestConcurrently(t *testing.T) {
+	tests := []struct {
+		name     string
+		fail     func(i int) string
+		expected []string
+	}{
+		{
+			name:     "All Pass",
+			fail:     func(i int) string { return "" },
+			expected: []string{"<nil>"},
+		},
+		{
+			name: "Single Failure",
+			fail: func(i int) string {
+				if i == 3 {
+					return "PUT /kv/key-3\n  Expected status: 200\n  Actual status: 500"
+				}
+				return ""
+			},
+			expected: []string{"PUT /kv/key-3\n  Expected status: 200"},
+		},
+		{
+			name: "Same Failure Different Keys",
+			fail: func(i int) string {
+				if i%2 == 0 {
+					return fmt.Sprintf("PUT /kv/key-%d\n  Trace: %x\n  Expected status: 200\n  Actual status: 500", i, i)
+				}
+				return ""
+			},
+			expected: []string{
+				"5/10 concurrent operations failed",
+				"5/10 failed with:\nPUT /kv/key-0",
+			},
+		},
+		{
+			name: "Same Failure Different Values",
+			fail: func(i int) string {
+				if i < 4 {
+					return fmt.Sprintf("GET /kv/key-%d\n  Expected response: \"value-%d\"\n  Actual response: \"\"", i, i)
+				}
+				return ""
+			},
+			expected: []string{
+				"4/10 concurrent operations failed",
+				"4/10 failed with:\nGET /kv/key-0\n  Expected response: \"value-0\"",
+			},
+		},
+		{
+			name: "Distinct Failures",
+			fail: func(i int) string {
+				switch {
+				case i < 6:
+					return fmt.Sprintf("GET /kv/key-%d\n  Expected status: 200\n  Actual status: 503 Service Unavailable", i)
+				case i < 8:
+					return fmt.Sprintf("GET /kv/key-%d\n  Expected status: 200\n  Actual status: 404 Not Found", i)
+				}
+				return ""
+			},
+			expected: []string{
+				"8/10 concurrent operations failed",
+				"6/10 failed with:\nGET /kv/key-0\n  Expected status: 200\n  Actual status: 503 Service Unavailable",
+				"2/10 failed with:\nGET /kv/key-6\n  Expected status: 200\n  Actual status: 404 Not Found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message string
+
+			config := &Config{WorkingDir: t.TempDir()}
+			New().WithConfig(config).
+				Test(tt.name, func(do *Do) {
+					defer func() {
+						message = fmt.Sprint(recover())
+					}()
+
+					var fns []func(*Do)
+					for i := range 10 {
+						// Failures are staggered so the first one in each group is deterministic
+						fns = append(fns, func(*Do) {
+							if msg := tt.fail(i); msg != "" {
+								time.Sleep(time.Duration(i) * 20 * time.Millisecond)
+								panic(msg)
+							}
+						})
+					}
+
+					do.Concurrently(fns...)
+				}).
+				Run(context.Background())
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(message, expected) {
+					t.Errorf("expected failure to contain %q, got:\n%s", expected, message)
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrentlyCancelsAfterCrash(t *testing.T) {
+	config := &Config{
+		Command:    helperCommand(t),
+		WorkingDir: t.TempDir(),
+		// Throttle hard so the queued requests would take far longer than the test allows
+		MaxRequestsPerSecond: 5,
+	}
+
+	var message string
+	start := time.Now()
+
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.Start("svc", "--exit-after=300ms")
+		}).
+		Test("Crash", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			var fns []func(*Do)
+			for range 50 {
+				fns = append(fns, func(do *Do) {
+					do.HTTP("svc", "GET", "/").
+						Consistently().For(2 * time.Second).T().
+						Status(Is(200)).
+						Assert("Server should stay up")
+				})
+			}
+
+			do.Concurrently(fns...)
+		}).
+		Run(context.Background())
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected remaining operations to be canceled quickly, took %s", elapsed)
+	}
+
+	expected := "concurrent operations were canceled after process svc exited unexpectedly"
+	if !strings.Contains(message, expected) {
+		t.Errorf("expected failure to contain %q, got:\n%s", expected, message)
+	}
+}
+
+func TestConcurrentlyCanceledRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	port := strings.Split(server.Listener.Addr().String(), ":")[1]
+	config := &Config{WorkingDir: t.TempDir()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var message string
+	start := time.Now()
+
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", port)
+		}).
+		Test("Interrupted", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			time.AfterFunc(200*time.Millisecond, cancel)
+
+			var fns []func(*Do)
+			for range 5 {
+				fns = append(fns, func(do *Do) {
+					// Never met, so only the canceled run ends the wait
+					do.HTTP("svc", "GET", "/").
+						Eventually().Within(5 * time.Second).T().
+						Status(Is(201)).
+						Assert("Server should create the key")
+				})
+			}
+
+			do.Concurrently(fns...)
+		}).
+		Run(ctx)
+
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected operations to be canceled with the run, took %s", elapsed)
+	}
+
+	expected := "5/5 concurrent operations were canceled because the run was canceled"
+	if !strings.Contains(message, expected) {
+		t.Errorf("expected failure to contain %q, got:\n%s", expected, message)
+	}
+
+	if strings.Contains(message, "exited unexpectedly") {
+		t.Errorf("expected no process to be blamed, got:\n%s", message)
+	}
+}
+
+func TestConcurrentlyGroupsAreIndependent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	port := strings.Split(server.Listener.Addr().String(), ":")[1]
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var message string
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", port)
+		}).
+		Test("Sibling Groups", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.Concurrently(
+				func(do *Do) {
+					// Finishes, and cancels its context, while the request below is in flight
+					do.Concurrently(func(*Do) {
+						time.Sleep(100 * time.Millisecond)
+					})
+				},
+				func(do *Do) {
+					time.Sleep(50 * time.Millisecond)
+					do.HTTP("svc", "GET", "/").T().
+						Status(Is(200)).
+						Assert("Request should outlive its sibling group")
+				},
+			)
+		}).
+		Run(context.Background())
+
+	if message != "<nil>" {
+		t.Errorf("expected the request to pass, got:\n%s", message)
+	}
+}
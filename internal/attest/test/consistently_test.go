@@ -0,0 +1,80 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestConsistentlyMinSamples(t *testing.T) {
+	tests := []struct {
+		name string
+		// failAt is the request the server starts failing at, if any
+		failAt     int64
+		expected   string
+		shouldPass bool
+	}{
+		{
+			name:       "Samples At Least Minimum",
+			shouldPass: true,
+		},
+		{
+			name:       "Reports Failing Sample",
+			failAt:     5,
+			expected:   "Consistently: sample 5 of at least 20 failed after",
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if n := requests.Add(1); tt.failAt > 0 && n >= tt.failAt {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			config := &Config{WorkingDir: t.TempDir()}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+					}).
+					Test(tt.name, func(do *Do) {
+						// The default poll interval would only sample twice in this window
+						do.HTTP("svc", "GET", "/").
+							Consistently().For(200 * time.Millisecond).MinSamples(20).T().
+							Status(Is(200)).
+							Assert("Server should stay available")
+					}).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed:\n%s", tt.name, output)
+				} else {
+					t.Errorf("%s test should fail but passed:\n%s", tt.name, output)
+				}
+			}
+
+			if tt.shouldPass && requests.Load() < 20 {
+				t.Errorf("expected at least 20 samples, got %d", requests.Load())
+			}
+
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expected, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// sessionServer issues a session cookie on login and greets logged-in users.
+func sessionServer(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/login":
+		http.SetCookie(w, &http.Cookie{
+			Name:     "session",
+			Value:    r.URL.Query().Get("user"),
+			Path:     "/",
+			MaxAge:   3600,
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+		})
+	case "/me":
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(cookie.Value))
+	}
+}
+
+func TestCookies(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Session OK",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "POST", "/login").Query("user", "alice").Session("alice").T().
+					Cookie("session", Is("alice")).
+					SetCookie("session", Contains("HttpOnly"), Contains("SameSite=Strict"), Contains("Max-Age=3600")).
+					Assert("Login should set a session cookie")
+
+				do.HTTP("svc", "GET", "/me").Session("alice").T().
+					Status(Is(200)).
+					Body(Is("alice")).
+					Assert("Session cookie should be sent back")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Separate Sessions",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "POST", "/login").Query("user", "alice").Session("alice").T().
+					Assert("Alice should log in")
+				do.HTTP("svc", "POST", "/login").Query("user", "bob").Session("bob").T().
+					Assert("Bob should log in")
+
+				do.HTTP("svc", "GET", "/me").Session("alice").T().
+					Body(Is("alice")).
+					Assert("Alice's session should be kept apart from Bob's")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "No Session",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "POST", "/login").Query("user", "alice").Session("alice").T().
+					Assert("Alice should log in")
+
+				do.HTTP("svc", "GET", "/me").T().
+					Status(Is(200)).
+					Assert("Should fail when cookies aren't sent outside the session")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Cookie Not Set",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/me").T().
+					Cookie("session", Is("alice")).
+					Assert("Should fail when the cookie isn't set")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Set-Cookie Attribute Missing",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "POST", "/login").Query("user", "alice").T().
+					SetCookie("session", Contains("Secure")).
+					Assert("Should fail when an attribute is missing")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(sessionServer))
+			defer server.Close()
+
+			port := strings.Split(server.URL, ":")[2]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
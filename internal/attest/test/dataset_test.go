@@ -0,0 +1,125 @@
+package attest_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestVerifyDataset(t *testing.T) {
+	data := make(map[string]string)
+	for i := range 100 {
+		data[fmt.Sprintf("key%d", i)] = fmt.Sprintf("value%d", i)
+	}
+
+	tests := []struct {
+		name       string
+		digest     bool
+		lost       string
+		gets       int64
+		digests    int64
+		shouldPass bool
+	}{
+		{
+			name:       "Digest Matches",
+			digest:     true,
+			digests:    2,
+			shouldPass: true,
+		},
+		{
+			name:       "No Digest Endpoint",
+			gets:       200,
+			digests:    1,
+			shouldPass: true,
+		},
+		{
+			name:       "Lost Key Found After Digest Mismatch",
+			digest:     true,
+			lost:       "key42",
+			gets:       38,
+			digests:    1,
+			shouldPass: false,
+		},
+		{
+			name:       "Lost Key Without Digest Endpoint",
+			lost:       "key42",
+			gets:       38,
+			digests:    1,
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gets, digests atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/digest" {
+					digests.Add(1)
+					if !tt.digest {
+						http.NotFound(w, r)
+						return
+					}
+
+					body, _ := io.ReadAll(r.Body)
+					hash := sha256.New()
+					for _, key := range strings.Split(string(body), "\n") {
+						if value, ok := data[key]; ok && key != tt.lost {
+							hash.Write([]byte(key + "\x00" + value + "\x00"))
+						}
+					}
+					w.Write([]byte(hex.EncodeToString(hash.Sum(nil))))
+					return
+				}
+
+				gets.Add(1)
+				key := strings.TrimPrefix(r.URL.Path, "/kv/")
+				if value, ok := data[key]; ok && key != tt.lost {
+					w.Write([]byte(value))
+					return
+				}
+				http.NotFound(w, r)
+			}))
+			defer server.Close()
+
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Dataset(DatasetSpec{Path: "/kv/%s", Digest: "/digest"}).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+				}).
+				Test(tt.name, func(do *Do) {
+					for range 2 {
+						do.VerifyDataset("svc", data, "Every key should survive")
+					}
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+
+			// Keys are read in sorted order until the first one that's wrong
+			if gets.Load() != tt.gets {
+				t.Errorf("expected %d GET requests, got %d", tt.gets, gets.Load())
+			}
+
+			if digests.Load() != tt.digests {
+				t.Errorf("expected %d digest requests, got %d", tt.digests, digests.Load())
+			}
+		})
+	}
+}
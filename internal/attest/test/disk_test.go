@@ -0,0 +1,95 @@
+package attest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestDiskFaults(t *testing.T) {
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var readOnlyErr, restoredErr error
+	var dataDir string
+	output := captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.MockProcess("node", "0")
+			}).
+			Test("Read-Only", func(do *Do) {
+				do.MakeDataDirReadOnly("node")
+				readOnlyErr = os.WriteFile(filepath.Join(do.DataDir("node"), "data.db"), []byte("x"), 0644)
+			}).
+			Test("Restored", func(do *Do) {
+				os.MkdirAll(do.DataDir("node"), 0755)
+				restoredErr = os.WriteFile(filepath.Join(do.DataDir("node"), "data.db"), []byte("x"), 0644)
+			}).
+			Test("Fill Disk", func(do *Do) {
+				dataDir = do.DataDir("node")
+				do.FillDisk("node")
+				t.Error("expected a large disk not to be filled")
+			}).
+			Run(context.Background())
+	})
+
+	if os.Geteuid() == 0 {
+		if !strings.Contains(output, "Read-Only (skipped: permissions don't apply to root") {
+			t.Errorf("expected the read-only test to be skipped for root, got:\n%s", output)
+		}
+	} else if readOnlyErr == nil {
+		t.Error("expected writing to a read-only data directory to fail")
+	}
+
+	if restoredErr != nil {
+		t.Errorf("expected the data directory to be writable in the next test: %v", restoredErr)
+	}
+
+	if !strings.Contains(output, "Fill Disk (skipped: ") {
+		t.Errorf("expected filling a large disk to be skipped, got:\n%s", output)
+	}
+
+	if _, err := os.Stat(filepath.Join(dataDir, ".lsfr-disk-filler")); err == nil {
+		t.Error("expected nothing to be written to a large disk")
+	}
+}
+
+func TestFillDisk(t *testing.T) {
+	config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+	var fullErr, freedErr error
+	output := captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.StartWith("node", []StartOption{WithDiskLimit(4 << 20)})
+			}).
+			Test("Full", func(do *Do) {
+				do.FillDisk("node")
+				fullErr = os.WriteFile(filepath.Join(do.DataDir("node"), "data.db"), make([]byte, 64<<10), 0644)
+			}).
+			Test("Freed", func(do *Do) {
+				freedErr = os.WriteFile(filepath.Join(do.DataDir("node"), "data.db"), make([]byte, 64<<10), 0644)
+			}).
+			Run(context.Background())
+	})
+
+	if strings.Contains(output, "Full (skipped: ") {
+		if os.Geteuid() == 0 {
+			t.Errorf("expected root to mount the disk limit, got:\n%s", output)
+		}
+		return
+	}
+
+	if !errors.Is(fullErr, syscall.ENOSPC) {
+		t.Errorf("expected writing to a full disk to fail with ENOSPC, got %v:\n%s", fullErr, output)
+	}
+
+	if freedErr != nil {
+		t.Errorf("expected the disk to have space again in the next test: %v", freedErr)
+	}
+}
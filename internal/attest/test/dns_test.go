@@ -0,0 +1,257 @@
+package attest_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// answerDNS answers queries for example.com, pointing back at the question's
+// name to exercise compression, and returns nil to stay silent.
+func answerDNS(query []byte) []byte {
+	end := 12
+	for query[end] != 0 {
+		end += int(query[end]) + 1
+	}
+	qname := string(query[12:end])
+	qtype := binary.BigEndian.Uint16(query[end+1:])
+	question := query[12 : end+5]
+
+	if strings.Contains(qname, "silent") {
+		return nil
+	}
+
+	type record struct {
+		rtype uint16
+		ttl   uint32
+		data  []byte
+	}
+
+	var records []record
+	rcode := uint16(0)
+	if qname != "\x07example\x03com" {
+		rcode = 3
+	} else {
+		switch qtype {
+		case 1:
+			records = []record{
+				{1, 300, []byte{93, 184, 216, 34}},
+				{1, 60, []byte{93, 184, 216, 35}},
+			}
+		case 15:
+			records = []record{{15, 3600, []byte{0, 10, 4, 'm', 'a', 'i', 'l', 0xc0, 12}}}
+		case 16:
+			records = []record{{16, 60, []byte("\x0bv=spf1 -all")}}
+		}
+	}
+
+	resp := append([]byte{}, query[:2]...)
+	resp = binary.BigEndian.AppendUint16(resp, 0x8180|rcode)
+	resp = binary.BigEndian.AppendUint16(resp, 1)
+	resp = binary.BigEndian.AppendUint16(resp, uint16(len(records)))
+	resp = append(resp, 0, 0, 0, 0)
+	resp = append(resp, question...)
+
+	for _, r := range records {
+		resp = append(resp, 0xc0, 12)
+		resp = binary.BigEndian.AppendUint16(resp, r.rtype)
+		resp = binary.BigEndian.AppendUint16(resp, 1)
+		resp = binary.BigEndian.AppendUint32(resp, r.ttl)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(r.data)))
+		resp = append(resp, r.data...)
+	}
+
+	return resp
+}
+
+func TestDNS(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "A Records",
+			testFunc: func(do *Do) {
+				do.DNS("svc", "A", "example.com").T().
+					RCode(Is(DNSNoError)).
+					AnswerCount(Is(2)).
+					Answer(0, Is("93.184.216.34")).
+					TTL(0, Is(300)).
+					Answer(1, Is("93.184.216.35")).
+					Assert("Server should resolve A records")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "MX Record",
+			testFunc: func(do *Do) {
+				do.DNS("svc", "MX", "example.com.").T().
+					Answer(0, Is("10 mail.example.com.")).
+					Assert("Server should resolve MX records")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "TXT Record",
+			testFunc: func(do *Do) {
+				do.DNS("svc", "TXT", "example.com").T().
+					Answer(0, Is(`"v=spf1 -all"`)).
+					Assert("Server should resolve TXT records")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "NXDOMAIN",
+			testFunc: func(do *Do) {
+				do.DNS("svc", "A", "missing.example.org").T().
+					RCode(Is(DNSNXDomain)).
+					AnswerCount(Is(0)).
+					Assert("Server should return NXDOMAIN for unknown names")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "RCode Mismatch",
+			testFunc: func(do *Do) {
+				do.DNS("svc", "A", "missing.example.org").T().
+					RCode(Is(DNSNoError)).
+					Assert("Should fail when the rcode doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "TTL Mismatch",
+			testFunc: func(do *Do) {
+				do.DNS("svc", "A", "example.com").T().
+					TTL(1, Is(300)).
+					Assert("Should fail when a TTL doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Missing Answer",
+			testFunc: func(do *Do) {
+				do.DNS("svc", "A", "example.com").T().
+					Answer(2, Is("93.184.216.36")).
+					Assert("Should fail when there are too few answers")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "No Response",
+			testFunc: func(do *Do) {
+				do.DNS("svc", "A", "silent.example.com").ReplyTimeout(200 * time.Millisecond).T().
+					RCode(Is(DNSNoError)).
+					Assert("Should fail when the server doesn't respond")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			go func() {
+				buf := make([]byte, 512)
+				for {
+					n, addr, err := conn.ReadFrom(buf)
+					if err != nil {
+						return
+					}
+
+					if resp := answerDNS(buf[:n]); resp != nil {
+						conn.WriteTo(resp, addr)
+					}
+				}
+			}()
+
+			port := strings.Split(conn.LocalAddr().String(), ":")[1]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
+
+func TestDNSQueryIDPerAttempt(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Fail the first two attempts so the query is retried
+	ids := make(chan uint16, 16)
+	go func() {
+		buf := make([]byte, 512)
+		for attempt := 0; ; attempt++ {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			ids <- binary.BigEndian.Uint16(buf)
+
+			resp := answerDNS(buf[:n])
+			if attempt < 2 {
+				resp[3] |= 2 // SERVFAIL
+			}
+			conn.WriteTo(resp, addr)
+		}
+	}()
+
+	port := strings.Split(conn.LocalAddr().String(), ":")[1]
+	config := &Config{WorkingDir: t.TempDir()}
+
+	success := New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", port)
+		}).
+		Test("Retried", func(do *Do) {
+			do.DNS("svc", "A", "example.com").Eventually().T().
+				RCode(Is(DNSNoError)).
+				Assert("Should pass once the server recovers")
+		}).
+		Run(context.Background())
+
+	if !success {
+		t.Fatal("expected the query to pass once the server recovers")
+	}
+
+	seen := make(map[uint16]bool)
+	for len(ids) > 0 {
+		id := <-ids
+		if seen[id] {
+			t.Errorf("expected a new query ID per attempt, got %d twice", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) < 3 {
+		t.Errorf("expected at least 3 attempts, got %d", len(seen))
+	}
+}
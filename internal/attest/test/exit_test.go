@@ -0,0 +1,135 @@
+package attest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestUnexpectedExit(t *testing.T) {
+	config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.Start("svc", "--exit-after=200ms")
+			}).
+			Test("Crash Between Assertions", func(do *Do) {
+				time.Sleep(500 * time.Millisecond)
+
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Assert("Process should stay up")
+			}).
+			Run(context.Background())
+	})
+
+	if success {
+		t.Fatalf("expected the test to fail:\n%s", output)
+	}
+
+	for _, expected := range []string{"Process svc exited unexpectedly with status 2", "shutting down unexpectedly"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if strings.Contains(output, "connection refused") {
+		t.Errorf("expected the exit to be reported instead of the failed request, got:\n%s", output)
+	}
+}
+
+func TestWaitExit(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		testFunc func(*Do)
+		// shutdownTimeout overrides how long processes get to exit after SIGTERM
+		shutdownTimeout time.Duration
+		expected        string
+		shouldPass      bool
+	}{
+		{
+			name: "Clean Shutdown",
+			args: []string{"--shutdown-delay=0s"},
+			testFunc: func(do *Do) {
+				do.Stop("svc")
+				do.WaitExit("svc").T().
+					ExitCode(Is(0)).
+					Assert("Process should exit cleanly on SIGTERM")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Killed After Timeout",
+			args: []string{"--shutdown-delay=1m"},
+			testFunc: func(do *Do) {
+				do.Stop("svc")
+				do.WaitExit("svc").T().
+					ExitCode(Is(0)).
+					Assert("Should fail when the process has to be killed")
+			},
+			shutdownTimeout: 300 * time.Millisecond,
+			expected:        "Actual: exited after SIGKILL",
+			shouldPass:      false,
+		},
+		{
+			name: "Exits By Itself",
+			args: []string{"--exit-after=100ms"},
+			testFunc: func(do *Do) {
+				do.WaitExit("svc").T().
+					ExitCode(Is(2)).
+					Assert("Process should exit with status 2")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Still Running",
+			testFunc: func(do *Do) {
+				do.WaitExit("svc").T().
+					ExitCode(Is(0)).
+					Assert("Should fail when the process doesn't exit")
+			},
+			expected:   "Actual: still running",
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Long enough for the process to exit on SIGTERM on a loaded machine
+			shutdownTimeout := 3 * time.Second
+			if tt.shutdownTimeout > 0 {
+				shutdownTimeout = tt.shutdownTimeout
+			}
+
+			config := &Config{
+				Command:                helperCommand(t),
+				WorkingDir:             t.TempDir(),
+				ProcessShutdownTimeout: shutdownTimeout,
+			}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.Start("svc", tt.args...)
+					}).
+					Test(tt.name, tt.testFunc).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Fatalf("expected success=%v, got %v:\n%s", tt.shouldPass, success, output)
+			}
+
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expected, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package attest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestFDCount(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Count",
+			testFunc: func(do *Do) {
+				do.FDCount("svc").T().
+					Count(GreaterThan(0), LessThan(100)).
+					Assert("Process should have a few files open")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "No Leak",
+			testFunc: func(do *Do) {
+				fds := do.FDCount("svc")
+				for range 20 {
+					do.HTTP("svc", "GET", "/").T().Status(Is(200)).Assert("Request should succeed")
+				}
+
+				fds.Eventually().T().
+					Growth(LessThan(10)).
+					Assert("Process shouldn't leak file descriptors")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Leak",
+			testFunc: func(do *Do) {
+				fds := do.FDCount("svc")
+				do.HTTP("svc", "GET", "/leak?n=50").T().Status(Is(200)).Assert("Request should succeed")
+
+				fds.T().
+					Growth(LessThan(10)).
+					Assert("Process shouldn't leak file descriptors")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.Start("svc")
+					}).
+					Test(tt.name, tt.testFunc).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Fatalf("expected success=%v, got %v:\n%s", tt.shouldPass, success, output)
+			}
+
+			if !tt.shouldPass && !strings.Contains(output, "Expected growth in open file descriptors: less than 10") {
+				t.Errorf("expected a growth mismatch, got:\n%s", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package attest_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestFuzz(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		crash      bool
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Malformed Requests OK",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, err := io.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			},
+			testFunc: func(do *Do) {
+				do.Fuzz("svc", MalformedHTTPRequests("/kv/key")...).T().
+					Status(Not(Is(200))).
+					Assert("Server should reject malformed requests")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Invalid UTF-8 Keys OK",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			testFunc: func(do *Do) {
+				do.Fuzz("svc", InvalidUTF8Requests("PUT", "/kv/")...).T().
+					Status(Is(400)).
+					Assert("Server should reject invalid keys")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Status Mismatch",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			testFunc: func(do *Do) {
+				do.Fuzz("svc", InvalidUTF8Requests("PUT", "/kv/")...).T().
+					Status(Not(Is(500))).
+					Assert("Should fail when server errors on invalid keys")
+			},
+			shouldPass: false,
+		},
+		{
+			name:  "Crash Detection",
+			crash: true,
+			testFunc: func(do *Do) {
+				do.Fuzz("svc", MalformedHTTPRequests("/kv/key")...).T().
+					Assert("Should fail when server stops accepting connections")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var port string
+			if tt.crash {
+				// Accept a single connection, then stop listening
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				go func() {
+					conn, err := listener.Accept()
+					listener.Close()
+					if err == nil {
+						conn.Close()
+					}
+				}()
+
+				port = strings.Split(listener.Addr().String(), ":")[1]
+			} else {
+				server := httptest.NewServer(tt.handler)
+				defer server.Close()
+
+				port = strings.Split(server.URL, ":")[2]
+			}
+
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
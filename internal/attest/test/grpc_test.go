@@ -0,0 +1,265 @@
+package attest_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+var (
+	getRequest = &ProtoDescriptor{
+		Name: "GetRequest",
+		Fields: []ProtoField{
+			{Name: "key", Number: 1, Type: ProtoString},
+		},
+	}
+
+	getResponse = &ProtoDescriptor{
+		Name: "GetResponse",
+		Fields: []ProtoField{
+			{Name: "value", Number: 1, Type: ProtoString},
+			{Name: "version", Number: 2, Type: ProtoInt64},
+			{Name: "tags", Number: 3, Type: ProtoString, Repeated: true},
+		},
+	}
+
+	getMethod = GRPCMethod{
+		Service:  "kv.v1.Store",
+		Method:   "Get",
+		Request:  getRequest,
+		Response: getResponse,
+	}
+)
+
+// grpcStore serves kv.v1.Store/Get over h2c with hand-encoded messages.
+// Key "a" exists, "old" has no version or tags, and anything else is NOT_FOUND.
+func grpcStore(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 {
+		w.WriteHeader(http.StatusHTTPVersionNotSupported)
+		return
+	}
+
+	if r.URL.Path != "/kv.v1.Store/Get" {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "12")
+		w.Header().Set("Grpc-Message", "unknown method")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	// Frame prefix, then field 1 (tag 0x0A) and its length
+	key := string(body[7:])
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	var message []byte
+	switch key {
+	case "a":
+		message = append(message, 0x0A, 1, '1')
+		message = append(message, 0x10, 7)
+		message = append(message, 0x1A, 3, 'n', 'e', 'w')
+		message = append(message, 0x1A, 3, 'h', 'o', 't')
+	case "old":
+		message = append(message, 0x0A, 1, '0')
+	default:
+		w.Header().Set("Grpc-Status", "5")
+		w.Header().Set("Grpc-Message", "key not found")
+		return
+	}
+
+	frame := []byte{0}
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(message)))
+	w.Write(append(frame, message...))
+
+	w.Header().Set("Grpc-Status", "0")
+}
+
+func TestGRPC(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Basic OK",
+			testFunc: func(do *Do) {
+				do.GRPC("svc", "kv.v1.Store", "Get", `{"key": "a"}`).T().
+					Status(Is(GRPCOK)).
+					JSON("value", Is("1")).
+					JSON("version", Is("7")).
+					JSON("tags", HasLen[string](2)).
+					JSON("tags.1", Is("hot")).
+					Assert("Get should return the stored value")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Default Values",
+			testFunc: func(do *Do) {
+				do.GRPC("svc", "kv.v1.Store", "Get", `{"key": "old"}`).T().
+					Status(Is(GRPCOK)).
+					JSON("version", Is("0")).
+					JSON("tags", HasLen[string](0)).
+					Assert("Unset fields should read as their defaults")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Not Found",
+			testFunc: func(do *Do) {
+				do.GRPC("svc", "kv.v1.Store", "Get", `{"key": "missing"}`).T().
+					Status(Is(GRPCNotFound)).
+					Assert("Get should return NOT_FOUND for missing keys")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Status Mismatch",
+			testFunc: func(do *Do) {
+				do.GRPC("svc", "kv.v1.Store", "Get", `{"key": "missing"}`).T().
+					Status(Is(GRPCOK)).
+					Assert("Should fail when the status doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Response Mismatch",
+			testFunc: func(do *Do) {
+				do.GRPC("svc", "kv.v1.Store", "Get", `{"key": "a"}`).T().
+					JSON("value", Is("2")).
+					Assert("Should fail when a field doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Unimplemented",
+			testFunc: func(do *Do) {
+				do.GRPC("svc", "kv.v1.Store", "Put", `{"key": "a"}`).T().
+					Status(Is(GRPCUnimplemented)).
+					Assert("Trailers-only responses should be understood")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Unregistered Method",
+			testFunc: func(do *Do) {
+				do.GRPC("svc", "kv.v1.Store", "Delete", `{"key": "a"}`).T().
+					Status(Is(GRPCOK)).
+					Assert("Should fail when the method has no descriptors")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Unknown Request Field",
+			testFunc: func(do *Do) {
+				do.GRPC("svc", "kv.v1.Store", "Get", `{"name": "a"}`).T().
+					Status(Is(GRPCOK)).
+					Assert("Should fail when the request doesn't match its descriptor")
+			},
+			shouldPass: false,
+		},
+	}
+
+	putMethod := getMethod
+	putMethod.Method = "Put"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(grpcStore))
+			server.Config.Protocols = new(http.Protocols)
+			server.Config.Protocols.SetUnencryptedHTTP2(true)
+			server.Start()
+			defer server.Close()
+
+			port := strings.Split(server.URL, ":")[2]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				GRPC(getMethod, putMethod).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCReusesConnections(t *testing.T) {
+	var mu sync.Mutex
+	opened, closed := 0, 0
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(grpcStore))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch state {
+		case http.StateNew:
+			opened++
+		case http.StateClosed:
+			closed++
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	port := strings.Split(server.URL, ":")[2]
+	success := New().WithConfig(&Config{WorkingDir: t.TempDir()}).
+		GRPC(getMethod).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", port)
+		}).
+		Test("Many Calls", func(do *Do) {
+			for range 50 {
+				do.GRPC("svc", "kv.v1.Store", "Get", `{"key": "a"}`).T().
+					Status(Is(GRPCOK)).
+					Assert("Get should return the stored value")
+			}
+		}).
+		Run(context.Background())
+
+	if !success {
+		t.Fatal("expected the calls to succeed")
+	}
+
+	// The server notices closed connections asynchronously
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		o, c := opened, closed
+		mu.Unlock()
+
+		if o == 1 && c == 1 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected one connection, closed once the run was done, got %d opened and %d closed", o, c)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
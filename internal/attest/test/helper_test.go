@@ -0,0 +1,226 @@
+package attest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// helperEnv makes the test binary act as a process under test when started by the harness.
+const helperEnv = "ATTEST_HELPER_PROCESS"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) == "1" {
+		runHelperProcess(os.Args[1:])
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// helperCommand returns a command that starts the test binary as its process.
+func helperCommand(t *testing.T) string {
+	t.Setenv(helperEnv, "1")
+	// The race detector otherwise delays every exit by a second
+	t.Setenv("GORACE", "atexit_sleep_ms=0")
+	return os.Args[0]
+}
+
+// runHelperProcess serves HTTP on the port or socket passed by the harness,
+// as arguments, PORT and PEER_PORT, or in ports.json in the working directory.
+// Behavior is controlled by extra arguments:
+//
+//	--announce: print LISTENING ON <port> once listening
+//	--exit-after=<duration>: exit with status 2 after the duration
+//	--log=<text>: print text to stdout shortly after startup
+//	--no-listen: run without listening, like a batch worker
+//	--healthy-after=<duration>: GET /healthz returns 503 until the duration has passed
+//	--peer-port=<port>: also serve PEER on the port
+//	--shutdown-delay=<duration>: exit with status 0 the duration after SIGTERM
+//	--tls-cert=<path> and --tls-key=<path>: serve HTTPS
+//	--udp: echo datagrams on the port instead, after a short delay
+//
+// GET /allocate?mb=<n> allocates and keeps n MiB before responding.
+// GET /leak?n=<n> opens n files and never closes them.
+func runHelperProcess(args []string) {
+	network, addr := "tcp", ""
+	certPath, keyPath := "", ""
+	listen, announce := true, false
+	healthyAt := time.Now()
+	if port := os.Getenv("PORT"); port != "" {
+		addr = "127.0.0.1:" + port
+	}
+	if port := os.Getenv("PEER_PORT"); port != "" {
+		servePeer(port)
+	}
+	for _, arg := range args {
+		key, value, _ := strings.Cut(arg, "=")
+		switch key {
+		case "--port":
+			addr = "127.0.0.1:" + value
+		case "--socket":
+			network, addr = "unix", value
+		case "--working-dir":
+			var ports struct {
+				Port     int `json:"port"`
+				PeerPort int `json:"peer_port"`
+			}
+			data, err := os.ReadFile(value + "/ports.json")
+			if err == nil && json.Unmarshal(data, &ports) == nil {
+				addr = fmt.Sprintf("127.0.0.1:%d", ports.Port)
+				if ports.PeerPort != 0 {
+					servePeer(strconv.Itoa(ports.PeerPort))
+				}
+			}
+		case "--exit-after":
+			duration, _ := time.ParseDuration(value)
+			go func() {
+				time.Sleep(duration)
+				fmt.Println("shutting down unexpectedly")
+				os.Exit(2)
+			}()
+		case "--shutdown-delay":
+			duration, _ := time.ParseDuration(value)
+			terminated := make(chan os.Signal, 1)
+			signal.Notify(terminated, syscall.SIGTERM)
+			go func() {
+				<-terminated
+				time.Sleep(duration)
+				fmt.Println("shutting down")
+				os.Exit(0)
+			}()
+		case "--tls-cert":
+			certPath = value
+		case "--tls-key":
+			keyPath = value
+		case "--log":
+			go func() {
+				time.Sleep(200 * time.Millisecond)
+				fmt.Println(value)
+			}()
+		case "--announce":
+			announce = true
+		case "--udp":
+			network = "udp"
+		case "--no-listen":
+			listen = false
+		case "--peer-port":
+			servePeer(value)
+		case "--healthy-after":
+			duration, _ := time.ParseDuration(value)
+			healthyAt = time.Now().Add(duration)
+		}
+	}
+
+	if !listen {
+		time.Sleep(time.Hour)
+		return
+	}
+
+	if network == "udp" {
+		// Bind late so readiness has to wait for it
+		time.Sleep(200 * time.Millisecond)
+		conn, err := net.ListenPacket(network, addr)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		buf := make([]byte, 1024)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(buf[:n], from)
+		}
+	}
+
+	listener, err := net.Listen(network, addr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if announce {
+		fmt.Printf("LISTENING ON %d\n", listener.Addr().(*net.TCPAddr).Port)
+	}
+
+	var kept [][]byte
+	var leaked []*os.File
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/allocate" {
+			mb, _ := strconv.Atoi(r.URL.Query().Get("mb"))
+			for range mb {
+				chunk := make([]byte, 1<<20)
+				for i := range chunk {
+					chunk[i] = 1
+				}
+				kept = append(kept, chunk)
+			}
+		}
+
+		if r.URL.Path == "/healthz" && time.Now().Before(healthyAt) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		if r.URL.Path == "/leak" {
+			n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+			for range n {
+				f, err := os.Open(os.DevNull)
+				if err == nil {
+					leaked = append(leaked, f)
+				}
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+
+	if certPath != "" {
+		http.ServeTLS(listener, handler, certPath, keyPath)
+		return
+	}
+
+	http.Serve(listener, handler)
+}
+
+// servePeer serves PEER on port in the background.
+func servePeer(port string) {
+	go http.ListenAndServe("127.0.0.1:"+port, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("PEER"))
+	}))
+}
+
+// captureStdout returns everything fn prints to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+
+	fn()
+	w.Close()
+
+	return <-output
+}
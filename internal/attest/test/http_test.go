@@ -2,12 +2,20 @@ package attest_test
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"math/rand/v2"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	. "github.com/st3v3nmw/lsfr/internal/attest"
 )
@@ -197,6 +205,45 @@ func TestHTTP(t *testing.T) {
 			},
 			shouldPass: false,
 		},
+		{
+			name: "Consistently NonDecreasing",
+			handler: func() http.HandlerFunc {
+				var term atomic.Int64
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					fmt.Fprintf(w, `{"term":%d}`, term.Add(1)/2)
+				}
+			}(),
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").
+					Consistently().For(300 * time.Millisecond).T().
+					Body(JSON("term", NonDecreasing[int]())).
+					Assert("Term should never decrease")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Consistently NonDecreasing Failure",
+			handler: func() http.HandlerFunc {
+				var polls atomic.Int64
+				return func(w http.ResponseWriter, r *http.Request) {
+					term := 3
+					if polls.Add(1) > 2 {
+						term = 2
+					}
+
+					w.WriteHeader(http.StatusOK)
+					fmt.Fprintf(w, `{"term":%d}`, term)
+				}
+			}(),
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").
+					Consistently().For(300 * time.Millisecond).T().
+					Body(JSON("term", NonDecreasing[int]())).
+					Assert("Should fail when the term goes back")
+			},
+			shouldPass: false,
+		},
 		{
 			name: "Consistently Cancellation",
 			handler: func(w http.ResponseWriter, r *http.Request) {
@@ -246,6 +293,48 @@ func TestHTTP(t *testing.T) {
 			},
 			shouldPass: false,
 		},
+		{
+			name: "HasPrefix and HasSuffix Checkers - match",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("Error: key not found"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Body(HasPrefix("Error:"), HasSuffix("not found")).
+					Assert("Should accept response with the prefix and suffix")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "HasPrefix Checker - fails when prefix is elsewhere",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("Fatal Error: key not found"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Body(HasPrefix("Error:")).
+					Assert("Should fail when the response doesn't start with the prefix")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "HasSuffix Checker - fails when suffix is elsewhere",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("not found: key"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Body(HasSuffix("not found")).
+					Assert("Should fail when the response doesn't end with the suffix")
+			},
+			shouldPass: false,
+		},
 		{
 			name: "Matches Checker - matches regex pattern",
 			handler: func(w http.ResponseWriter, r *http.Request) {
@@ -474,92 +563,1405 @@ func TestHTTP(t *testing.T) {
 			shouldPass: false,
 		},
 		{
-			name: "Multiple Checkers - multiple status checkers",
+			name: "Ordered Checkers - numeric JSON fields",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"term":3,"commit":1.5,"voted":true}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					Status(Between(200, 299)).
+					Body(JSON("term", GreaterThan(1))).
+					Body(JSON("term", Between(1, 3))).
+					Body(JSON("commit", LessThan(2.0))).
+					Body(JSON("voted", Is(true))).
+					Assert("Should compare JSON fields as numbers and booleans")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Ordered Checkers - GreaterThan fails",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"term":10}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					Body(JSON("term", GreaterThan(10))).
+					Assert("Should fail when the field isn't greater")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Ordered Checkers - numeric checker on string field",
 			handler: func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"term":"5"}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					Body(JSON("term", GreaterThan(1))).
+					Assert("Should fail when the field isn't a number")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Ordered Checkers - Between fails",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
 			},
 			testFunc: func(do *Do) {
 				do.HTTP("svc", "GET", "/").T().
-					Status(Is(200), Not(Is(404)), Not(Is(500))).
-					Assert("Should pass when all status checkers pass")
+					Status(Between(200, 299)).
+					Assert("Should fail when the status is out of range")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "JSON Array Checkers - length and membership",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"log":[{"term":1},{"term":2},{"term":2}],"members":["node-1","node-3"],"ids":[4,7]}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster").T().
+					Body(JSONLen("log", Is(3))).
+					Body(JSONLen("log.0", Is(1))).
+					Body(JSONContains("members", Is("node-3"))).
+					Body(JSONContains("ids", GreaterThan(5))).
+					Assert("Should check array lengths and elements")
 			},
 			shouldPass: true,
 		},
 		{
-			name: "Multiple Checkers - multiple body checkers",
+			name: "JSON Array Checkers - length mismatch",
 			handler: func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(http.StatusOK)
-				w.Write([]byte("Hello World"))
+				w.Write([]byte(`{"log":[{"term":1},{"term":2}]}`))
 			},
 			testFunc: func(do *Do) {
-				do.HTTP("svc", "GET", "/").T().
-					Status(Is(200)).
-					Body(Contains("Hello"), Contains("World"), Not(Contains("Goodbye"))).
-					Assert("Should pass when all body checkers pass")
+				do.HTTP("svc", "GET", "/cluster").T().
+					Body(JSONLen("log", Is(3))).
+					Assert("Should fail when the array is too short")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "JSON Array Checkers - no matching element",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"members":["node-1","node-2"]}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster").T().
+					Body(JSONContains("members", Is("node-3"))).
+					Assert("Should fail when no element matches")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "JSON Array Checkers - not an array",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"members":"node-3"}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster").T().
+					Body(JSONContains("members", Is("node-3"))).
+					Assert("Should fail when the field isn't an array")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Capture",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "POST" && r.URL.Path == "/items":
+					w.Header().Set("ETag", `"v1"`)
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"id":"abc123","version":1}`))
+				case r.Method == "GET" && r.URL.Path == "/items/abc123" && r.Header.Get("If-None-Match") == `"v1"`:
+					w.WriteHeader(http.StatusNotModified)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			},
+			testFunc: func(do *Do) {
+				var id, etag string
+				var version int
+				do.HTTP("svc", "POST", "/items", `{"name":"widget"}`).T().
+					Status(Is(201)).
+					Header("ETag", Capture(&etag)).
+					JSON("id", Capture(&id)).
+					Body(JSONCapture("version", &version)).
+					Assert("Should capture the created resource's ID")
+
+				if version != 1 {
+					panic(fmt.Sprintf("captured version %d, want 1", version))
+				}
+
+				do.HTTP("svc", "GET", "/items/"+id).
+					Header("If-None-Match", etag).T().
+					Status(Is(304)).
+					Assert("Should GET the resource by the captured ID")
 			},
 			shouldPass: true,
 		},
 		{
-			name: "Multiple Checkers - multiple JSON checkers on same field",
+			name: "Capture - missing field",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"name":"widget"}`))
+			},
+			testFunc: func(do *Do) {
+				var id string
+				do.HTTP("svc", "POST", "/items", `{"name":"widget"}`).T().
+					Body(JSONCapture("id", &id)).
+					Assert("Should fail when there's nothing to capture")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "YAML Checkers",
 			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/yaml")
 				w.WriteHeader(http.StatusOK)
-				w.Write([]byte(`{"role":"leader"}`))
+				w.Write([]byte("cluster:\n  name: demo\n  replicas: 3\nnodes:\n  - name: node-1\n    leader: true\n  - name: node-2\n"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/config").T().
+					YAML("cluster.name", Is("demo")).
+					YAML("nodes.1.name", Is("node-2")).
+					Body(YAML("cluster.replicas", Is(3)), YAML("nodes.0.leader", Is(true))).
+					Body(YAML("nodes", HasLen[string](2))).
+					Assert("Should check YAML fields")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "YAML Checkers - mismatch",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("cluster:\n  replicas: \"3\"\n"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/config").T().
+					Body(YAML("cluster.replicas", Is(3))).
+					Assert("Should fail when the field has the wrong type")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "YAML Checkers - invalid YAML",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("cluster: [unclosed\n"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/config").T().
+					YAML("cluster", NotEmpty()).
+					Assert("Should fail when the body isn't YAML")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Time Checkers",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				now := time.Now().UTC()
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintf(w, `{"created":%q,"expires_at":%q}`, now.Format(time.RFC3339Nano), now.Add(time.Minute).Format(time.RFC3339))
+			},
+			testFunc: func(do *Do) {
+				start := time.Now().Add(-time.Second)
+				do.HTTP("svc", "GET", "/jobs/1").T().
+					JSON("created", IsRFC3339(), After(start)).
+					JSON("expires_at", WithinDuration(time.Now().Add(time.Minute), 5*time.Second)).
+					Assert("Should check timestamps semantically")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Time Checkers - not a timestamp",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"created":"1700000000"}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/jobs/1").T().
+					JSON("created", IsRFC3339()).
+					Assert("Should fail for a Unix timestamp")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Time Checkers - expired too early",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintf(w, `{"expires_at":%q}`, time.Now().Format(time.RFC3339))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/jobs/1").T().
+					JSON("expires_at", WithinDuration(time.Now().Add(time.Hour), time.Minute)).
+					Assert("Should fail outside the duration")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "CloseTo",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"avg_latency_ms":12.46,"score":0.3}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/metrics").T().
+					Body(JSON("avg_latency_ms", CloseTo(12.5, 0.05))).
+					Body(JSON("score", CloseTo(0.3, 1e-9))).
+					Assert("Should pass within the tolerance")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "CloseTo - outside tolerance",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"avg_latency_ms":13}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/metrics").T().
+					Body(JSON("avg_latency_ms", CloseTo(12.5, 0.1))).
+					Assert("Should fail outside the tolerance")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Empty Checkers",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"leader":"node-1","error":"","peers":[],"meta":{},"last":null}`))
 			},
 			testFunc: func(do *Do) {
 				do.HTTP("svc", "GET", "/cluster/info").T().
-					Status(Is(200)).
-					JSON("role", Is("leader"), Not(Is("follower")), Not(Is("candidate"))).
-					Assert("Should pass when all checkers for the same JSON field pass")
+					Body(NotEmpty()).
+					JSON("leader", NotEmpty()).
+					JSON("error", Empty()).
+					JSON("peers", Empty()).
+					JSON("meta", Empty()).
+					JSON("last", Empty()).
+					JSON("missing", Empty()).
+					Assert("Should treat missing, null, \"\", [] and {} as empty")
 			},
 			shouldPass: true,
 		},
 		{
-			name: "Multiple Checkers - fails when one checker fails",
+			name: "Empty Checkers - leader not set",
 			handler: func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(http.StatusOK)
-				w.Write([]byte("Hello World"))
+				w.Write([]byte(`{"leader":null}`))
 			},
 			testFunc: func(do *Do) {
-				do.HTTP("svc", "GET", "/").T().
-					Status(Is(200)).
-					Body(Contains("Hello"), Contains("Goodbye")).
-					Assert("Should fail when one of the checkers fails")
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					JSON("leader", NotEmpty()).
+					Assert("Should fail when the field is null")
 			},
 			shouldPass: false,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			server := httptest.NewServer(tt.handler)
-			defer server.Close()
-
-			port := strings.Split(server.URL, ":")[2]
-
-			if tt.config == nil {
-				tt.config = &Config{}
-			}
-			tt.config.WorkingDir = t.TempDir()
-
-			suite := New().WithConfig(tt.config)
-
-			success := suite.
-				Setup(func(do *Do) {
-					do.MockProcess("svc", port)
-					if tt.cancel != nil {
-						tt.cancel(do)
-					}
-				}).
-				Test(tt.name, func(do *Do) {
-					tt.testFunc(do)
-				}).
-				Run(context.Background())
-
-			if success != tt.shouldPass {
-				if tt.shouldPass {
-					t.Errorf("%s test should pass but failed", tt.name)
-				} else {
-					t.Errorf("%s test should fail but passed", tt.name)
+		{
+			name: "Empty Checkers - negated empty array",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"peers":[]}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					JSON("peers", Not(Empty())).
+					Assert("Should fail when the array is empty")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Empty Checkers - negated empty object",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"meta":{}}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					JSON("meta", Not(Empty())).
+					Assert("Should fail when the object is empty")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Empty Checkers - body not empty",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("error: key not found"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").T().
+					Body(Empty()).
+					Assert("Should fail when the body has content")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "EqualFold",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Connection", "Keep-Alive")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("Node-1.Local"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/leader").T().
+					Header("Connection", EqualFold("keep-alive")).
+					Body(EqualFold("node-1.local")).
+					Assert("Should ignore case")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "EqualFold - different value",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("node-2.local"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/leader").T().
+					Body(EqualFold("NODE-1.LOCAL")).
+					Assert("Should fail when the values differ beyond case")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "CheckFunc",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"created":"2024-01-02T03:04:05Z","term":4}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").T().
+					JSON("created", CheckFunc("an RFC 3339 time", func(s string) bool {
+						_, err := time.Parse(time.RFC3339, s)
+						return err == nil
+					})).
+					Body(JSON("term", CheckFunc("even", func(n int) bool { return n%2 == 0 }))).
+					Assert("Should pass when the predicates hold")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "CheckFunc - fails",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"created":"yesterday"}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").T().
+					JSON("created", CheckFunc("an RFC 3339 time", func(s string) bool {
+						_, err := time.Parse(time.RFC3339, s)
+						return err == nil
+					})).
+					Assert("Should fail when the predicate doesn't hold")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Combinators - any of",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTemporaryRedirect)
+				w.Write([]byte(`{"leader":null,"term":3}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").T().
+					Status(AnyOf(Is(200), Is(307))).
+					JSON("leader", AnyOf(IsNull[string](), HasPrefix("node-"))).
+					JSON("term", AllOf(IsNumber(), Not(Is("0")))).
+					Assert("Should accept either alternative")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Combinators - none of",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").T().
+					Status(AnyOf(Is(200), Is(307))).
+					Assert("Should fail when no alternative matches")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Combinators - all of",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("leader: node-2"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").T().
+					Body(AllOf(HasPrefix("leader"), HasSuffix("node-1"))).
+					Assert("Should fail when one checker fails")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Or - second alternative",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", "/kv/key?leader=node-2")
+				w.WriteHeader(http.StatusTemporaryRedirect)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").NoFollowRedirects().T().
+					Or(
+						func(a *HTTPAssert) { a.Status(Is(200)).Body(Is("v1")) },
+						func(a *HTTPAssert) { a.Status(Is(307)).Location(Contains("leader=")) },
+					).
+					Assert("Should accept a redirect to the leader")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Or - none",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("v2"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").NoFollowRedirects().T().
+					Or(
+						func(a *HTTPAssert) { a.Status(Is(200)).Body(Is("v1")) },
+						func(a *HTTPAssert) { a.Status(Is(307)).Location(Contains("leader=")) },
+					).
+					Assert("Should fail when no alternative matches as a whole")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Or - other checkers still apply",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("v1"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/key").T().
+					Header("X-Term", Is("2")).
+					Or(
+						func(a *HTTPAssert) { a.Status(Is(200)).Body(Is("v1")) },
+						func(a *HTTPAssert) { a.Status(Is(307)) },
+					).
+					Assert("Should fail when a checker outside the alternatives fails")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "JSON Type Checkers - match",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"term":1,"leader":"node-1","voted":false,"log":[],"state":{}}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					Body(IsObject()).
+					JSON("term", IsNumber(), Is("1")).
+					JSON("leader", IsString()).
+					JSON("voted", IsBool()).
+					JSON("log", IsArray()).
+					JSON("state", IsObject()).
+					Assert("Should check the type of each field")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "JSON Type Checkers - number as string",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"term":"1"}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					JSON("term", IsNumber(), Is("1")).
+					Assert("Should fail when a number is sent as a string")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "JSON Type Checkers - negated",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"term":1}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					JSON("term", Not(IsString())).
+					Assert("Should pass when the field isn't a string")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "JSON Type Checkers - negated string",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"name":"abc"}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					JSON("name", Not(IsString())).
+					Assert("Should fail when the field is a string")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Multiple Checkers - multiple status checkers",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200), Not(Is(404)), Not(Is(500))).
+					Assert("Should pass when all status checkers pass")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Multiple Checkers - multiple body checkers",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("Hello World"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Body(Contains("Hello"), Contains("World"), Not(Contains("Goodbye"))).
+					Assert("Should pass when all body checkers pass")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Multiple Checkers - multiple JSON checkers on same field",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"role":"leader"}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/cluster/info").T().
+					Status(Is(200)).
+					JSON("role", Is("leader"), Not(Is("follower")), Not(Is("candidate"))).
+					Assert("Should pass when all checkers for the same JSON field pass")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Multiple Checkers - fails when one checker fails",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("Hello World"))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Body(Contains("Hello"), Contains("Goodbye")).
+					Assert("Should fail when one of the checkers fails")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Rate Limit",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			config: &Config{MaxRequestsPerSecond: 20},
+			testFunc: func(do *Do) {
+				start := time.Now()
+				for range 30 {
+					do.HTTP("svc", "GET", "/").T().
+						Status(Is(200)).
+						Assert("Server should respond to throttled requests")
+				}
+
+				if time.Since(start) < 400*time.Millisecond {
+					panic("requests were not throttled")
+				}
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Trace Header",
+			handler: func() http.HandlerFunc {
+				seen := map[string]bool{}
+				return func(w http.ResponseWriter, r *http.Request) {
+					traceID := r.Header.Get(TraceHeader)
+					if traceID == "" || seen[traceID] {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+
+					seen[traceID] = true
+					w.WriteHeader(http.StatusOK)
+				}
+			}(),
+			testFunc: func(do *Do) {
+				for range 3 {
+					do.HTTP("svc", "GET", "/").T().
+						Status(Is(200)).
+						Assert("Each assertion should send a unique trace header")
+				}
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Header OK",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Add("Vary", "Accept")
+				w.Header().Add("Vary", "Accept-Encoding")
+				w.Write([]byte(`{}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Header("content-type", Is("application/json")).
+					Header("Vary", Is("Accept, Accept-Encoding")).
+					Assert("Response headers should match")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Header Mismatch",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte(`{}`))
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Header("Content-Type", Is("application/json")).
+					Assert("Should fail when a header doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Header Missing",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Header("Cache-Control", Contains("max-age")).
+					Assert("Should fail when a header is missing")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Request Builder",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				fmt.Fprintf(w, "%s|%s|%s|%s", r.URL.RawQuery, r.Header.Get("Content-Type"),
+					r.Header.Get("Authorization"), body)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "POST", "/search?page=2").
+					Query("q", "a b&c").
+					Header("Authorization", "Bearer token").
+					BodyJSON(map[string]any{"limit": 10}).T().
+					Body(Is(`page=2&q=a+b%26c|application/json|Bearer token|{"limit":10}`)).
+					Assert("Builder should set query, headers and body")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Request Builder Keeps Content-Type",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				fmt.Fprintf(w, "%s|%s", r.Header.Get("Content-Type"), body)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "PUT", "/kv/a").
+					Header("content-type", "application/merge-patch+json").
+					BodyJSON([]int{1, 2}).T().
+					Body(Is(`application/merge-patch+json|[1,2]`)).
+					Assert("BodyJSON shouldn't override an explicit Content-Type")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Request Builder Body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				w.Write(body)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "PUT", "/kv/a").Body("Nairobi").T().
+					Body(Is("Nairobi")).
+					Assert("Body should set the request body")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Binary Body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				fmt.Fprintf(w, "%d %x", r.ContentLength, body)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "PUT", "/objects/a", []byte{0x00, 0xff, 0xfe}).T().
+					Body(Is("3 00fffe")).
+					Assert("Binary bodies should be sent as-is")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Body From File",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				fmt.Fprintf(w, "%d %d", r.ContentLength, len(body))
+			},
+			testFunc: func(do *Do) {
+				file, _ := os.CreateTemp("", "upload-*.bin")
+				file.Write(make([]byte, 1<<20))
+				file.Close()
+				defer os.Remove(file.Name())
+
+				do.HTTP("svc", "PUT", "/objects/a").BodyFromFile(file.Name()).Eventually().T().
+					Body(Is("1048576 1048576")).
+					Assert("File bodies should be streamed with their length")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Body From Reader",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				w.Write(body)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "PUT", "/objects/a", strings.NewReader("streamed")).Eventually().T().
+					Body(Is("streamed")).
+					Assert("Reader bodies should be sent")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Body From Reader Retried",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				w.Write(body)
+			},
+			testFunc: func(do *Do) {
+				reader := io.MultiReader(strings.NewReader("once"))
+				do.HTTP("svc", "PUT", "/objects/a", reader).Eventually().T().
+					Body(Is("twice")).
+					Assert("Should fail when a one-shot reader has to be resent")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Multipart Upload",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				file, header, err := r.FormFile("file")
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				content, _ := io.ReadAll(file)
+				fmt.Fprintf(w, "%s|%s|%s", r.FormValue("title"), header.Filename, content)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "POST", "/upload").
+					Header("Content-Type", "text/plain").
+					FormField("title", "Nairobi").
+					FormFile("file", "skyline.jpg", []byte{0xff, 0xd8}).T().
+					Status(Is(200)).
+					Body(Is("Nairobi|skyline.jpg|\xff\xd8")).
+					Assert("Form fields and files should be uploaded as multipart/form-data")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Multipart Response Parts",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writer := multipart.NewWriter(w)
+				w.Header().Set("Content-Type", writer.FormDataContentType())
+				writer.WriteField("city", "Nairobi")
+				part, _ := writer.CreateFormFile("thumbnail", "thumb.png")
+				part.Write([]byte("png-bytes"))
+				writer.Close()
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/objects/a").T().
+					Body(FormPart("city", Is("Nairobi"))).
+					Body(FormPart("thumbnail", Contains("png"))).
+					Assert("FormPart should check parts of a multipart body")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Multipart Response Part Missing",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				writer := multipart.NewWriter(w)
+				writer.WriteField("city", "Nairobi")
+				writer.Close()
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/objects/a").T().
+					Body(FormPart("country", Is("Kenya"))).
+					Assert("Should fail when a part is missing")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Duration OK",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Duration(LessThan(5 * time.Second)).
+					Assert("Server should respond quickly")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Duration Exceeded",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+				w.WriteHeader(http.StatusOK)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Duration(LessThan(50 * time.Millisecond)).
+					Assert("Should fail when the server is too slow")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Redirect Not Followed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/leader/kv/a" {
+					w.Write([]byte("from leader"))
+					return
+				}
+				http.Redirect(w, r, "/leader"+r.URL.Path, http.StatusTemporaryRedirect)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "PUT", "/kv/a", "1").NoFollowRedirects().T().
+					Status(Is(307)).
+					Location(Is(do.URL("svc", "/leader/kv/a"))).
+					Assert("Followers should redirect clients to the leader")
+
+				do.HTTP("svc", "PUT", "/kv/a", "1").T().
+					Status(Is(200)).
+					Body(Is("from leader")).
+					Assert("Redirects should be followed by default")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Location Mismatch",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "http://127.0.0.1:1/kv/a", http.StatusTemporaryRedirect)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/a").NoFollowRedirects().T().
+					Location(Is(do.URL("svc", "/kv/a"))).
+					Assert("Should fail when the redirect points elsewhere")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Location Missing",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/a").NoFollowRedirects().T().
+					Location(Contains("/kv/a")).
+					Assert("Should fail when there's no redirect")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			port := strings.Split(server.URL, ":")[2]
+
+			if tt.config == nil {
+				tt.config = &Config{}
+			}
+			tt.config.WorkingDir = t.TempDir()
+
+			suite := New().WithConfig(tt.config)
+
+			success := suite.
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+					if tt.cancel != nil {
+						tt.cancel(do)
+					}
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "svc.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("over " + r.URL.Path))
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	config := &Config{WorkingDir: t.TempDir()}
+
+	success := New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockSocketProcess("svc", socketPath)
+		}).
+		Test("Unix Socket OK", func(do *Do) {
+			do.HTTP("svc", "GET", "/socket").T().
+				Status(Is(200)).
+				Body(Is("over /socket")).
+				Assert("Server should respond over the unix socket")
+		}).
+		Run(context.Background())
+
+	if !success {
+		t.Errorf("Unix Socket OK test should pass but failed")
+	}
+}
+
+func TestHTTPReportsAllMismatches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"key":"a","value":"2"}`))
+	}))
+	defer server.Close()
+
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+		}).
+		Test("Mismatches", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.HTTP("svc", "GET", "/kv/a").T().
+				Status(Is(200)).
+				JSON("key", Is("a")).
+				JSON("value", Is("1")).
+				JSON("version", Is("3")).
+				Assert("Every mismatch should be reported")
+		}).
+		Run(context.Background())
+
+	expected := []string{
+		"Expected status: 200\n  Actual status: 500 Internal Server Error",
+		"Expected JSON fields:",
+		`key      a         "a"`,
+		`value    1         "2"`,
+		`version  3         (missing)`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(message, e) {
+			t.Errorf("expected failure to contain %q, got:\n%s", e, message)
+		}
+	}
+}
+
+func TestHTTPYAMLReportsFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		assert   func(*HTTPAssert)
+		expected []string
+	}{
+		{
+			name: "Single Field",
+			body: "cluster:\n  name: prod\n",
+			assert: func(a *HTTPAssert) {
+				a.YAML("cluster.name", Is("demo"))
+			},
+			expected: []string{"Expected YAML: field cluster.name: demo\n  Actual value: cluster:\n  name: prod"},
+		},
+		{
+			name: "Several Fields",
+			body: "cluster:\n  name: prod\nnodes:\n  - name: node-1\n",
+			assert: func(a *HTTPAssert) {
+				a.YAML("cluster.name", Is("demo")).YAML("nodes.0.name", Is("node-1")).YAML("nodes.1.name", Is("node-2"))
+			},
+			expected: []string{
+				"Expected YAML fields:",
+				`cluster.name  demo      "prod"`,
+				`nodes.0.name  node-1    "node-1"`,
+				`nodes.1.name  node-2    (missing)`,
+			},
+		},
+		{
+			name: "Invalid YAML",
+			body: "cluster: [unclosed\n",
+			assert: func(a *HTTPAssert) {
+				a.YAML("cluster", NotEmpty())
+			},
+			expected: []string{"Expected YAML: a valid document\n  Actual error:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			var message string
+
+			config := &Config{WorkingDir: t.TempDir()}
+			New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+				}).
+				Test(tt.name, func(do *Do) {
+					defer func() {
+						message = fmt.Sprint(recover())
+					}()
+
+					a := do.HTTP("svc", "GET", "/config").T()
+					tt.assert(a)
+					a.Assert("YAML mismatches should be reported as YAML")
+				}).
+				Run(context.Background())
+
+			for _, e := range tt.expected {
+				if !strings.Contains(message, e) {
+					t.Errorf("expected failure to contain %q, got:\n%s", e, message)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPOrReportsAlternatives(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+		}).
+		Test("Alternatives", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.HTTP("svc", "GET", "/kv/a").NoFollowRedirects().T().
+				Or(
+					func(a *HTTPAssert) { a.Status(Is(200)).Body(Is("v1")) },
+					func(a *HTTPAssert) { a.Status(Is(307)).Location(Contains("leader=")) },
+				).
+				Assert("Either alternative should match")
+		}).
+		Run(context.Background())
+
+	expected := "Expected one of these alternatives to match:\n" +
+		"    Alternative 1:\n" +
+		"      Expected status: 200\n" +
+		"      Actual status: 503 Service Unavailable\n" +
+		"      Expected response: v1\n"
+	if !strings.Contains(message, expected) {
+		t.Errorf("expected failure to contain %q, got:\n%s", expected, message)
+	}
+
+	if !strings.Contains(message, "    Alternative 2:\n      Expected status: 307") {
+		t.Errorf("expected failure to describe the second alternative, got:\n%s", message)
+	}
+}
+
+func TestHTTPContainsReportsMissing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ready\nstarted\n"))
+	}))
+	defer server.Close()
+
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+		}).
+		Test("Contains", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.HTTP("svc", "GET", "/log").T().
+				Body(ContainsAll("started", "elected", "ready", "serving")).
+				Body(ContainsInOrder("started", "ready")).
+				Assert("Missing and out of order lines should be reported")
+		}).
+		Run(context.Background())
+
+	expected := []string{
+		`Expected response: containing all of "started", "elected", "ready", "serving"`,
+		`Expected response: containing "started", "ready" in order`,
+		"Differences:\n    missing \"elected\"\n    missing \"serving\"\n    \"ready\" appears, but not after \"started\"",
+	}
+	for _, e := range expected {
+		if !strings.Contains(message, e) {
+			t.Errorf("expected failure to contain %q, got:\n%s", e, message)
+		}
+	}
+}
+
+func TestHTTPDescribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"leader":"127.0.0.1:8002","term":3}`))
+	}))
+	defer server.Close()
+
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+		}).
+		Test("Describe", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.HTTP("svc", "GET", "/cluster/info").T().
+				Status(Describe("status after the election", Is(200))).
+				JSON("leader", Describe("leader address", Is("127.0.0.1:8001"))).
+				JSON("term", Is("3")).
+				Assert("Descriptions should replace raw labels")
+		}).
+		Run(context.Background())
+
+	expected := []string{
+		"Expected status after the election: 200\n  Actual status: 503 Service Unavailable",
+		`leader address (leader)  127.0.0.1:8001  "127.0.0.1:8002"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(message, e) {
+			t.Errorf("expected failure to contain %q, got:\n%s", e, message)
+		}
+	}
+}
+
+func TestHTTPJSONEquals(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"term": 2, "leader": "node-1", "members": ["node-1", "node-2"], "extra": true}`))
+	}))
+	defer server.Close()
+
+	var passed bool
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+		}).
+		Test("JSONEquals", func(do *Do) {
+			do.HTTP("svc", "GET", "/cluster").T().
+				Body(JSONEquals(`{"extra":true,"members":["node-1","node-2"],"leader":"node-1","term":2.0}`)).
+				Assert("Key order, whitespace and number formatting should be ignored")
+			passed = true
+
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.HTTP("svc", "GET", "/cluster").T().
+				Body(JSONEquals(`{"term": 3, "leader": "node-1", "members": ["node-1", "node-2", "node-3"]}`)).
+				Assert("Every difference should be listed")
+		}).
+		Run(context.Background())
+
+	if !passed {
+		t.Fatalf("expected equal documents to pass")
+	}
+
+	expected := []string{
+		"Differences:",
+		"$.term: expected 3, got 2",
+		`$.members[2]: missing, expected "node-3"`,
+		"$.extra: unexpected true",
+	}
+	for _, e := range expected {
+		if !strings.Contains(message, e) {
+			t.Errorf("expected failure to contain %q, got:\n%s", e, message)
+		}
+	}
+}
+
+func TestHTTPBodyDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+		want     []string
+	}{
+		{
+			name:     "Long Line",
+			body:     strings.Repeat("a", 1_000) + "x" + strings.Repeat("a", 1_000),
+			expected: strings.Repeat("a", 2_001),
+			want: []string{
+				"first difference at offset 1000, line 1 column 1001",
+				`- "` + strings.Repeat("a", 40) + `"`,
+				`+ "` + strings.Repeat("a", 20) + "x" + strings.Repeat("a", 19) + `"`,
+				"\n      " + strings.Repeat(" ", 21) + "^",
+			},
+		},
+		{
+			name:     "Lines",
+			body:     "one\ntwo\nthree\nfour\n",
+			expected: "one\n2\n3\nfour\n",
+			want: []string{
+				"first difference at offset 4, line 2 column 1",
+				"@@ line 2 @@\n    - 2\n    - 3\n    + two\n    + three",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			var message string
+
+			config := &Config{WorkingDir: t.TempDir()}
+			New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+				}).
+				Test("Diff", func(do *Do) {
+					defer func() {
+						message = fmt.Sprint(recover())
+					}()
+
+					do.HTTP("svc", "GET", "/kv/long").T().
+						Body(Is(tt.expected)).
+						Assert("Long bodies should be diffed")
+				}).
+				Run(context.Background())
+
+			for _, e := range tt.want {
+				if !strings.Contains(message, e) {
+					t.Errorf("expected failure to contain %q, got:\n%s", e, message)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPTruncatesLongBodies(t *testing.T) {
+	value := strings.Repeat("v", 5_000) + "x" + strings.Repeat("v", 5_000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(value))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		config   *Config
+		expected []string
+		maxLen   int
+	}{
+		{
+			name:     "Default",
+			config:   &Config{},
+			expected: []string{"vvvvxvvvv", " chars]…", "first difference at offset 5000, line 1 column 5001"},
+			maxLen:   530,
+		},
+		{
+			name:     "Line Width",
+			config:   &Config{LineWidth: 100},
+			expected: []string{"vvvxvvv"},
+			maxLen:   100,
+		},
+		{
+			name:     "Unlimited",
+			config:   &Config{MaxBodyLength: -1},
+			expected: []string{value},
+			maxLen:   20_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message string
+
+			tt.config.WorkingDir = t.TempDir()
+			New().WithConfig(tt.config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+				}).
+				Test("Long Body", func(do *Do) {
+					defer func() {
+						message = fmt.Sprint(recover())
+					}()
+
+					do.HTTP("svc", "GET", "/kv/long").T().
+						Body(Is(strings.Repeat("v", 10_001))).
+						Assert("Long bodies should be shortened around the difference")
+				}).
+				Run(context.Background())
+
+			for _, e := range tt.expected {
+				if !strings.Contains(message, e) {
+					t.Errorf("expected failure to contain %q, got:\n%s", e, message)
+				}
+			}
+
+			for _, line := range strings.Split(message, "\n") {
+				if n := utf8.RuneCountInString(line); n > tt.maxLen {
+					t.Errorf("expected lines of at most %d characters, got %d", tt.maxLen, n)
 				}
 			}
 		})
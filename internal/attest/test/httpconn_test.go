@@ -0,0 +1,176 @@
+package attest_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// oneShotServer answers the first request on each connection, then either
+// hangs up or ignores everything else sent on it.
+func oneShotServer(t *testing.T, hangUp bool) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				if _, err := http.ReadRequest(bufio.NewReader(conn)); err != nil {
+					return
+				}
+				conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"))
+
+				if !hangUp {
+					io.Copy(io.Discard, conn)
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestHTTPConn(t *testing.T) {
+	get := func(path string, headers ...string) []byte {
+		request := "GET " + path + " HTTP/1.1\r\nHost: localhost\r\n"
+		for _, header := range headers {
+			request += header + "\r\n"
+		}
+
+		return []byte(request + "\r\n")
+	}
+
+	compliant := func(t *testing.T) string {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.Path))
+		}))
+		t.Cleanup(server.Close)
+
+		return server.Listener.Addr().String()
+	}
+
+	tests := []struct {
+		name       string
+		server     func(*testing.T) string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name:   "Keep-Alive Reuse",
+			server: compliant,
+			testFunc: func(do *Do) {
+				do.HTTPConn("svc", get("/a"), get("/b")).T().
+					Responses(Is(2)).
+					Status(0, Is(200)).
+					Body(1, Is("/b")).
+					Closed(Is(false)).
+					Assert("Server should keep the connection alive between requests")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Connection Close Honored",
+			server: compliant,
+			testFunc: func(do *Do) {
+				do.HTTPConn("svc", get("/a", "Connection: close"), get("/b")).T().
+					Responses(Is(1)).
+					Header(0, "connection", Is("close")).
+					Closed(Is(true)).
+					Assert("Server should close the connection when asked to")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Pipelined Requests",
+			server: compliant,
+			testFunc: func(do *Do) {
+				head := []byte("HEAD /a HTTP/1.1\r\nHost: localhost\r\n\r\n")
+				do.HTTPConn("svc", head, get("/b"), get("/c")).Pipelined().T().
+					Responses(Is(3)).
+					Body(0, Is("")).
+					Body(1, Is("/b")).
+					Body(2, Is("/c")).
+					Assert("Server should answer pipelined requests in order")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Pipelined Requests Dropped",
+			server: func(t *testing.T) string {
+				return oneShotServer(t, true)
+			},
+			testFunc: func(do *Do) {
+				do.HTTPConn("svc", get("/a"), get("/b")).Pipelined().T().
+					Responses(Is(2)).
+					Assert("Should fail when the server drops in-flight requests")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Connection Not Reused",
+			server: func(t *testing.T) string {
+				return oneShotServer(t, true)
+			},
+			testFunc: func(do *Do) {
+				do.HTTPConn("svc", get("/a"), get("/b")).T().
+					Status(1, Is(200)).
+					Assert("Should fail when there's no second response")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Connection Close Ignored",
+			server: func(t *testing.T) string {
+				return oneShotServer(t, false)
+			},
+			testFunc: func(do *Do) {
+				do.HTTPConn("svc", get("/a", "Connection: close")).T().
+					Responses(Is(1)).
+					Closed(Is(true)).
+					Assert("Should fail when the server leaves the connection open")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := tt.server(t)
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", addr[strings.LastIndex(addr, ":")+1:])
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
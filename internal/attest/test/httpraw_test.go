@@ -0,0 +1,146 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestHTTPRaw(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Well-Formed Request",
+			testFunc: func(do *Do) {
+				do.HTTPRaw("svc", []byte("GET /hello HTTP/1.1\r\nHost: localhost\r\n\r\n")).T().
+					Status(Is(200)).
+					Header("x-greeting", Is("hi")).
+					Body(Is("hello")).
+					Response(Contains("HTTP/1.1 200 OK\r\n")).
+					Assert("Server should answer well-formed requests")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Malformed Request Line",
+			testFunc: func(do *Do) {
+				do.HTTPRaw("svc", []byte("GARBAGE\r\n\r\n")).T().
+					Status(Is(400)).
+					Assert("Server should reject malformed request lines")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Missing Host",
+			testFunc: func(do *Do) {
+				do.HTTPRaw("svc", []byte("GET /hello HTTP/1.1\r\n\r\n")).T().
+					Status(Is(400)).
+					Body(Contains("Host")).
+					Assert("Server should require a Host header")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Oversized Headers",
+			testFunc: func(do *Do) {
+				header := "X-Padding: " + strings.Repeat("a", 8192) + "\r\n"
+				do.HTTPRaw("svc", []byte("GET /hello HTTP/1.1\r\nHost: localhost\r\n"+header+"\r\n")).T().
+					Status(Is(431)).
+					Assert("Server should reject oversized headers")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "HEAD Response",
+			testFunc: func(do *Do) {
+				do.HTTPRaw("svc", []byte("HEAD /hello HTTP/1.1\r\nHost: localhost\r\n\r\n")).T().
+					Status(Is(200)).
+					Body(Is("")).
+					Assert("HEAD responses should have no body")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Status Mismatch",
+			testFunc: func(do *Do) {
+				do.HTTPRaw("svc", []byte("GARBAGE\r\n\r\n")).T().
+					Status(Is(200)).
+					Assert("Should fail when the status doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Header Mismatch",
+			testFunc: func(do *Do) {
+				do.HTTPRaw("svc", []byte("GET /hello HTTP/1.1\r\nHost: localhost\r\n\r\n")).T().
+					Header("X-Greeting", Is("hello")).
+					Assert("Should fail when a header doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Invalid Response",
+			testFunc: func(do *Do) {
+				do.HTTPRaw("svc", []byte("GET /garbage HTTP/1.1\r\nHost: localhost\r\n\r\n")).T().
+					Status(Is(200)).
+					Assert("Should fail when the response isn't valid HTTP")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Raw Response Of Invalid HTTP",
+			testFunc: func(do *Do) {
+				do.HTTPRaw("svc", []byte("GET /garbage HTTP/1.1\r\nHost: localhost\r\n\r\n")).T().
+					Response(Is("not http\n")).
+					Assert("Raw checkers should work on any response")
+			},
+			shouldPass: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/garbage" {
+					conn, _, _ := w.(http.Hijacker).Hijack()
+					conn.Write([]byte("not http\n"))
+					conn.Close()
+					return
+				}
+
+				w.Header().Set("X-Greeting", "hi")
+				w.Write([]byte("hello"))
+			}))
+			server.Config.MaxHeaderBytes = 1024
+			server.Start()
+			defer server.Close()
+
+			port := strings.Split(server.URL, ":")[2]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
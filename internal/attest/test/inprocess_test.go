@@ -0,0 +1,165 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// counter is an in-process server that persists its count to the working directory when closed.
+type counter struct {
+	mu    sync.Mutex
+	path  string
+	count int
+}
+
+func newCounter(args []string) (http.Handler, error) {
+	c := &counter{}
+	for _, arg := range args {
+		if dir, ok := strings.CutPrefix(arg, "--working-dir="); ok {
+			c.path = filepath.Join(dir, "count")
+		}
+	}
+
+	data, _ := os.ReadFile(c.path)
+	c.count = len(data)
+	return c, nil
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r.Method == "POST" {
+		c.count++
+	}
+	w.Write([]byte(strings.Repeat("+", c.count)))
+}
+
+func (c *counter) Close() error {
+	return os.WriteFile(c.path, []byte(strings.Repeat("+", c.count)), 0644)
+}
+
+func TestInProcess(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Serve",
+			testFunc: func(do *Do) {
+				do.HTTP("node", "POST", "/").T().
+					Body(Is("+")).
+					Assert("The handler should serve requests")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Restart Closes Handler",
+			testFunc: func(do *Do) {
+				do.HTTP("node", "POST", "/").T().Body(Is("+")).Assert("First increment")
+				do.Restart("node")
+				do.HTTP("node", "GET", "/").T().
+					Body(Is("+")).
+					Assert("The handler should be closed on SIGTERM")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Kill Abandons Handler",
+			testFunc: func(do *Do) {
+				do.HTTP("node", "POST", "/").T().Body(Is("+")).Assert("First increment")
+				do.Kill("node")
+				do.HTTP("node", "GET", "/").T().
+					Body(Is("+")).
+					Assert("Should fail as the server was killed")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{WorkingDir: t.TempDir(), Command: "/nonexistent"}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).InProcess(newCounter).
+					Setup(func(do *Do) {
+						do.Start("node")
+					}).
+					Test(tt.name, tt.testFunc).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Errorf("expected pass=%v, got output:\n%s", tt.shouldPass, output)
+			}
+		})
+	}
+}
+
+func TestInProcessArgs(t *testing.T) {
+	var args []string
+	server := func(a []string) (http.Handler, error) {
+		args = a
+		return http.NotFoundHandler(), nil
+	}
+
+	captureStdout(t, func() {
+		New().WithConfig(&Config{WorkingDir: t.TempDir()}).InProcess(server).
+			Setup(func(do *Do) {
+				do.Start("node", "--peers=a,b")
+			}).
+			Run(context.Background())
+	})
+
+	if len(args) != 3 || !strings.HasPrefix(args[0], "--port=") ||
+		!strings.HasPrefix(args[1], "--working-dir=") || !slices.Contains(args, "--peers=a,b") {
+		t.Errorf("unexpected arguments %v", args)
+	}
+}
+
+func TestInProcessDataDir(t *testing.T) {
+	var dirA, dirB string
+	var countA, countB []byte
+	var errB error
+
+	output := captureStdout(t, func() {
+		New().WithConfig(&Config{WorkingDir: t.TempDir()}).InProcess(newCounter).
+			Setup(func(do *Do) {
+				do.Start("a")
+				do.Start("b")
+			}).
+			Test("Separate Data", func(do *Do) {
+				do.HTTP("a", "POST", "/").T().Body(Is("+")).Assert("First increment")
+				do.Stop("a")
+				do.Stop("b")
+
+				dirA, dirB = do.DataDir("a"), do.DataDir("b")
+				countA, _ = os.ReadFile(filepath.Join(dirA, "count"))
+				countB, errB = os.ReadFile(filepath.Join(dirB, "count"))
+			}).
+			Run(context.Background())
+	})
+
+	if dirA == dirB {
+		t.Fatalf("expected separate data directories, both are %s:\n%s", dirA, output)
+	}
+
+	if string(countA) != "+" {
+		t.Errorf("expected a to persist its count to %s, got %q", dirA, countA)
+	}
+
+	if errB != nil || len(countB) != 0 {
+		t.Errorf("expected b's count to be empty, got %q, %v", countB, errB)
+	}
+}
@@ -0,0 +1,103 @@
+package attest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestInteractive(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Send and Expect",
+			testFunc: func(do *Do) {
+				do.Interactive("shell").
+					Send("echo hi-$((1+2))\n").
+					Expect(Contains("hi-3\n")).
+					Send("test -t 0 && echo tty-$((1+1))\n").
+					Expect(Contains("tty-2\n"))
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Expected Output Consumed",
+			testFunc: func(do *Do) {
+				do.Interactive("shell").
+					Send("echo one-$((1))\n").
+					Expect(Contains("one-1\n")).
+					Send("echo two\n").
+					Timeout(300 * time.Millisecond).
+					Expect(Contains("one-1\n"))
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Exit Code",
+			testFunc: func(do *Do) {
+				do.Interactive("shell").
+					Send("exit 3\n").
+					ExpectExit(Is(3))
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Exit Code Mismatch",
+			testFunc: func(do *Do) {
+				do.Interactive("shell").
+					Send("exit 3\n").
+					ExpectExit(Is(0))
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Timeout",
+			testFunc: func(do *Do) {
+				do.Interactive("shell").
+					Timeout(300 * time.Millisecond).
+					Send("echo hello\n").
+					Expect(Contains("goodbye"))
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Exited Before Output",
+			testFunc: func(do *Do) {
+				do.Interactive("shell").
+					Send("exit 0\n").
+					Expect(Contains("goodbye"))
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Command: "sh", WorkingDir: t.TempDir()}
+
+			start := time.Now()
+			success := New().WithConfig(config).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("expected the session to finish promptly, took %s", elapsed)
+			}
+		})
+	}
+}
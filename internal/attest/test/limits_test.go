@@ -0,0 +1,74 @@
+package attest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		mb         string
+		shouldPass bool
+	}{
+		{name: "Within Limit", mb: "16", shouldPass: true},
+		{name: "Out Of Memory", mb: "512", shouldPass: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.shouldPass && raceEnabled {
+				t.Skip("the race detector reserves more memory than the limit")
+			}
+
+			config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.StartWith("svc", []StartOption{WithMemoryLimit(256 << 20)})
+					}).
+					Test("Allocate", func(do *Do) {
+						do.HTTP("svc", "GET", "/allocate?mb="+tt.mb).T().
+							Status(Is(200)).
+							Assert("Process should stay within its memory limit")
+					}).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Fatalf("expected success=%v, got %v:\n%s", tt.shouldPass, success, output)
+			}
+
+			if !tt.shouldPass && !strings.Contains(output, "under its memory limit of 256.0 MiB, so it likely ran out of memory") {
+				t.Errorf("expected an out of memory failure, got:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestMemoryLimitWhileStarting(t *testing.T) {
+	config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.StartWith("svc", []StartOption{WithMemoryLimit(1 << 20)})
+			}).
+			Test("Start", func(do *Do) {}).
+			Run(context.Background())
+	})
+
+	if success {
+		t.Fatalf("expected the suite to fail:\n%s", output)
+	}
+
+	if !strings.Contains(output, "under its memory limit of 1.0 MiB, so it likely ran out of memory") {
+		t.Errorf("expected an out of memory failure, got:\n%s", output)
+	}
+}
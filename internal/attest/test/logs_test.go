@@ -0,0 +1,104 @@
+package attest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestLogs(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(*Do)
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Never OK",
+			testFunc: func(do *Do) {
+				do.MockLog("svc", "starting\nlistening on :8080\n")
+				do.Logs("svc").T().
+					Never(Contains("panic")).
+					Assert("Logs should not contain panics")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Never Failure",
+			testFunc: func(do *Do) {
+				do.MockLog("svc", "starting\npanic: runtime error: index out of range\n")
+				do.Logs("svc").T().
+					Never(Contains("panic")).
+					Assert("Should fail when logs contain a panic")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Only Current Test",
+			setup: func(do *Do) {
+				do.MockLog("svc", "panic: during setup\n")
+			},
+			testFunc: func(do *Do) {
+				do.MockLog("svc", "all good\n")
+				do.Logs("svc").T().
+					Output(Is("all good\n")).
+					Never(Contains("panic")).
+					Assert("Should only consider output from the current test")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "At End Failure",
+			testFunc: func(do *Do) {
+				do.Logs("svc").T().
+					Never(Contains("panic")).
+					AssertAtEnd("Should fail when a panic is logged later in the test")
+
+				do.MockLog("svc", "panic: late\n")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Eventually OK",
+			testFunc: func(do *Do) {
+				go func() {
+					time.Sleep(300 * time.Millisecond)
+					do.MockLog("svc", "ready\n")
+				}()
+
+				do.Logs("svc").Eventually().T().
+					Output(Contains("ready")).
+					Assert("Logs should eventually contain ready")
+			},
+			shouldPass: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", "0")
+					if tt.setup != nil {
+						tt.setup(do)
+					}
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package attest_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// echoServer starts a TCP server that echoes lines back, returning its port.
+func echoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					fmt.Fprintln(conn, scanner.Text())
+				}
+			}()
+		}
+	}()
+
+	return strings.Split(listener.Addr().String(), ":")[1]
+}
+
+// echo sends line over conn and reads the reply.
+func echo(conn net.Conn, line string) (string, error) {
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	_, err := fmt.Fprintln(conn, line)
+	if err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	return strings.TrimSpace(reply), err
+}
+
+func TestPartition(t *testing.T) {
+	port := echoServer(t)
+
+	// reach reports whether a new connection from node-1 to node-2 gets through
+	reach := func(do *Do) bool {
+		conn, err := net.Dial("tcp", do.PeerAddress("node-1", "node-2"))
+		if err != nil {
+			return false
+		}
+		defer conn.Close()
+
+		reply, err := echo(conn, "ping")
+		return err == nil && reply == "ping"
+	}
+
+	config := &Config{WorkingDir: t.TempDir()}
+	captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.MockProcess("node-1", echoServer(t))
+				do.MockProcess("node-2", port)
+				do.MockProcess("node-3", echoServer(t))
+			}).
+			Test("Partition", func(do *Do) {
+				if !reach(do) {
+					t.Error("expected node-1 to reach node-2 before partitioning")
+				}
+
+				open, err := net.Dial("tcp", do.PeerAddress("node-1", "node-2"))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer open.Close()
+
+				_, err = echo(open, "before")
+				if err != nil {
+					t.Errorf("expected an open connection to work: %v", err)
+				}
+
+				do.Partition("node-1", "node-3")
+
+				if reach(do) {
+					t.Error("expected node-1 not to reach node-2 while partitioned")
+				}
+				if _, err := echo(open, "during"); err == nil {
+					t.Error("expected the open connection to be closed by the partition")
+				}
+
+				conn, err := net.Dial("tcp", do.PeerAddress("node-3", "node-1"))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer conn.Close()
+
+				if reply, err := echo(conn, "same side"); err != nil || reply != "same side" {
+					t.Errorf("expected node-3 to reach node-1 on the same side, got %q, %v", reply, err)
+				}
+
+				do.Heal()
+
+				if !reach(do) {
+					t.Error("expected node-1 to reach node-2 after healing")
+				}
+			}).
+			Test("Left Partitioned", func(do *Do) {
+				do.Partition("node-2")
+				do.AddLatency("node-2", time.Second)
+			}).
+			Test("Healed After Test", func(do *Do) {
+				start := time.Now()
+				if !reach(do) {
+					t.Error("expected a partition left by the previous test to be healed")
+				}
+				if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+					t.Errorf("expected latency left by the previous test to be removed, took %s", elapsed)
+				}
+			}).
+			Run(context.Background())
+	})
+}
+
+func TestNetworkFaults(t *testing.T) {
+	config := &Config{WorkingDir: t.TempDir()}
+	captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.MockProcess("node-1", echoServer(t))
+				do.MockProcess("node-2", echoServer(t))
+			}).
+			Test("Latency", func(do *Do) {
+				conn, err := net.Dial("tcp", do.PeerAddress("node-1", "node-2"))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer conn.Close()
+
+				do.AddLatency("node-2", 50*time.Millisecond)
+				do.AddLatency("node-2", 50*time.Millisecond)
+
+				start := time.Now()
+				reply, err := echo(conn, "slow")
+				if err != nil || reply != "slow" {
+					t.Errorf("expected the reply to arrive late, got %q, %v", reply, err)
+				}
+				if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+					t.Errorf("expected a round trip of at least 200ms, took %s", elapsed)
+				}
+
+				do.Heal()
+
+				start = time.Now()
+				echo(conn, "fast")
+				if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+					t.Errorf("expected healing to remove the latency, took %s", elapsed)
+				}
+			}).
+			Test("Latency Throughput", func(do *Do) {
+				conn, err := net.Dial("tcp", do.PeerAddress("node-1", "node-2"))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer conn.Close()
+
+				do.AddLatency("node-2", 100*time.Millisecond)
+
+				// Each line is delayed by 100ms each way however many are in
+				// flight, as on a real link, rather than queueing behind the
+				// delay of the one before
+				sent := make(chan time.Time, 20)
+				go func() {
+					for i := range 20 {
+						sent <- time.Now()
+						fmt.Fprintf(conn, "line %d\n", i)
+						time.Sleep(30 * time.Millisecond)
+					}
+				}()
+
+				conn.SetDeadline(time.Now().Add(3 * time.Second))
+				reader := bufio.NewReader(conn)
+				for i := range 20 {
+					reply, err := reader.ReadString('\n')
+					if err != nil || strings.TrimSpace(reply) != fmt.Sprintf("line %d", i) {
+						t.Errorf("expected line %d back, got %q, %v", i, reply, err)
+						return
+					}
+
+					rtt := time.Since(<-sent)
+					if rtt < 200*time.Millisecond || rtt > 260*time.Millisecond {
+						t.Errorf("expected line %d to take a round trip of 200ms, took %s", i, rtt)
+					}
+				}
+			}).
+			Test("Throttle", func(do *Do) {
+				conn, err := net.Dial("tcp", do.PeerAddress("node-1", "node-2"))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer conn.Close()
+
+				// 16KiB each way through node-2's 64KiB/s cap takes about 500ms
+				do.Throttle("node-2", 64*1024)
+
+				line := strings.Repeat("x", 16*1024)
+				start := time.Now()
+				reply, err := echo(conn, line)
+				if err != nil || reply != line {
+					t.Errorf("expected the whole line back, got %d bytes, %v", len(reply), err)
+				}
+				if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+					t.Errorf("expected the transfer to be throttled, took %s", elapsed)
+				}
+
+				do.Heal()
+			}).
+			Test("Packet Loss", func(do *Do) {
+				do.DropPackets("node-1", 1)
+
+				conn, err := net.Dial("tcp", do.PeerAddress("node-1", "node-2"))
+				if err == nil {
+					_, err = echo(conn, "lost")
+					conn.Close()
+				}
+				if err == nil {
+					t.Error("expected every connection to be lost")
+				}
+
+				do.Heal()
+
+				conn, err = net.Dial("tcp", do.PeerAddress("node-1", "node-2"))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer conn.Close()
+
+				if reply, err := echo(conn, "found"); err != nil || reply != "found" {
+					t.Errorf("expected connections to get through after healing, got %q, %v", reply, err)
+				}
+			}).
+			Run(context.Background())
+	})
+}
@@ -0,0 +1,184 @@
+package attest_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// registerModel expects every path to hold the last value PUT to it.
+type registerModel struct {
+	values     map[string]string
+	concurrent int
+}
+
+func (m *registerModel) Check(op Operation) string {
+	if op.Concurrent {
+		m.concurrent++
+	}
+
+	switch op.Method {
+	case "PUT":
+		m.values[op.Path] = op.Body
+	case "GET":
+		if expected := m.values[op.Path]; op.Response != expected {
+			return fmt.Sprintf("Expected response: %q\n  Actual response: %q", expected, op.Response)
+		}
+	}
+
+	return ""
+}
+
+// registerServer stores values by path, dropping writes to paths containing drop.
+func registerServer(drop string) http.HandlerFunc {
+	var mu sync.Mutex
+	values := make(map[string]string)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case "PUT":
+			body, _ := io.ReadAll(r.Body)
+			if drop == "" || !strings.Contains(r.URL.Path, drop) {
+				values[r.URL.Path] = string(body)
+			}
+		case "GET":
+			w.Write([]byte(values[r.URL.Path]))
+		}
+	}
+}
+
+func TestOracle(t *testing.T) {
+	tests := []struct {
+		name       string
+		drop       string
+		expected   string
+		shouldPass bool
+	}{
+		{
+			name:       "Consistent Server",
+			shouldPass: true,
+		},
+		{
+			name:       "Diverging Server",
+			drop:       "/7",
+			expected:   "Your server diverged from the reference model at operation #18: GET /7",
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(registerServer(tt.drop))
+			defer server.Close()
+
+			model := &registerModel{values: make(map[string]string)}
+			config := &Config{WorkingDir: t.TempDir()}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Oracle(func() Oracle { return model }).
+					Setup(func(do *Do) {
+						do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+					}).
+					Test("Operations", func(do *Do) {
+						for i := range 10 {
+							do.HTTP("svc", "PUT", fmt.Sprintf("/%d", i), fmt.Sprintf("v%d", i)).T().
+								Status(Is(200)).
+								Assert("Writes should succeed")
+						}
+
+						// Only the status is asserted, the oracle checks the values
+						for i := range 10 {
+							do.HTTP("svc", "GET", fmt.Sprintf("/%d", i)).T().
+								Status(Is(200)).
+								Assert("Reads should succeed")
+						}
+					}).
+					Test("Concurrent Operations", func(do *Do) {
+						do.Concurrently(func(do *Do) {
+							do.HTTP("svc", "PUT", "/a", "1").T().
+								Status(Is(200)).
+								Assert("Writes should succeed")
+						})
+					}).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed:\n%s", tt.name, output)
+				} else {
+					t.Errorf("%s test should fail but passed:\n%s", tt.name, output)
+				}
+			}
+
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expected, output)
+			}
+
+			if tt.shouldPass && model.concurrent != 1 {
+				t.Errorf("expected 1 concurrent operation, got %d", model.concurrent)
+			}
+		})
+	}
+}
+
+func TestOracleMatrix(t *testing.T) {
+	server := httptest.NewServer(registerServer(""))
+	defer server.Close()
+
+	var models []*registerModel
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Oracle(func() Oracle {
+				model := &registerModel{values: make(map[string]string)}
+				models = append(models, model)
+				return model
+			}).
+			Matrix("nodes", 3, 5).
+			Setup(func(do *Do) {
+				do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+			}).
+			Test("Concurrent Operations", func(do *Do) {
+				do.Concurrently(func(do *Do) {
+					// Operations of goroutines started by the function are part of its group too
+					var wg sync.WaitGroup
+					wg.Go(func() {
+						do.HTTP("svc", "PUT", "/a", "1").T().
+							Status(Is(200)).
+							Assert("Writes should succeed")
+					})
+					wg.Wait()
+				})
+			}).
+			Run(context.Background())
+	})
+
+	if !success {
+		t.Fatalf("suite should pass:\n%s", output)
+	}
+
+	if len(models) != 2 {
+		t.Fatalf("expected a model per variant, got %d", len(models))
+	}
+
+	for i, model := range models {
+		if model.concurrent != 1 {
+			t.Errorf("expected variant %d's model to see only its own operation, got %d", i+1, model.concurrent)
+		}
+	}
+}
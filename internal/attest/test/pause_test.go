@@ -0,0 +1,125 @@
+package attest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestPauseResume(t *testing.T) {
+	// responds reports whether svc answers within a short timeout
+	responds := func(do *Do) bool {
+		client := &http.Client{Timeout: 300 * time.Millisecond}
+		resp, err := client.Get(do.URL("svc", "/"))
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+
+		return true
+	}
+
+	config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.Start("svc")
+			}).
+			Test("Pause", func(do *Do) {
+				do.Pause("svc")
+				if responds(do) {
+					t.Error("expected a paused process not to respond")
+				}
+
+				do.Resume("svc")
+				if !responds(do) {
+					t.Error("expected a resumed process to respond")
+				}
+			}).
+			Test("Stop While Paused", func(do *Do) {
+				do.Pause("svc")
+				do.Restart("svc")
+				if !responds(do) {
+					t.Error("expected a process restarted while paused to respond")
+				}
+				do.Pause("svc")
+			}).
+			Test("Resumed After Test", func(do *Do) {
+				if !responds(do) {
+					t.Error("expected a process left paused by the previous test to be resumed")
+				}
+			}).
+			Run(context.Background())
+	})
+
+	if !success {
+		t.Fatalf("expected the suite to pass:\n%s", output)
+	}
+}
+
+func TestPauseProcessGroup(t *testing.T) {
+	// The server is a child of the shell, like a run.sh that doesn't exec
+	script := filepath.Join(t.TempDir(), "run.sh")
+	err := os.WriteFile(script, []byte(fmt.Sprintf("#!/bin/sh\n%q \"$@\"\n", helperCommand(t))), 0755)
+	if err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	config := &Config{Command: script, WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.Start("svc")
+			}).
+			Test("Pause", func(do *Do) {
+				do.Pause("svc")
+
+				client := &http.Client{Timeout: 300 * time.Millisecond}
+				resp, err := client.Get(do.URL("svc", "/"))
+				if err == nil {
+					resp.Body.Close()
+					t.Error("expected the server in a paused process group not to respond")
+				}
+			}).
+			Run(context.Background())
+	})
+
+	if !success {
+		t.Fatalf("expected the suite to pass:\n%s", output)
+	}
+}
+
+func TestPauseMockProcess(t *testing.T) {
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var msg string
+	captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.MockProcess("svc", "0")
+			}).
+			Test("Pause", func(do *Do) {
+				defer func() {
+					msg, _ = recover().(string)
+				}()
+
+				do.Pause("svc")
+			}).
+			Run(context.Background())
+	})
+
+	if !strings.Contains(msg, "can't be paused") {
+		t.Errorf("expected pausing a mock process to panic, got %q", msg)
+	}
+}
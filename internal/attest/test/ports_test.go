@@ -0,0 +1,123 @@
+package attest_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestExtraPorts(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Main Port",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Body(Is("OK")).
+					Assert("Requests should go to the main port by default")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Peer Port",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").OnPort("peer").T().
+					Body(Is("PEER")).
+					Assert("Requests should go to the selected port")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Peer Port After Restart",
+			testFunc: func(do *Do) {
+				port := do.Port("svc", "peer")
+				do.Restart("svc")
+				if do.Port("svc", "peer") != port {
+					panic("the peer port should stay the same across restarts")
+				}
+
+				do.HTTP("svc", "GET", "/").OnPort("peer").Eventually().T().
+					Body(Is("PEER")).
+					Assert("The restarted process should listen on the same peer port")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Unknown Port",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").OnPort("admin").T().
+					Status(Is(200)).
+					Assert("Should fail as there's no admin port")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.StartWith("svc", []StartOption{WithPort("peer")})
+					}).
+					Test(tt.name, tt.testFunc).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Fatalf("expected success=%v, got %v:\n%s", tt.shouldPass, success, output)
+			}
+		})
+	}
+}
+
+func TestPortPassing(t *testing.T) {
+	for _, passing := range []PortPassing{PortFlag, PortEnv, PortFile} {
+		t.Run(passing.String(), func(t *testing.T) {
+			config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).WithPortPassing(passing).
+					Setup(func(do *Do) {
+						do.StartWith("svc", []StartOption{WithPort("peer")})
+					}).
+					Test("Both Ports", func(do *Do) {
+						do.HTTP("svc", "GET", "/").T().
+							Body(Is("OK")).
+							Assert("The process should listen on the port it was passed")
+
+						do.HTTP("svc", "GET", "/").OnPort("peer").T().
+							Body(Is("PEER")).
+							Assert("The process should listen on the peer port it was passed")
+					}).
+					Run(context.Background())
+			})
+
+			if !success {
+				t.Fatalf("expected the process to find its ports:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestParsePortPassing(t *testing.T) {
+	for _, name := range []string{"flag", "env", "file"} {
+		passing, err := ParsePortPassing(name)
+		if err != nil || passing.String() != name {
+			t.Errorf("expected %q to parse, got %v, %v", name, passing, err)
+		}
+	}
+
+	_, err := ParsePortPassing("stdin")
+	if err == nil {
+		t.Error("expected an unknown convention to fail")
+	}
+}
@@ -0,0 +1,109 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestPreflight(t *testing.T) {
+	endpoints := []Endpoint{
+		{Method: "PUT", Path: "/kv/preflight:key", Body: "value"},
+		{Method: "GET", Path: "/kv/preflight:key"},
+	}
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		shouldPass bool
+	}{
+		{
+			name: "Endpoints Implemented",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Some Endpoints Implemented",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			},
+			shouldPass: true,
+		},
+		{
+			name: "No Endpoints Implemented",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			port := strings.Split(server.URL, ":")[2]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Preflight("svc", endpoints...).
+				Test(tt.name, func(do *Do) {}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
+
+func TestPreflightSendsEveryEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	port := strings.Split(server.URL, ":")[2]
+	config := &Config{WorkingDir: t.TempDir()}
+
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", port)
+		}).
+		Preflight("svc",
+			Endpoint{Method: "PUT", Path: "/kv/preflight:key", Body: "value"},
+			Endpoint{Method: "DELETE", Path: "/kv/preflight:key"},
+		).
+		Test("Preflight", func(do *Do) {}).
+		Run(context.Background())
+
+	expected := "PUT /kv/preflight:key, DELETE /kv/preflight:key"
+	if actual := strings.Join(requests, ", "); actual != expected {
+		t.Errorf("expected the cleanup to run after the first endpoint answered, got %q", actual)
+	}
+}
@@ -0,0 +1,122 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestHTTPProto(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+
+	h2c := func() *httptest.Server {
+		server := httptest.NewUnstartedServer(ok)
+		server.Config.Protocols = new(http.Protocols)
+		server.Config.Protocols.SetHTTP1(true)
+		server.Config.Protocols.SetUnencryptedHTTP2(true)
+		server.Start()
+		return server
+	}
+
+	tests := []struct {
+		name       string
+		server     func() *httptest.Server
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "HTTP/1.1 By Default",
+			server: func() *httptest.Server {
+				return httptest.NewServer(ok)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Proto(Is("HTTP/1.1")).
+					Assert("Plaintext requests should use HTTP/1.1")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Forced h2c",
+			server: h2c,
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").HTTP2().T().
+					Proto(Is("HTTP/2.0")).
+					Body(Is("HTTP/2.0")).
+					Assert("Server should speak HTTP/2 with prior knowledge")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Forced h2",
+			server: func() *httptest.Server {
+				server := httptest.NewUnstartedServer(ok)
+				server.EnableHTTP2 = true
+				server.StartTLS()
+				return server
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").TLS().HTTP2().T().
+					Proto(Is("HTTP/2.0")).
+					ALPN(Is("h2")).
+					Assert("Server should negotiate HTTP/2 over TLS")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "h2c Unsupported",
+			server: func() *httptest.Server {
+				return httptest.NewServer(ok)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").HTTP2().T().
+					Proto(Is("HTTP/2.0")).
+					Assert("Should fail when the server only speaks HTTP/1.1")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Proto Mismatch",
+			server: h2c,
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Proto(Is("HTTP/2.0")).
+					Assert("Should fail when the server doesn't upgrade")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.server()
+			defer server.Close()
+
+			config := &Config{WorkingDir: t.TempDir()}
+			port := server.URL[strings.LastIndex(server.URL, ":")+1:]
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
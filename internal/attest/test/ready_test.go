@@ -0,0 +1,213 @@
+package attest_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestReadyWhenLogMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []StartOption
+		args       []string
+		expected   string
+		shouldPass bool
+	}{
+		{
+			name:       "Ready",
+			opts:       []StartOption{ReadyWhenLogMatches(`^started consumer \d+$`)},
+			args:       []string{"--no-listen", "--log=started consumer 1"},
+			shouldPass: true,
+		},
+		{
+			name:       "Never Ready",
+			opts:       []StartOption{ReadyWhenLogMatches("started consumer")},
+			args:       []string{"--no-listen", "--log=still loading"},
+			expected:   `Process worker didn't log a line matching "started consumer" within 1s`,
+			shouldPass: false,
+		},
+		{
+			name:       "Exits Before Ready",
+			opts:       []StartOption{ReadyWhenLogMatches("started consumer")},
+			args:       []string{"--no-listen", "--exit-after=100ms"},
+			expected:   "Process worker exited with status 2 before logging a line matching",
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Command:             helperCommand(t),
+				WorkingDir:          t.TempDir(),
+				ProcessStartTimeout: time.Second,
+			}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.StartWith("worker", tt.opts, tt.args...)
+					}).
+					Test("Started", func(do *Do) {
+						do.Logs("worker").T().
+							Never(Contains("loading")).
+							Assert("Worker should be ready")
+					}).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Fatalf("expected success=%v, got %v:\n%s", tt.shouldPass, success, output)
+			}
+
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestReadyWhenHealthy(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []StartOption
+		args       []string
+		expected   string
+		shouldPass bool
+	}{
+		{
+			name:       "Healthy",
+			opts:       []StartOption{ReadyWhenHealthy("/healthz")},
+			args:       []string{"--healthy-after=300ms"},
+			shouldPass: true,
+		},
+		{
+			name:       "Never Healthy",
+			opts:       []StartOption{ReadyWhenHealthy("/healthz")},
+			args:       []string{"--healthy-after=1h"},
+			expected:   "Process svc didn't become healthy within 1s, GET /healthz last returned 503 Service Unavailable",
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Command:             helperCommand(t),
+				WorkingDir:          t.TempDir(),
+				ProcessStartTimeout: time.Second,
+			}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.StartWith("svc", tt.opts, tt.args...)
+					}).
+					Test("First Request", func(do *Do) {
+						do.HTTP("svc", "GET", "/healthz").T().
+							Status(Is(200)).
+							Assert("The first request should be served once the process is healthy")
+					}).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Fatalf("expected success=%v, got %v:\n%s", tt.shouldPass, success, output)
+			}
+
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestReadyWhenPortAnnounced(t *testing.T) {
+	announced := ReadyWhenPortAnnounced(`^LISTENING ON (\d+)$`)
+
+	tests := []struct {
+		name       string
+		opts       []StartOption
+		args       []string
+		testFunc   func(*Do)
+		expected   string
+		shouldPass bool
+	}{
+		{
+			name: "Announced",
+			opts: []StartOption{announced},
+			args: []string{"--announce"},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Assert("Requests should go to the announced port")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Announced Again After Restart",
+			opts: []StartOption{announced},
+			args: []string{"--announce"},
+			testFunc: func(do *Do) {
+				do.Restart("svc")
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Assert("Requests should go to the port announced after the restart")
+			},
+			shouldPass: true,
+		},
+		{
+			name:       "Never Announced",
+			opts:       []StartOption{announced},
+			testFunc:   func(do *Do) {},
+			expected:   `Process svc didn't log a line matching "^LISTENING ON (\\d+)$" within 1s`,
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Command:             helperCommand(t),
+				WorkingDir:          t.TempDir(),
+				ProcessStartTimeout: time.Second,
+			}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.StartWith("svc", tt.opts, tt.args...)
+					}).
+					Test(tt.name, tt.testFunc).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Fatalf("expected success=%v, got %v:\n%s", tt.shouldPass, success, output)
+			}
+
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestReadyWhenPortAnnouncedWithoutGroup(t *testing.T) {
+	defer func() {
+		err := recover()
+		if err == nil || !strings.Contains(fmt.Sprint(err), "one group capturing the port") {
+			t.Errorf("expected a missing group panic, got %v", err)
+		}
+	}()
+
+	ReadyWhenPortAnnounced(`LISTENING ON \d+`)
+}
@@ -0,0 +1,134 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// kvServer stores values on PUT and serves them on GET, making writes
+// visible only after a short delay to exercise retries.
+func kvServer() http.Handler {
+	var mu sync.Mutex
+	values := map[string]string{}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case "PUT":
+			body := make([]byte, r.ContentLength)
+			r.Body.Read(body)
+			go func() {
+				time.Sleep(150 * time.Millisecond)
+				mu.Lock()
+				values[r.URL.Path] = string(body)
+				mu.Unlock()
+			}()
+		case "GET":
+			value, ok := values[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Write([]byte(value))
+		}
+	})
+}
+
+// kvStage writes a key and waits for it to become readable.
+func kvStage(expected string) func(*Do) {
+	return func(do *Do) {
+		do.HTTP("svc", "PUT", "/kv/a", "1").T().
+			Status(Is(200)).
+			Assert("Writes should succeed")
+
+		do.HTTP("svc", "GET", "/kv/a").Eventually().T().
+			Status(Is(200)).
+			Body(Is(expected)).
+			Assert("Writes should become readable")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	recording := filepath.Join(t.TempDir(), "reference.jsonl")
+
+	// Record against the reference implementation
+	server := httptest.NewServer(kvServer())
+	success := New().WithConfig(&Config{WorkingDir: t.TempDir()}).
+		Record(recording).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+		}).
+		Test("Record", kvStage("1")).
+		Run(context.Background())
+	server.Close()
+
+	if !success {
+		t.Fatal("recording against the reference implementation should pass")
+	}
+
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name:       "Satisfiable",
+			testFunc:   kvStage("1"),
+			shouldPass: true,
+		},
+		{
+			name:       "Impossible Body",
+			testFunc:   kvStage("2"),
+			shouldPass: false,
+		},
+		{
+			name: "Unrecorded Request",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/kv/b").T().
+					Status(Is(404)).
+					Assert("Should fail when there's no recorded response")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Nothing is started during a dry run, so the command doesn't need to exist
+			config := &Config{
+				Command:             filepath.Join(t.TempDir(), "missing"),
+				WorkingDir:          t.TempDir(),
+				DefaultRetryTimeout: time.Second,
+			}
+
+			success := New().WithConfig(config).
+				Replay(recording).
+				Setup(func(do *Do) {
+					do.Start("svc")
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
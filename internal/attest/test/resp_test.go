@@ -0,0 +1,298 @@
+package attest_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// redisStore is a tiny Redis-like server supporting SET, GET, INCR, KEYS and
+// RAW (which replies with its argument verbatim).
+type redisStore struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (s *redisStore) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		var n int
+		if _, err := fmt.Fscanf(reader, "*%d\r\n", &n); err != nil {
+			return
+		}
+
+		args := make([]string, n)
+		for i := range args {
+			var length int
+			fmt.Fscanf(reader, "$%d\r\n", &length)
+			buf := make([]byte, length+2)
+			reader.Read(buf)
+			args[i] = string(buf[:length])
+		}
+
+		conn.Write([]byte(s.reply(args)))
+	}
+}
+
+func (s *redisStore) reply(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "SET":
+		s.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "GET":
+		value, ok := s.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "INCR":
+		var n int
+		fmt.Sscan(s.data[args[1]], &n)
+		s.data[args[1]] = fmt.Sprint(n + 1)
+		return fmt.Sprintf(":%d\r\n", n+1)
+	case "KEYS":
+		reply := fmt.Sprintf("*%d\r\n", len(s.data))
+		for _, key := range []string{"a", "b", "counter"} {
+			if _, ok := s.data[key]; ok {
+				reply += fmt.Sprintf("$%d\r\n%s\r\n", len(key), key)
+			}
+		}
+		return reply
+	case "RAW":
+		return args[1]
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+func TestRESP(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Set And Get",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "SET", "a", "hello world").T().
+					String(Is("OK")).
+					Assert("SET should reply OK")
+
+				do.RESP("redis", "GET", "a").T().
+					String(Is("hello world")).
+					Assert("GET should return the stored value")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Nil Reply",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "GET", "missing").T().
+					Nil().
+					Assert("GET on a missing key should return nil")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Nil Mismatch",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "SET", "a", "1").T().
+					Nil().
+					Assert("Should fail when the reply isn't nil")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Integer",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "INCR", "counter").T().Integer(Is(1)).Assert("INCR should start at 1")
+				do.RESP("redis", "INCR", "counter").T().Integer(Is(2)).Assert("INCR should increment")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Integer Type Mismatch",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "SET", "a", "1").T().
+					Integer(Is(1)).
+					Assert("Should fail when the reply isn't an integer")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Error",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "FLY").T().
+					Error(Matches("^ERR unknown command")).
+					Assert("Unknown commands should return an error")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Error Is Not A String",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "FLY").T().
+					String(Contains("unknown")).
+					Assert("Should fail when an error is checked as a string")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Array",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "SET", "a", "1").T().String(Is("OK")).Assert("SET should reply OK")
+				do.RESP("redis", "SET", "b", "2").T().String(Is("OK")).Assert("SET should reply OK")
+
+				do.RESP("redis", "KEYS", "*").T().
+					Len(Is(2)).
+					Element(0, Is("a")).
+					Element(1, Is("b")).
+					Assert("KEYS should list every key")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Array Element Mismatch",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "SET", "a", "1").T().String(Is("OK")).Assert("SET should reply OK")
+
+				do.RESP("redis", "KEYS", "*").T().
+					Element(1, Is("b")).
+					Assert("Should fail when an element is missing")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Nested Array With Integers",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "RAW", "*2\r\n:7\r\n*1\r\n+x\r\n").T().
+					Len(Is(2)).
+					Element(0, Is("7")).
+					Assert("Integer elements should be checked in decimal form")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Malformed Reply",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "RAW", "OK\r\n").T().
+					String(Is("OK")).
+					Assert("Should fail when the reply isn't valid RESP")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Bulk String Length Mismatch",
+			testFunc: func(do *Do) {
+				do.RESP("redis", "RAW", "$2\r\nabc\r\n").T().
+					String(Is("ab")).
+					Assert("Should fail when a bulk string is longer than declared")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+
+			store := &redisStore{data: make(map[string]string)}
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+
+					go store.handle(conn)
+				}
+			}()
+
+			port := strings.Split(listener.Addr().String(), ":")[1]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("redis", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
+
+func TestRESPReportsAllMismatches(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	store := &redisStore{data: map[string]string{"a": "1"}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go store.handle(conn)
+		}
+	}()
+
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("redis", strings.Split(listener.Addr().String(), ":")[1])
+		}).
+		Test("Mismatches", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.RESP("redis", "KEYS", "*").T().
+				Len(Is(2)).
+				Element(0, Is("b")).
+				Element(1, Is("c")).
+				Assert("Every mismatch should be reported")
+		}).
+		Run(context.Background())
+
+	for _, expected := range []string{
+		"Expected array length: 2",
+		"Expected element #1: b",
+		"Expected element #2: c",
+		"Actual reply:",
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("expected failure to contain %q, got:\n%s", expected, message)
+		}
+	}
+}
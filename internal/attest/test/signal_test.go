@@ -0,0 +1,119 @@
+package attest_test
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestSignal(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Prompt Exit",
+			args: []string{"--shutdown-delay=0s"},
+			testFunc: func(do *Do) {
+				do.Signal("svc", syscall.SIGTERM).T().
+					ExitAfter(LessThan(time.Second)).
+					ExitCode(Is(0)).
+					Assert("Process should exit promptly on SIGTERM")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Slow Exit",
+			args: []string{"--shutdown-delay=500ms"},
+			testFunc: func(do *Do) {
+				do.Signal("svc", syscall.SIGTERM).T().
+					ExitAfter(LessThan(100 * time.Millisecond)).
+					Assert("Should fail when the process takes too long to exit")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Killed After Timeout",
+			args: []string{"--shutdown-delay=1m"},
+			testFunc: func(do *Do) {
+				do.Signal("svc", syscall.SIGTERM).T().
+					ExitCode(Is(0)).
+					Assert("Should fail when the process has to be killed")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Restart After Signal",
+			args: []string{"--shutdown-delay=0s"},
+			testFunc: func(do *Do) {
+				do.Signal("svc", syscall.SIGTERM).T().
+					ExitCode(Is(0)).
+					Assert("Process should exit cleanly on SIGTERM")
+
+				do.Restart("svc")
+
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Assert("Process should serve requests again after restarting")
+			},
+			shouldPass: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Command:                helperCommand(t),
+				WorkingDir:             t.TempDir(),
+				ProcessShutdownTimeout: 300 * time.Millisecond,
+			}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.Start("svc", tt.args...)
+					}).
+					Test(tt.name, tt.testFunc).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Errorf("expected pass=%v, got output:\n%s", tt.shouldPass, output)
+			}
+		})
+	}
+}
+
+func TestSignalKilledMessage(t *testing.T) {
+	config := &Config{
+		Command:                helperCommand(t),
+		WorkingDir:             t.TempDir(),
+		ProcessShutdownTimeout: 200 * time.Millisecond,
+	}
+
+	output := captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.Start("svc", "--shutdown-delay=1m")
+			}).
+			Test("Stuck", func(do *Do) {
+				do.Signal("svc", syscall.SIGTERM).T().
+					ExitAfter(LessThan(100 * time.Millisecond)).
+					Assert("Handle SIGTERM")
+			}).
+			Run(context.Background())
+	})
+
+	for _, expected := range []string{"Expected exit after SIGTERM: less than 100ms", "killed with SIGKILL"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package attest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// broker streams published messages to subscribers of /events.
+type broker struct {
+	mu          sync.Mutex
+	subscribers []chan string
+}
+
+func (b *broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/events":
+		ch := make(chan string, 16)
+		b.mu.Lock()
+		b.subscribers = append(b.subscribers, ch)
+		b.mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, ": connected\n\n")
+		w.(http.Flusher).Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case msg := <-ch:
+				fmt.Fprint(w, msg)
+				w.(http.Flusher).Flush()
+			}
+		}
+	case "/publish":
+		b.mu.Lock()
+		for _, ch := range b.subscribers {
+			ch <- r.URL.Query().Get("raw")
+		}
+		b.mu.Unlock()
+	case "/plain":
+		w.Write([]byte("not a stream"))
+	}
+}
+
+// publish sends a raw event to every subscriber.
+func publish(do *Do, raw string) {
+	do.HTTP("svc", "POST", "/publish?raw="+url.QueryEscape(raw)).T().
+		Status(Is(200)).
+		Assert("Publishing should succeed")
+}
+
+func TestSSE(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Events OK",
+			testFunc: func(do *Do) {
+				do.SSE("svc", "/events").
+					During(func() {
+						publish(do, "event: set\ndata: {\"key\":\"a\"}\n\n")
+						publish(do, "data: second\n\n")
+					}).T().
+					EventName(0, Is("set")).
+					Event(0, JSON("key", Is("a"))).
+					EventName(1, Is("message")).
+					Event(1, Is("second")).
+					Assert("Subscribers should receive published events")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Multi-Line Data",
+			testFunc: func(do *Do) {
+				do.SSE("svc", "/events").
+					During(func() {
+						publish(do, "data: line one\ndata: line two\n\n")
+					}).T().
+					Event(0, Is("line one\nline two")).
+					Assert("Data lines should be joined")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Count",
+			testFunc: func(do *Do) {
+				do.SSE("svc", "/events").Listen(500 * time.Millisecond).
+					During(func() {
+						for i := range 3 {
+							publish(do, fmt.Sprintf("data: %d\n\n", i))
+						}
+					}).T().
+					Count(Is(3)).
+					Assert("Every published event should arrive")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Count Mismatch",
+			testFunc: func(do *Do) {
+				do.SSE("svc", "/events").Listen(500 * time.Millisecond).
+					During(func() {
+						publish(do, "data: only\n\n")
+					}).T().
+					Count(Is(2)).
+					Assert("Should fail when too few events arrive")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "In Order",
+			testFunc: func(do *Do) {
+				do.SSE("svc", "/events").Listen(500*time.Millisecond).
+					During(func() {
+						publish(do, "data: set a\n\n")
+						publish(do, "data: noise\n\n")
+						publish(do, "data: delete a\n\n")
+					}).T().
+					InOrder(Contains("set"), Contains("delete")).
+					Assert("Changes should be streamed in order")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Out Of Order",
+			testFunc: func(do *Do) {
+				do.SSE("svc", "/events").Listen(500*time.Millisecond).
+					During(func() {
+						publish(do, "data: delete a\n\n")
+						publish(do, "data: set a\n\n")
+					}).T().
+					InOrder(Contains("set"), Contains("delete")).
+					Assert("Should fail when events arrive out of order")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Missing Event",
+			testFunc: func(do *Do) {
+				do.SSE("svc", "/events").Listen(300*time.Millisecond).T().
+					Event(0, Is("anything")).
+					Assert("Should fail when no event arrives")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Not A Stream",
+			testFunc: func(do *Do) {
+				do.SSE("svc", "/plain").T().
+					Count(Is(0)).
+					Assert("Should fail when the response isn't an event stream")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(&broker{})
+			defer server.Close()
+
+			port := strings.Split(server.URL, ":")[2]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
+
+func TestSSEReportsAllMismatches(t *testing.T) {
+	server := httptest.NewServer(&broker{})
+	defer server.Close()
+
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+		}).
+		Test("Mismatches", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.SSE("svc", "/events").Listen(500*time.Millisecond).
+				During(func() {
+					publish(do, "event: set\ndata: a\n\n")
+				}).T().
+				Count(Is(2)).
+				EventName(0, Is("delete")).
+				Event(0, Is("b")).
+				Event(1, Is("c")).
+				Assert("Every mismatch should be reported")
+		}).
+		Run(context.Background())
+
+	for _, expected := range []string{
+		"Expected events: 2\n  Actual events: 1",
+		"Expected event #1 name: delete\n  Actual event #1 name: \"set\"",
+		"Expected event #1: b\n  Actual event #1: \"a\"",
+		"Expected event #2 within 500ms",
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("expected failure to contain %q, got:\n%s", expected, message)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package attest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestRunningProcesses(t *testing.T) {
+	config := &Config{
+		Command:    helperCommand(t),
+		WorkingDir: t.TempDir(),
+	}
+
+	var during []ProcessRecord
+	var err error
+	success := New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.Start("svc")
+		}).
+		Test("Running", func(do *Do) {
+			during, err = RunningProcesses(config.WorkingDir)
+		}).
+		Run(context.Background())
+
+	if !success {
+		t.Fatalf("suite should pass")
+	}
+
+	if err != nil {
+		t.Fatalf("failed to read process state: %v", err)
+	}
+
+	if len(during) != 1 || during[0].Name != "svc" || during[0].Port == 0 || during[0].RunDir == "" {
+		t.Errorf("expected svc to be listed while running, got %+v", during)
+	}
+
+	after, err := RunningProcesses(config.WorkingDir)
+	if err != nil {
+		t.Fatalf("failed to read process state: %v", err)
+	}
+
+	if len(after) != 0 {
+		t.Errorf("expected no processes after the run, got %+v", after)
+	}
+
+	killed, err := KillProcesses(config.WorkingDir)
+	if err != nil || len(killed) != 0 {
+		t.Errorf("expected nothing to kill after the run, got %+v (%v)", killed, err)
+	}
+}
+
+func TestKillProcessesChecksIdentity(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("requires /proc")
+	}
+
+	// start runs a process in its own group, as the harness does
+	start := func() *exec.Cmd {
+		cmd := exec.Command("sleep", "60")
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("failed to start sleep: %v", err)
+		}
+		t.Cleanup(func() {
+			cmd.Process.Kill()
+			cmd.Wait()
+		})
+
+		return cmd
+	}
+
+	// identity reads the process's start time, as the harness records it
+	identity := func(pid int) string {
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err != nil {
+			t.Fatalf("failed to read stat: %v", err)
+		}
+
+		return strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))[19]
+	}
+
+	reused, ours := start(), start()
+	records := []ProcessRecord{
+		// A pid that now belongs to a process started later than the recorded one
+		{Name: "reused", PID: reused.Process.Pid, Identity: "1"},
+		{Name: "ours", PID: ours.Process.Pid, Identity: identity(ours.Process.Pid)},
+	}
+
+	workingDir := t.TempDir()
+	data, _ := json.Marshal(records)
+	if err := os.WriteFile(filepath.Join(workingDir, "processes.json"), data, 0644); err != nil {
+		t.Fatalf("failed to write process state: %v", err)
+	}
+
+	killed, err := KillProcesses(workingDir)
+	if err != nil {
+		t.Fatalf("failed to kill processes: %v", err)
+	}
+
+	if len(killed) != 1 || killed[0].Name != "ours" {
+		t.Errorf("expected only ours to be killed, got %+v", killed)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- ours.Wait() }()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected ours to be killed")
+	}
+
+	if err := reused.Process.Signal(syscall.Signal(0)); err != nil {
+		t.Errorf("expected the process that reused the pid to be left running: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package attest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestStrict(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		strict     bool
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name:   "Healthy Process",
+			strict: true,
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").
+					Consistently().For(500 * time.Millisecond).T().
+					Status(Is(200)).
+					Assert("Healthy process should pass in strict mode")
+			},
+			shouldPass: true,
+		},
+		{
+			name:   "Unexpected Exit",
+			args:   []string{"--exit-after=200ms"},
+			strict: true,
+			testFunc: func(do *Do) {
+				time.Sleep(500 * time.Millisecond)
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Stack Trace In Logs",
+			args:   []string{"--log=panic: runtime error: invalid memory address"},
+			strict: true,
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").
+					Consistently().For(time.Second).T().
+					Status(Is(200)).
+					Assert("Should fail when the process logs a stack trace")
+			},
+			shouldPass: false,
+		},
+		{
+			name:   "Stack Trace Without Strict",
+			args:   []string{"--log=panic: runtime error: invalid memory address"},
+			strict: false,
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").
+					Consistently().For(time.Second).T().
+					Status(Is(200)).
+					Assert("Stack traces are ignored without strict mode")
+			},
+			shouldPass: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Command:    helperCommand(t),
+				WorkingDir: t.TempDir(),
+			}
+
+			suite := New().WithConfig(config)
+			if tt.strict {
+				suite.Strict()
+			}
+
+			success := suite.
+				Setup(func(do *Do) {
+					do.Start("svc", tt.args...)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
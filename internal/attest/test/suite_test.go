@@ -0,0 +1,450 @@
+package attest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := &Config{WorkingDir: t.TempDir()}
+
+	output := captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+			}).
+			Test("Passes", func(do *Do) {
+				for range 2 {
+					do.HTTP("svc", "GET", "/").T().
+						Status(Is(200)).
+						Assert("Server should respond")
+				}
+			}).
+			Test("Fails", func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(404)).
+					Assert("Should fail")
+			}).
+			Test("Never Runs", func(do *Do) {}).
+			Run(context.Background())
+	})
+
+	expected := []string{"FAILED", "1 passed, 1 failed, 1 skipped · 3 assertions", config.WorkingDir}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("expected output to contain %q, got:\n%s", e, output)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first two requests so they get retried
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	config := &Config{WorkingDir: t.TempDir()}
+
+	output := captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+			}).
+			Test("Retries", func(do *Do) {
+				do.HTTP("svc", "GET", "/").Eventually().T().
+					Status(Is(200)).
+					Assert("Server should eventually respond")
+			}).
+			Run(context.Background())
+	})
+
+	expected := "Harness: 3 requests (2 retries) · 0 processes started · 0 B sent · 15 B received"
+	if !strings.Contains(output, expected) {
+		t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(config.WorkingDir, "run-*", "metrics.json"))
+	if len(paths) != 1 {
+		t.Fatalf("expected a metrics.json in the run directory, found %v", paths)
+	}
+
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var metrics map[string]int64
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatal(err)
+	}
+
+	if metrics["requests"] != 3 || metrics["retries"] != 2 || metrics["bytes_received"] != 15 {
+		t.Errorf("unexpected metrics: %s", data)
+	}
+}
+
+func TestManifest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	config := &Config{WorkingDir: t.TempDir()}
+
+	captureStdout(t, func() {
+		New().WithConfig(config).WithStage("kv-store", "http-api").
+			Jitter(time.Millisecond, 42).
+			Setup(func(do *Do) {
+				do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+			}).
+			Test("Hello", func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Body(Is("hello")).
+					Assert("Server should respond")
+			}).
+			Run(context.Background())
+	})
+
+	paths, _ := filepath.Glob(filepath.Join(config.WorkingDir, "run-*", "manifest.json"))
+	if len(paths) != 1 {
+		t.Fatalf("expected a manifest.json in the run directory, found %v", paths)
+	}
+
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	if manifest.Version == "" || manifest.Challenge != "kv-store" || manifest.Stage != "http-api" ||
+		manifest.Seed != 42 || !manifest.Passed {
+		t.Errorf("unexpected manifest: %s", data)
+	}
+
+	var files []string
+	for _, file := range manifest.Files {
+		files = append(files, file.Path)
+	}
+
+	if !slices.Contains(files, "metrics.json") || !slices.Contains(files, "trace.log") || slices.Contains(files, "manifest.json") {
+		t.Errorf("unexpected file inventory %v", files)
+	}
+}
+
+func TestResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no such key"))
+	}))
+	defer server.Close()
+
+	config := &Config{WorkingDir: t.TempDir()}
+
+	captureStdout(t, func() {
+		New().WithConfig(config).WithStage("kv-store", "http-api").
+			Setup(func(do *Do) {
+				do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+			}).
+			Test("Passes", func(do *Do) {}).
+			Test("Fails", func(do *Do) {
+				do.HTTP("svc", "PUT", "/kv/a", "1").T().
+					Status(Is(200)).
+					Assert("Server should store the key")
+			}).
+			Run(context.Background())
+	})
+
+	results, err := LatestResults(config.WorkingDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results.Passed || results.Challenge != "kv-store" || results.Stage != "http-api" || len(results.Tests) != 2 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	test := results.FirstFailure()
+	if test == nil || test.Name != "Fails" {
+		t.Fatalf("expected the second test to be the first failure, got %+v", test)
+	}
+
+	if !strings.Contains(test.Failure.Message, "Server should store the key") {
+		t.Errorf("expected the failure message, got %q", test.Failure.Message)
+	}
+
+	exchange := test.Failure.Exchange
+	if exchange == nil || exchange.Method != "PUT" || exchange.RequestBody != "1" ||
+		exchange.Status != 404 || exchange.ResponseBody != "no such key" ||
+		exchange.ResponseHeader.Get("Content-Type") != "text/plain" {
+		t.Errorf("unexpected exchange: %+v", exchange)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var elapsed time.Duration
+	output := captureStdout(t, func() {
+		New().WithConfig(config).
+			Jitter(20*time.Millisecond, 42).
+			Setup(func(do *Do) {
+				do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+			}).
+			Test("Jittered", func(do *Do) {
+				start := time.Now()
+				for range 10 {
+					do.HTTP("svc", "GET", "/").T().
+						Status(Is(200)).
+						Assert("Server should respond")
+				}
+				elapsed = time.Since(start)
+			}).
+			Run(context.Background())
+	})
+
+	expected := "Jittering requests by up to 20ms (seed 42)"
+	if !strings.Contains(output, expected) {
+		t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+	}
+
+	// The delays drawn from seed 42 add up to well over this
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("expected requests to be delayed, took %s", elapsed)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var ran []string
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Matrix("signal", syscall.SIGTERM, syscall.SIGKILL).
+			Matrix("nodes", 3, 5).
+			Test("Cluster", func(do *Do) {
+				signal := Variant[syscall.Signal](do, "signal")
+				nodes := Variant[int](do, "nodes")
+				ran = append(ran, fmt.Sprintf("%d/%d", signal, nodes))
+
+				if signal == syscall.SIGKILL && nodes == 5 {
+					panic("lost writes")
+				}
+			}).
+			Run(context.Background())
+	})
+
+	if success {
+		t.Errorf("suite should fail when any variant fails, got:\n%s", output)
+	}
+
+	// Later variants still run after one fails
+	expectedRuns := []string{"15/3", "15/5", "9/3", "9/5"}
+	if !slices.Equal(ran, expectedRuns) {
+		t.Errorf("expected variants %v to run, got %v", expectedRuns, ran)
+	}
+
+	for _, expected := range []string{
+		"Variant signal=terminated, nodes=3",
+		"✓ signal=killed, nodes=3",
+		"✗ signal=killed, nodes=5",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestSkip(t *testing.T) {
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Test("Runs", func(do *Do) {
+				do.SkipIf(false, "never skipped")
+			}).
+			Test("Platform Specific", func(do *Do) {
+				panic("should not run")
+			}).
+			Test("Optional", func(do *Do) {
+				do.SkipIf(true, "optional feature")
+				panic("should not run")
+			}).
+			Skip("Platform Specific", "requires Linux").
+			Run(context.Background())
+	})
+
+	if !success {
+		t.Errorf("skipped tests should not fail the suite, got:\n%s", output)
+	}
+
+	expected := []string{
+		"Platform Specific (skipped: requires Linux)",
+		"Optional (skipped: optional feature)",
+		"1 passed, 0 failed, 2 skipped",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("expected output to contain %q, got:\n%s", e, output)
+		}
+	}
+}
+
+func TestSkipDuringSetup(t *testing.T) {
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.SkipIf(true, "unsupported platform")
+			}).
+			Test("First", func(do *Do) {
+				panic("should not run")
+			}).
+			Test("Second", func(do *Do) {
+				panic("should not run")
+			}).
+			Run(context.Background())
+	})
+
+	if !success {
+		t.Errorf("skipped tests should not fail the suite, got:\n%s", output)
+	}
+
+	if !strings.Contains(output, "0 passed, 0 failed, 2 skipped") {
+		t.Errorf("expected every test to be skipped, got:\n%s", output)
+	}
+}
+
+func TestXFail(t *testing.T) {
+	config := &Config{WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Test("In Progress", func(do *Do) {
+				panic("not implemented yet")
+			}).
+			Test("Already Works", func(do *Do) {}).
+			Test("Still Runs", func(do *Do) {}).
+			XFail("In Progress", "leader election is under development").
+			XFail("Already Works", "flaky").
+			Run(context.Background())
+	})
+
+	if !success {
+		t.Errorf("expected failures should not fail the suite, got:\n%s", output)
+	}
+
+	expected := []string{
+		"In Progress XFAIL (leader election is under development)",
+		"Already Works XPASS (expected to fail: flaky)",
+		"Still Runs",
+		"2 passed, 0 failed, 0 skipped, 1 xfailed",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("expected output to contain %q, got:\n%s", e, output)
+		}
+	}
+
+	if strings.Contains(output, "not implemented yet") {
+		t.Errorf("expected failure details should not be printed, got:\n%s", output)
+	}
+}
+
+func TestInDir(t *testing.T) {
+	dir := t.TempDir()
+
+	var success bool
+	captureStdout(t, func() {
+		success = New().WithConfig(&Config{WorkingDir: "runs"}).
+			InDir(dir).
+			Test("Nothing", func(do *Do) {}).
+			Run(context.Background())
+	})
+
+	if !success {
+		t.Fatal("suite should pass")
+	}
+
+	// The stage's own WorkingDir is kept, relative to dir
+	runs, err := filepath.Glob(filepath.Join(dir, "runs", "run-*"))
+	if err != nil || len(runs) != 1 {
+		t.Errorf("expected a run in %s, got %v (%v)", filepath.Join(dir, "runs"), runs, err)
+	}
+}
+
+func TestWithMaxRequestsPerSecond(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	port := strings.Split(server.Listener.Addr().String(), ":")[1]
+
+	run := func(requests int, configure func(*Suite) *Suite) (time.Duration, string) {
+		start := time.Now()
+		output := captureStdout(t, func() {
+			suite := New().WithConfig(&Config{WorkingDir: t.TempDir()})
+			configure(suite).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test("Burst", func(do *Do) {
+					for range requests {
+						do.HTTP("svc", "GET", "/").T().
+							Status(Is(200)).
+							Assert("Server should respond")
+					}
+				}).
+				Run(context.Background())
+		})
+
+		return time.Since(start), output
+	}
+
+	// By default, a burst well short of the port range isn't throttled
+	_, output := run(1200, func(s *Suite) *Suite { return s })
+	if strings.Contains(output, "Throttling") {
+		t.Errorf("expected the default not to throttle, got:\n%s", output)
+	}
+
+	elapsed, output := run(30, func(s *Suite) *Suite { return s.WithMaxRequestsPerSecond(20) })
+	if !strings.Contains(output, "Throttling requests to 20/s") || elapsed < 400*time.Millisecond {
+		t.Errorf("expected requests to be throttled to 20/s, took %s:\n%s", elapsed, output)
+	}
+}
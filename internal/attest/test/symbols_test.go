@@ -0,0 +1,97 @@
+package attest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestDetectSymbols(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		unicode bool
+	}{
+		{
+			name:    "UTF-8 Locale",
+			env:     map[string]string{"LANG": "en_US.UTF-8"},
+			unicode: true,
+		},
+		{
+			name:    "Unset Locale",
+			unicode: true,
+		},
+		{
+			name:    "C Locale",
+			env:     map[string]string{"LANG": "C"},
+			unicode: false,
+		},
+		{
+			name:    "LC_ALL Overrides LANG",
+			env:     map[string]string{"LC_ALL": "POSIX", "LANG": "en_US.UTF-8"},
+			unicode: false,
+		},
+		{
+			name:    "NO_COLOR",
+			env:     map[string]string{"NO_COLOR": "1", "LANG": "en_US.UTF-8"},
+			unicode: false,
+		},
+		{
+			name:    "Dumb Terminal",
+			env:     map[string]string{"TERM": "dumb", "LANG": "en_US.UTF-8"},
+			unicode: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"NO_COLOR", "TERM", "LC_ALL", "LC_CTYPE", "LANG"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			symbols := DetectSymbols()
+			if symbols.Unicode != tt.unicode {
+				t.Errorf("expected Unicode symbols to be %t, got %+v", tt.unicode, symbols)
+			}
+
+			if !tt.unicode && symbols.Check != "+" {
+				t.Errorf("expected ASCII check mark, got %q", symbols.Check)
+			}
+		})
+	}
+}
+
+func TestASCIISymbolsKeepProcessOutput(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	defer func(marks Symbols) { Marks = marks }(Marks)
+	Marks = DetectSymbols()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("a → b ✓"))
+	}))
+	defer server.Close()
+
+	config := &Config{WorkingDir: t.TempDir()}
+	output := captureStdout(t, func() {
+		New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+			}).
+			Test("Body", func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Body(Is("a -> b")).
+					Assert("The body should be shown as the process sent it")
+			}).
+			Run(context.Background())
+	})
+
+	for _, expected := range []string{`"a → b ✓"`, "GET / -> 200", "1 assertions | "} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
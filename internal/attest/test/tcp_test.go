@@ -0,0 +1,160 @@
+package attest_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestTCP(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(net.Conn)
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Basic OK",
+			handler: func(conn net.Conn) {
+				line, _ := bufio.NewReader(conn).ReadString('\n')
+				if line == "PING\r\n" {
+					conn.Write([]byte("+PONG\r\n"))
+				}
+			},
+			testFunc: func(do *Do) {
+				do.TCP("svc").Send([]byte("PING\r\n")).T().
+					Response(Is("+PONG\r\n")).
+					Assert("Server should respond to PING")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Response Mismatch",
+			handler: func(conn net.Conn) {
+				conn.Write([]byte("-ERR unknown command\r\n"))
+			},
+			testFunc: func(do *Do) {
+				do.TCP("svc").Send([]byte("PING\r\n")).T().
+					Response(Is("+PONG\r\n")).
+					Assert("Should fail when response doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Until Delimiter",
+			handler: func(conn net.Conn) {
+				conn.Write([]byte("first\n"))
+				time.Sleep(time.Second)
+				conn.Write([]byte("second\n"))
+			},
+			testFunc: func(do *Do) {
+				do.TCP("svc").Send([]byte("HELLO\n")).Until([]byte("\n")).T().
+					Response(Is("first\n")).
+					Assert("Should stop reading at the delimiter")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Connection Closed",
+			handler: func(conn net.Conn) {
+				conn.Write([]byte("bye"))
+				conn.Close()
+			},
+			testFunc: func(do *Do) {
+				do.TCP("svc").Send([]byte("QUIT\r\n")).T().
+					Response(Is("bye")).
+					Assert("Should read until the connection closes")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Eventually OK",
+			handler: func() func(net.Conn) {
+				readyAt := time.Now().Add(500 * time.Millisecond)
+				return func(conn net.Conn) {
+					if time.Now().After(readyAt) {
+						conn.Write([]byte("READY"))
+					} else {
+						conn.Write([]byte("LOADING"))
+					}
+				}
+			}(),
+			testFunc: func(do *Do) {
+				do.TCP("svc").Send([]byte("STATUS\r\n")).
+					Eventually().T().
+					Response(Is("READY")).
+					Assert("Server should eventually become ready")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Consistently Failure",
+			handler: func() func(net.Conn) {
+				failAt := time.Now().Add(300 * time.Millisecond)
+				return func(conn net.Conn) {
+					if time.Now().Before(failAt) {
+						conn.Write([]byte("OK"))
+					} else {
+						conn.Write([]byte("FAIL"))
+					}
+				}
+			}(),
+			testFunc: func(do *Do) {
+				do.TCP("svc").Send([]byte("STATUS\r\n")).
+					Consistently().For(time.Second).T().
+					Response(Is("OK")).
+					Assert("Should fail when the response changes")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+
+					go func() {
+						defer conn.Close()
+						tt.handler(conn)
+					}()
+				}
+			}()
+
+			port := strings.Split(listener.Addr().String(), ":")[1]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
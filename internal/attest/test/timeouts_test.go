@@ -0,0 +1,197 @@
+package attest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestTimeoutMultiplier(t *testing.T) {
+	tests := []struct {
+		name       string
+		multiplier string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name:       "Scaled Retry Timeout",
+			multiplier: "4",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").Eventually().T().
+					Status(Is(200)).
+					Assert("Server should be ready within the scaled timeout")
+			},
+			shouldPass: true,
+		},
+		{
+			name:       "Scaled Within",
+			multiplier: "4",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").Eventually().Within(100 * time.Millisecond).T().
+					Status(Is(200)).
+					Assert("Server should be ready within the scaled timeout")
+			},
+			shouldPass: true,
+		},
+		{
+			name:       "Scaled Limit",
+			multiplier: "4",
+			testFunc: func(do *Do) {
+				if limit := do.Scaled(100 * time.Millisecond); limit != 400*time.Millisecond {
+					panic(fmt.Sprintf("Expected limit: 400ms\n  Actual limit: %s", limit))
+				}
+			},
+			shouldPass: true,
+		},
+		{
+			name:       "Unscaled",
+			multiplier: "1",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").Eventually().T().
+					Status(Is(200)).
+					Assert("Should time out before the server is ready")
+			},
+			shouldPass: false,
+		},
+		{
+			name:       "Invalid Multiplier",
+			multiplier: "fast",
+			testFunc:   func(do *Do) {},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LSFR_TIMEOUT_MULTIPLIER", tt.multiplier)
+
+			// The server only becomes ready after the unscaled timeout
+			ready := time.Now().Add(250 * time.Millisecond)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if time.Now().Before(ready) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			config := &Config{
+				WorkingDir:          t.TempDir(),
+				DefaultRetryTimeout: 100 * time.Millisecond,
+				RetryPollInterval:   10 * time.Millisecond,
+			}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+					}).
+					Test(tt.name, func(do *Do) {
+						tt.testFunc(do)
+					}).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed:\n%s", tt.name, output)
+				} else {
+					t.Errorf("%s test should fail but passed:\n%s", tt.name, output)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "HTTP Default Timeout",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/slow").T().
+					Status(Is(200)).
+					Assert("Should time out under the configured timeout")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "HTTP Longer Timeout",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/slow").WithTimeout(time.Second).T().
+					Status(Is(200)).
+					Assert("Slow request should get more headroom")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "CLI Default Timeout",
+			testFunc: func(do *Do) {
+				do.Exec("-c", "sleep 0.3").T().
+					ExitCode(Is(0)).
+					Assert("Should time out under the configured timeout")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "CLI Longer Timeout",
+			testFunc: func(do *Do) {
+				do.Exec("-c", "sleep 0.3").WithTimeout(time.Second).T().
+					ExitCode(Is(0)).
+					Assert("Slow command should get more headroom")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Other Requests Keep Default",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/slow").WithTimeout(time.Second).T().
+					Status(Is(200)).
+					Assert("Slow request should get more headroom")
+				do.HTTP("svc", "GET", "/slow").T().
+					Status(Is(200)).
+					Assert("Should time out as only the first request had more headroom")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(300 * time.Millisecond)
+			}))
+			defer server.Close()
+
+			config := &Config{
+				Command:           "sh",
+				WorkingDir:        t.TempDir(),
+				ExecuteTimeout:    100 * time.Millisecond,
+				TimeoutMultiplier: 1,
+			}
+
+			var success bool
+			output := captureStdout(t, func() {
+				success = New().WithConfig(config).
+					Setup(func(do *Do) {
+						do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+					}).
+					Test(tt.name, tt.testFunc).
+					Run(context.Background())
+			})
+
+			if success != tt.shouldPass {
+				t.Errorf("expected pass=%v, got output:\n%s", tt.shouldPass, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package attest_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestTLS(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	})
+
+	tests := []struct {
+		name string
+		// server is mocked as the process; if nil, the helper process is started with StartTLS
+		server     func() *httptest.Server
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "StartTLS OK",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					Body(Is("OK")).
+					CertificateCN(Is("localhost")).
+					CertificateSANs(Contains("localhost"), Contains("127.0.0.1")).
+					TLSVersion(Is("TLS 1.3")).
+					ALPN(Is("h2")).
+					Assert("Process should serve HTTPS with the generated certificate")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Certificate CN Mismatch",
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					CertificateCN(Is("example.com")).
+					Assert("Should fail when the certificate's CN doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Restart Keeps TLS",
+			testFunc: func(do *Do) {
+				do.Restart("svc")
+
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					TLSVersion(Is("TLS 1.3")).
+					Assert("Process should still serve HTTPS after a restart")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Per-Request TLS",
+			server: func() *httptest.Server {
+				return httptest.NewTLSServer(ok)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").TLS().T().
+					Status(Is(200)).
+					CertificateSANs(Contains("example.com")).
+					ALPN(Is("http/1.1")).
+					Assert("Request should be made over HTTPS")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Old Protocol Version",
+			server: func() *httptest.Server {
+				server := httptest.NewUnstartedServer(ok)
+				server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+				server.StartTLS()
+				return server
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").TLS().T().
+					TLSVersion(Is("TLS 1.3")).
+					Assert("Should fail when the server only speaks TLS 1.2")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Plaintext Connection",
+			server: func() *httptest.Server {
+				return httptest.NewServer(ok)
+			},
+			testFunc: func(do *Do) {
+				do.HTTP("svc", "GET", "/").T().
+					Status(Is(200)).
+					CertificateCN(Is("localhost")).
+					Assert("Should fail when certificate checks run without TLS")
+			},
+			shouldPass: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{WorkingDir: t.TempDir()}
+
+			var port string
+			if tt.server != nil {
+				server := tt.server()
+				defer server.Close()
+				port = server.URL[strings.LastIndex(server.URL, ":")+1:]
+			} else {
+				config.Command = helperCommand(t)
+			}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					if port != "" {
+						do.MockProcess("svc", port)
+					} else {
+						do.StartTLS("svc")
+					}
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package attest_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestUDP(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(reply func(string), payload string)
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Basic OK",
+			handler: func(reply func(string), payload string) {
+				if payload == "PING" {
+					reply("PONG")
+				}
+			},
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("PING")).T().
+					Response(Is("PONG")).
+					Assert("Server should respond to PING")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Response Mismatch",
+			handler: func(reply func(string), payload string) {
+				reply("NOPE")
+			},
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("PING")).T().
+					Response(Is("PONG")).
+					Assert("Should fail when response doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name:    "No Reply Expected",
+			handler: func(reply func(string), payload string) {},
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("GARBAGE")).ReplyTimeout(200 * time.Millisecond).T().
+					NoReply().
+					Assert("Server should drop malformed datagrams")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Unexpected Reply",
+			handler: func(reply func(string), payload string) {
+				reply("ERR")
+			},
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("GARBAGE")).T().
+					NoReply().
+					Assert("Should fail when the server replies")
+			},
+			shouldPass: false,
+		},
+		{
+			name:    "Missing Reply",
+			handler: func(reply func(string), payload string) {},
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("PING")).ReplyTimeout(200 * time.Millisecond).T().
+					Response(Is("PONG")).
+					Assert("Should fail when no reply arrives")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Multiple Datagrams",
+			handler: func(reply func(string), payload string) {
+				reply("one")
+				reply("two")
+			},
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("LIST")).T().
+					Count(Is(2)).
+					Datagram(0, Is("one")).
+					Datagram(1, Is("two")).
+					Assert("Server should reply with two datagrams")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Missing Second Datagram",
+			handler: func(reply func(string), payload string) {
+				reply("one")
+			},
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("LIST")).T().
+					Datagram(1, Is("two")).
+					Assert("Should fail when the second datagram never arrives")
+			},
+			shouldPass: false,
+		},
+		{
+			name:    "Datagram Past Replies",
+			handler: func(reply func(string), payload string) {},
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("LIST")).ReplyTimeout(200*time.Millisecond).T().
+					Datagram(5, Is("six")).
+					Assert("Should fail when there are fewer datagrams than checked")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Eventually OK",
+			handler: func() func(func(string), string) {
+				readyAt := time.Now().Add(500 * time.Millisecond)
+				return func(reply func(string), payload string) {
+					if time.Now().After(readyAt) {
+						reply("READY")
+					}
+				}
+			}(),
+			testFunc: func(do *Do) {
+				do.UDP("svc", []byte("STATUS")).ReplyTimeout(100 * time.Millisecond).
+					Eventually().T().
+					Response(Is("READY")).
+					Assert("Server should eventually become ready")
+			},
+			shouldPass: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			go func() {
+				buf := make([]byte, 65535)
+				for {
+					n, addr, err := conn.ReadFrom(buf)
+					if err != nil {
+						return
+					}
+
+					reply := func(msg string) { conn.WriteTo([]byte(msg), addr) }
+					tt.handler(reply, string(buf[:n]))
+				}
+			}()
+
+			port := strings.Split(conn.LocalAddr().String(), ":")[1]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
+
+func TestUDPReportsAllMismatches(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			conn.WriteTo([]byte("ERR"), addr)
+		}
+	}()
+
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(conn.LocalAddr().String(), ":")[1])
+		}).
+		Test("Mismatches", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.UDP("svc", []byte("PING")).ReplyTimeout(200*time.Millisecond).T().
+				Count(Is(2)).
+				Response(Is("PONG")).
+				Datagram(1, Is("DONE")).
+				Assert("Every mismatch should be reported")
+		}).
+		Run(context.Background())
+
+	for _, expected := range []string{
+		"Expected datagrams: 2\n  Actual datagrams: 1",
+		"Expected datagram #1: PONG\n  Actual datagram #1: \"ERR\"",
+		"Expected datagram #2: DONE\n  Actual: no datagram within 200ms",
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("expected failure to contain %q, got:\n%s", expected, message)
+		}
+	}
+}
+
+func TestStartUDP(t *testing.T) {
+	config := &Config{Command: helperCommand(t), WorkingDir: t.TempDir()}
+
+	var success bool
+	output := captureStdout(t, func() {
+		success = New().WithConfig(config).
+			Setup(func(do *Do) {
+				do.StartUDP("svc", "--udp")
+			}).
+			Test("Echo", func(do *Do) {
+				do.UDP("svc", []byte("PING")).T().
+					Response(Is("PING")).
+					Assert("Server should be ready once its port is bound")
+			}).
+			Run(context.Background())
+	})
+
+	if !success {
+		t.Fatalf("expected the process to be ready once bound:\n%s", output)
+	}
+}
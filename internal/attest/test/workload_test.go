@@ -0,0 +1,99 @@
+package attest_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+func TestWorkload(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload Workload
+		check    func(t *testing.T, ops []WorkloadOp)
+	}{
+		{
+			name:     "Read Ratio",
+			workload: Workload{Keys: 100, Reads: 0.9, Seed: 1},
+			check: func(t *testing.T, ops []WorkloadOp) {
+				writes := 0
+				for _, op := range ops {
+					if op.Write {
+						writes++
+					} else if op.Value != "" {
+						t.Errorf("expected reads to have no value, got %q", op.Value)
+					}
+				}
+
+				if writes < 800 || writes > 1200 {
+					t.Errorf("expected about 1000 writes, got %d", writes)
+				}
+			},
+		},
+		{
+			name:     "Zipfian Keys",
+			workload: Workload{Keys: 1000, Skew: 1.2, Seed: 1},
+			check: func(t *testing.T, ops []WorkloadOp) {
+				hot := 0
+				for _, op := range ops {
+					if op.Key == "key0" || op.Key == "key1" || op.Key == "key2" {
+						hot++
+					}
+				}
+
+				// Uniform keys would send 0.3% of the traffic to the first three
+				if hot < len(ops)/4 {
+					t.Errorf("expected hot keys to get most of the traffic, got %d of %d", hot, len(ops))
+				}
+			},
+		},
+		{
+			name:     "Uniform Value Sizes",
+			workload: Workload{Keys: 10, ValueSize: UniformSize(10, 20), Seed: 1},
+			check: func(t *testing.T, ops []WorkloadOp) {
+				for _, op := range ops {
+					if len(op.Value) < 10 || len(op.Value) > 20 {
+						t.Fatalf("expected values of 10 to 20 bytes, got %d", len(op.Value))
+					}
+				}
+			},
+		},
+		{
+			name:     "Long-Tailed Value Sizes",
+			workload: Workload{Keys: 10, ValueSize: LogNormalSize(100, 1.5, 4096), Seed: 1},
+			check: func(t *testing.T, ops []WorkloadOp) {
+				small, large := 0, 0
+				for _, op := range ops {
+					switch {
+					case len(op.Value) < 1 || len(op.Value) > 4096:
+						t.Fatalf("expected values of 1 to 4096 bytes, got %d", len(op.Value))
+					case len(op.Value) <= 200:
+						small++
+					case len(op.Value) >= 1000:
+						large++
+					}
+				}
+
+				if small < len(ops)/2 || large == 0 {
+					t.Errorf("expected mostly small values and some large ones, got %d small and %d large", small, large)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := tt.workload.Ops(10_000)
+			if len(ops) != 10_000 {
+				t.Fatalf("expected 10000 operations, got %d", len(ops))
+			}
+
+			if !reflect.DeepEqual(ops, tt.workload.Ops(10_000)) {
+				t.Errorf("expected the same seed to generate the same operations")
+			}
+
+			tt.check(t, ops)
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package attest_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/st3v3nmw/lsfr/internal/attest"
+)
+
+// wsServerConn is a minimal server side of a websocket connection.
+type wsServerConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+func (c *wsServerConn) send(message string) {
+	frame := []byte{0x81}
+	if len(message) < 126 {
+		frame = append(frame, byte(len(message)))
+	} else {
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(message)))
+	}
+	frame = append(frame, message...)
+
+	c.rw.Write(frame)
+	c.rw.Flush()
+}
+
+// receive reads the next masked text frame from the client.
+func (c *wsServerConn) receive() (string, bool) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		return "", false
+	}
+
+	length := int(head[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		io.ReadFull(c.rw, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+
+	var mask [4]byte
+	io.ReadFull(c.rw, mask[:])
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return "", false
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	if head[0]&0x0F == 0x8 {
+		return "", false
+	}
+
+	return string(payload), true
+}
+
+// wsHandler upgrades the request and hands the connection to handle.
+func wsHandler(handle func(*wsServerConn)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+		rw.WriteString("Upgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+		rw.Flush()
+
+		handle(&wsServerConn{conn: conn, rw: rw})
+	}
+}
+
+func TestWS(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(*wsServerConn)
+		testFunc   func(*Do)
+		shouldPass bool
+	}{
+		{
+			name: "Echo OK",
+			handler: func(c *wsServerConn) {
+				for {
+					msg, ok := c.receive()
+					if !ok {
+						return
+					}
+					c.send("echo: " + msg)
+				}
+			},
+			testFunc: func(do *Do) {
+				do.WS("svc", "/ws").Send("hello").T().
+					Response(Is("echo: hello")).
+					Assert("Server should echo messages")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Response Mismatch",
+			handler: func(c *wsServerConn) {
+				c.receive()
+				c.send("wrong")
+			},
+			testFunc: func(do *Do) {
+				do.WS("svc", "/ws").Send("hello").T().
+					Response(Is("echo: hello")).
+					Assert("Should fail when the message doesn't match")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Oversized Frame",
+			handler: func(c *wsServerConn) {
+				c.receive()
+				// Claims a 1 TiB payload the harness mustn't allocate
+				frame := binary.BigEndian.AppendUint64([]byte{0x81, 127}, 1<<40)
+				c.rw.Write(frame)
+				c.rw.Flush()
+				time.Sleep(time.Second)
+			},
+			testFunc: func(do *Do) {
+				do.WS("svc", "/ws").Send("hello").T().
+					Response(Is("echo: hello")).
+					Assert("Should fail when a frame is too large")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "JSON Message",
+			handler: func(c *wsServerConn) {
+				c.send(`{"event":"set","key":"a","value":"1"}`)
+			},
+			testFunc: func(do *Do) {
+				do.WS("svc", "/watch").T().
+					Response(JSON("event", Is("set")), JSON("key", Is("a"))).
+					Assert("Server should push change events")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Multiple Messages",
+			handler: func(c *wsServerConn) {
+				c.send("one")
+				time.Sleep(300 * time.Millisecond)
+				c.send("two")
+			},
+			testFunc: func(do *Do) {
+				do.WS("svc", "/ws").T().
+					Message(0, Is("one")).
+					Message(1, Contains("tw")).
+					Assert("Server should push both messages")
+			},
+			shouldPass: true,
+		},
+		{
+			name: "Count",
+			handler: func(c *wsServerConn) {
+				c.send("one")
+				c.send("two")
+				c.send("three")
+				time.Sleep(time.Second)
+			},
+			testFunc: func(do *Do) {
+				do.WS("svc", "/ws").T().
+					Count(Is(2)).
+					Assert("Should fail when too many messages arrive")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Missing Message",
+			handler: func(c *wsServerConn) {
+				c.send("one")
+				time.Sleep(time.Second)
+			},
+			testFunc: func(do *Do) {
+				do.WS("svc", "/ws").ReplyTimeout(300*time.Millisecond).T().
+					Message(1, Is("two")).
+					Assert("Should fail when the second message never arrives")
+			},
+			shouldPass: false,
+		},
+		{
+			name: "Large Message",
+			handler: func(c *wsServerConn) {
+				msg, _ := c.receive()
+				c.send(msg)
+			},
+			testFunc: func(do *Do) {
+				do.WS("svc", "/ws").Send(strings.Repeat("x", 1000)).T().
+					Response(HasLen[string](1000)).
+					Assert("Server should echo large messages")
+			},
+			shouldPass: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(wsHandler(tt.handler))
+			defer server.Close()
+
+			port := strings.Split(server.URL, ":")[2]
+			config := &Config{WorkingDir: t.TempDir()}
+
+			success := New().WithConfig(config).
+				Setup(func(do *Do) {
+					do.MockProcess("svc", port)
+				}).
+				Test(tt.name, func(do *Do) {
+					tt.testFunc(do)
+				}).
+				Run(context.Background())
+
+			if success != tt.shouldPass {
+				if tt.shouldPass {
+					t.Errorf("%s test should pass but failed", tt.name)
+				} else {
+					t.Errorf("%s test should fail but passed", tt.name)
+				}
+			}
+		})
+	}
+}
+
+func TestWSReportsAllMismatches(t *testing.T) {
+	server := httptest.NewServer(wsHandler(func(c *wsServerConn) {
+		c.receive()
+		c.send("wrong")
+	}))
+	defer server.Close()
+
+	var message string
+
+	config := &Config{WorkingDir: t.TempDir()}
+	New().WithConfig(config).
+		Setup(func(do *Do) {
+			do.MockProcess("svc", strings.Split(server.URL, ":")[2])
+		}).
+		Test("Mismatches", func(do *Do) {
+			defer func() {
+				message = fmt.Sprint(recover())
+			}()
+
+			do.WS("svc", "/ws").Send("hello").ReplyTimeout(200*time.Millisecond).T().
+				Count(Is(2)).
+				Response(Is("echo: hello")).
+				Message(1, Is("bye")).
+				Assert("Every mismatch should be reported")
+		}).
+		Run(context.Background())
+
+	for _, expected := range []string{
+		"Expected messages: 2\n  Actual messages: 1",
+		"Expected message #1: echo: hello\n  Actual message #1: \"wrong\"",
+		"Expected message #2: bye\n  Actual: no message within 200ms",
+	} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("expected failure to contain %q, got:\n%s", expected, message)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package attest
 
-import "strconv"
+import (
+	"os"
+	"strconv"
+)
 
 // Do
 
@@ -15,3 +18,19 @@ func (do *Do) MockProcess(name, realPort string) {
 
 	do.processes.Set(name, proc)
 }
+
+func (do *Do) MockSocketProcess(name, socketPath string) {
+	proc := &Process{socketPath: socketPath}
+
+	do.processes.Set(name, proc)
+}
+
+func (do *Do) MockLog(name, output string) {
+	logFile, err := os.OpenFile(do.logPath(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		panic(err.Error())
+	}
+	defer logFile.Close()
+
+	logFile.WriteString(output)
+}
@@ -0,0 +1,95 @@
+package attest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// generateCertificate writes a self-signed certificate for localhost and its
+// private key to dir, returning their paths.
+func generateCertificate(dir, name string) (string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", "", err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "localhost", Organization: []string{"lsfr"}},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", "", err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	certPath := filepath.Join(dir, fmt.Sprintf("%s-cert.pem", name))
+	keyPath := filepath.Join(dir, fmt.Sprintf("%s-key.pem", name))
+
+	err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		return "", "", err
+	}
+
+	return certPath, keyPath, nil
+}
+
+// certificateSANs lists the DNS names and IP addresses of the certificate,
+// comma-separated.
+func certificateSANs(cert *x509.Certificate) string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	return strings.Join(sans, ", ")
+}
+
+// tlsDetails summarizes a TLS connection for failure messages.
+func tlsDetails(state *tls.ConnectionState) string {
+	if state == nil {
+		return "no TLS"
+	}
+
+	details := fmt.Sprintf("%s, ALPN %q", tls.VersionName(state.Version), state.NegotiatedProtocol)
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		details += fmt.Sprintf(", CN %q, SANs [%s]", cert.Subject.CommonName, certificateSANs(cert))
+	}
+
+	return details
+}
@@ -0,0 +1,70 @@
+package attest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TraceHeader is the HTTP header carrying the trace ID of each assertion.
+const TraceHeader = "X-Lsfr-Trace"
+
+// traceLog records issued requests to a file in the run directory so
+// learners can correlate failures with their own server logs.
+type traceLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// newTraceLog creates the trace log at path.
+func newTraceLog(path string) *traceLog {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create trace log: %v", err))
+	}
+
+	return &traceLog{file: file}
+}
+
+// record writes a timestamped line to the trace log.
+func (l *traceLog) record(format string, args ...any) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return
+	}
+
+	timestamp := time.Now().Format("15:04:05.000")
+	fmt.Fprintf(l.file, "%s %s\n", timestamp, fmt.Sprintf(format, args...))
+}
+
+// close closes the underlying file.
+func (l *traceLog) close() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+// newTraceID returns a random identifier for a single assertion.
+func newTraceID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
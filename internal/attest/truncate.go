@@ -0,0 +1,223 @@
+package attest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// differ is a checker that can explain how a value fails it, e.g. JSONEquals.
+type differ interface {
+	differences(actual string) []string
+}
+
+// bodyMismatch is mismatch for bodies and outputs, which are shortened for
+// display. A value checked against an exact expectation is cut around where
+// the two first differ, so the difference stays visible, and one checked by
+// a differ, e.g. JSONEquals or ContainsAll, is followed by its differences.
+func bodyMismatch(value string, checkers []Checker[string], label string, config *Config) string {
+	maxLen := config.maxBodyLength(len("Actual " + label + ": "))
+
+	var lines, diffs []string
+	actual := clipValue(value, maxLen, -1)
+	for _, checker := range checkers {
+		if checker.Check(value) {
+			continue
+		}
+
+		expected := clipValue(checker.Expected(), maxLen, -1)
+		if is, ok := checker.(isChecker[string]); ok {
+			at := firstDifference(is.value, value)
+			expected = clipValue(is.value, maxLen, at)
+			actual = clipValue(value, maxLen, at)
+
+			if expected != is.value || actual != value || strings.Contains(is.value+value, "\n") {
+				diffs = append(diffs, bodyDiff(is.value, value, at, maxLen)...)
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("Expected %s: %s", checkerLabel(checker, label), expected))
+
+		if d, ok := checker.(differ); ok {
+			diffs = append(diffs, d.differences(value)...)
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	lines = append(lines, fmt.Sprintf("Actual %s: %q", label, actual))
+	if len(diffs) > 0 {
+		lines = append(lines, "Differences:\n    "+strings.Join(diffs, "\n    "))
+	}
+
+	return strings.Join(lines, "\n  ")
+}
+
+// maxDiffLines is how many lines of each side a body diff shows.
+const maxDiffLines = 10
+
+// diffContext is how many runes before the first difference a body diff shows.
+const diffContext = 20
+
+// bodyDiff describes where a long or multi-line actual value first differs
+// from the expected one: a window around the difference with a caret under
+// it, or, for multi-line values, the lines that differ, diff-style.
+func bodyDiff(expected, actual string, at, maxLen int) []string {
+	if at < 0 {
+		return nil
+	}
+
+	prefix := []rune(actual)[:at]
+	line, column := 1, at
+	for i, r := range prefix {
+		if r == '\n' {
+			line, column = line+1, at-i-1
+		}
+	}
+
+	diff := []string{fmt.Sprintf("first difference at offset %d, line %d column %d", at, line, column+1)}
+	if !strings.Contains(expected+actual, "\n") {
+		start := max(at-diffContext, 0)
+		window := func(s string) string {
+			runes := []rune(s)
+			return strconv.Quote(string(runes[min(start, len(runes)):min(at+diffContext, len(runes))]))
+		}
+
+		caret := utf8.RuneCountInString(strconv.Quote(string(prefix[start:]))) - 1
+		return append(diff,
+			"- "+window(expected),
+			"+ "+window(actual),
+			"  "+strings.Repeat(" ", caret)+"^")
+	}
+
+	// Lines in common at the start and end are left out, the rest differ
+	expectedLines, actualLines := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+	first := line - 1
+	last := 0
+	for last < len(expectedLines)-first && last < len(actualLines)-first &&
+		expectedLines[len(expectedLines)-1-last] == actualLines[len(actualLines)-1-last] {
+		last++
+	}
+
+	diff = append(diff, fmt.Sprintf("@@ line %d @@", line))
+	for _, side := range []struct {
+		mark  string
+		lines []string
+	}{{"-", expectedLines[first : len(expectedLines)-last]}, {"+", actualLines[first : len(actualLines)-last]}} {
+		for i, l := range side.lines {
+			if i == maxDiffLines {
+				diff = append(diff, fmt.Sprintf("%s %s[%d more lines]", side.mark, Marks.Ellipsis, len(side.lines)-i))
+				break
+			}
+
+			// The first line is clipped around the difference, the rest from the start
+			at := -1
+			if i == 0 {
+				at = column
+			}
+			diff = append(diff, fmt.Sprintf("%s %s", side.mark, clipValue(l, maxLen, at)))
+		}
+	}
+
+	return diff
+}
+
+// maxBodyLength returns how many runes of a body fit on a line after a
+// label of labelLen, within the configured line width if there's one.
+func (c *Config) maxBodyLength(labelLen int) int {
+	maxLen := c.MaxBodyLength
+	if c.LineWidth > 0 {
+		// Quoting adds at least two characters and the line is indented by two
+		width := max(c.LineWidth-labelLen-4, clipMinLength)
+		if maxLen < 0 || width < maxLen {
+			maxLen = width
+		}
+	}
+
+	return maxLen
+}
+
+// clipMinLength is the shortest a value is clipped to.
+const clipMinLength = 40
+
+// clipMarkerLength is about how long the marker of each clipped run is, e.g. "…[9800 chars]…".
+const clipMarkerLength = 16
+
+// clipValue shortens s to about maxLen runes for display, keeping its start,
+// its end and, if at isn't negative, the runes around at, e.g. where s first
+// differs from an expected value. A negative maxLen leaves s as is.
+func clipValue(s string, maxLen, at int) string {
+	if maxLen < 0 || utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+
+	runes := []rune(s)
+	maxLen = max(maxLen, clipMinLength)
+
+	// Runes kept, as [start, end) ranges in order, leaving room for the markers
+	var keep [][2]int
+	if at < 0 || at >= len(runes) {
+		n := (maxLen - clipMarkerLength) / 2
+		keep = [][2]int{{0, n}, {len(runes) - n, len(runes)}}
+	} else {
+		n := maxLen - 2*clipMarkerLength
+		edge := n / 4
+		start := max(at-n/8, 0)
+		end := min(start+n/2, len(runes))
+		keep = [][2]int{{0, edge}, {start, end}, {len(runes) - edge, len(runes)}}
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, r := range keep {
+		start := max(r[0], last)
+		if start >= r[1] {
+			continue
+		}
+
+		if start > last {
+			fmt.Fprintf(&b, "%s[%d chars]%s", Marks.Ellipsis, start-last, Marks.Ellipsis)
+		}
+
+		b.WriteString(string(runes[start:r[1]]))
+		last = r[1]
+	}
+
+	return b.String()
+}
+
+// firstDifference returns the index of the first rune where a and b differ,
+// or -1 if they're equal.
+func firstDifference(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	for i := range min(len(ra), len(rb)) {
+		if ra[i] != rb[i] {
+			return i
+		}
+	}
+
+	if len(ra) == len(rb) {
+		return -1
+	}
+
+	return min(len(ra), len(rb))
+}
+
+// fitLines cuts the lines of a failure message down to width, if it's positive.
+func fitLines(msg string, width int) string {
+	if width <= 0 {
+		return msg
+	}
+
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) > width {
+			lines[i] = truncateValue(line, width)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,144 @@
+package attest
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stackTracePatterns are log fragments that indicate a crash or unhandled error
+// in common languages.
+var stackTracePatterns = []string{
+	"panic:",
+	"goroutine 1 [running]",
+	"fatal error:",
+	"Traceback (most recent call last)",
+	"Exception in thread",
+	"stack backtrace:",
+	"panicked at",
+	"Segmentation fault",
+	"Uncaught exception",
+}
+
+// watchdog records failures detected in the background, such as unexpected
+// process exits or stack traces in logs, so the current test fails immediately.
+type watchdog struct {
+	mu      sync.Mutex
+	failure string
+}
+
+// fail records the failure if none has been recorded yet.
+func (w *watchdog) fail(msg string) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failure == "" {
+		w.failure = msg
+	}
+}
+
+// check panics with the recorded failure, if any.
+func (w *watchdog) check() {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	failure := w.failure
+	w.failure = ""
+	w.mu.Unlock()
+
+	if failure != "" {
+		panic(failure)
+	}
+}
+
+// checkAfterError gives the watchdog a moment to notice a crash that caused
+// an operation to fail, so the root cause is reported instead of a symptom.
+func (w *watchdog) checkAfterError(ctx context.Context) {
+	if w == nil {
+		return
+	}
+
+	eventually(ctx, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		return w.failure != ""
+	}, 500*time.Millisecond, 50*time.Millisecond)
+
+	w.check()
+}
+
+// enableStrict starts watching process logs for stack traces.
+func (do *Do) enableStrict() {
+	go func() {
+		offsets := map[string]int64{}
+
+		for {
+			select {
+			case <-do.runCtx.Done():
+				return
+			case <-time.After(do.config.RetryPollInterval):
+			}
+
+			do.processes.Range(func(name string, _ *Process) bool {
+				offsets[name] = do.scanLog(name, offsets[name])
+				return true
+			})
+		}
+	}()
+}
+
+// scanLog checks complete log lines written after offset for stack traces
+// and returns the offset up to which the log has been scanned.
+func (do *Do) scanLog(name string, offset int64) int64 {
+	file, err := os.Open(do.logPath(name))
+	if err != nil {
+		return offset
+	}
+	defer file.Close()
+
+	_, err = file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return offset
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return offset
+	}
+
+	// Leave partial lines for the next scan
+	end := strings.LastIndexByte(string(data), '\n')
+	if end < 0 {
+		return offset
+	}
+
+	lines := strings.Split(string(data[:end]), "\n")
+	for i, line := range lines {
+		for _, pattern := range stackTracePatterns {
+			if strings.Contains(line, pattern) {
+				excerpt := lines[i:min(i+20, len(lines))]
+				do.watchdog.fail("Process " + name + " logged a stack trace.\n\n  Log excerpt:\n" +
+					indent(strings.Join(excerpt, "\n"), "    "))
+
+				return offset + int64(end) + 1
+			}
+		}
+	}
+
+	return offset + int64(end) + 1
+}
+
+// indent prefixes every line of s with prefix.
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,118 @@
+package attest
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Workload is a profile of key-value traffic for load and soak stages, so
+// they exercise hot keys, mixed reads and writes and uneven value sizes
+// the way real clients do rather than writing sequential keys once.
+type Workload struct {
+	// Keys is the number of distinct keys, named "key0" to "key<Keys-1>"
+	Keys int
+
+	// Skew is the exponent of the zipfian key distribution. Zero picks keys
+	// uniformly, otherwise it must be greater than 1 and larger values send
+	// more of the traffic to the first few keys.
+	Skew float64
+
+	// Reads is the fraction of operations that are reads, from 0 to 1
+	Reads float64
+
+	// ValueSize draws the size of each written value in bytes, or writes
+	// 100-byte values if unset
+	ValueSize SizeDistribution
+
+	// Seed makes the generated operations reproducible
+	Seed uint64
+}
+
+// WorkloadOp is a single operation generated from a workload.
+type WorkloadOp struct {
+	Write bool
+	Key   string
+
+	// Value is set for writes
+	Value string
+}
+
+// SizeDistribution draws value sizes in bytes.
+type SizeDistribution func(rng *rand.Rand) int
+
+// FixedSize always draws n bytes.
+func FixedSize(n int) SizeDistribution {
+	return func(*rand.Rand) int {
+		return n
+	}
+}
+
+// UniformSize draws sizes uniformly from min to max bytes, inclusive.
+func UniformSize(min, max int) SizeDistribution {
+	return func(rng *rand.Rand) int {
+		return min + rng.IntN(max-min+1)
+	}
+}
+
+// LogNormalSize draws mostly small sizes around median bytes with a long
+// tail of large ones, which sigma widens, capped at limit bytes.
+func LogNormalSize(median, sigma float64, limit int) SizeDistribution {
+	return func(rng *rand.Rand) int {
+		size := int(median * math.Exp(sigma*rng.NormFloat64()))
+		return min(max(size, 1), limit)
+	}
+}
+
+// Ops generates n operations from the workload. The same workload always
+// generates the same operations.
+func (w Workload) Ops(n int) []WorkloadOp {
+	if w.Keys < 1 {
+		panic("Workload requires at least one key")
+	}
+
+	if w.Skew != 0 && w.Skew <= 1 {
+		panic(fmt.Sprintf("Workload skew must be 0 or greater than 1, got %g", w.Skew))
+	}
+
+	rng := rand.New(rand.NewPCG(w.Seed, w.Seed))
+
+	key := func() int {
+		return rng.IntN(w.Keys)
+	}
+	if w.Skew != 0 {
+		zipf := rand.NewZipf(rng, w.Skew, 1, uint64(w.Keys-1))
+		key = func() int {
+			return int(zipf.Uint64())
+		}
+	}
+
+	valueSize := w.ValueSize
+	if valueSize == nil {
+		valueSize = FixedSize(100)
+	}
+
+	ops := make([]WorkloadOp, n)
+	for i := range ops {
+		op := WorkloadOp{Write: rng.Float64() >= w.Reads, Key: fmt.Sprintf("key%d", key())}
+		if op.Write {
+			op.Value = workloadValue(rng, valueSize(rng))
+		}
+
+		ops[i] = op
+	}
+
+	return ops
+}
+
+// workloadValue generates a printable value of size bytes.
+func workloadValue(rng *rand.Rand, size int) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	value := make([]byte, size)
+	for i := range value {
+		value[i] = alphabet[rng.IntN(len(alphabet))]
+	}
+
+	return string(value)
+}
@@ -0,0 +1,209 @@
+package attest
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// wsGUID is the fixed GUID used to derive Sec-WebSocket-Accept (RFC 6455 §1.3).
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// maxWSMessageSize caps a frame, and a reassembled message, so a malformed
+// or hostile length can't exhaust memory.
+const maxWSMessageSize = 16 << 20
+
+// errWSMessageTooLarge is returned when a frame or message exceeds maxWSMessageSize.
+var errWSMessageTooLarge = fmt.Errorf("Message exceeds %s", formatBytes(maxWSMessageSize))
+
+// wsConn is a minimal RFC 6455 client connection.
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialWS opens a connection to addr, wrapped by wrap, and performs the
+// websocket handshake for path.
+func dialWS(network, addr, host, path string, timeout time.Duration, wrap func(net.Conn) net.Conn) (*wsConn, error) {
+	conn, err := net.DialTimeout(network, addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn = wrap(conn)
+
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", host, path), nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("handshake failed: expected status 101, got %d", resp.StatusCode)
+	}
+
+	sum := sha1.Sum([]byte(key + wsGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+	if resp.Header.Get("Sec-WebSocket-Accept") != accept {
+		conn.Close()
+		return nil, fmt.Errorf("handshake failed: invalid Sec-WebSocket-Accept %q", resp.Header.Get("Sec-WebSocket-Accept"))
+	}
+
+	conn.SetDeadline(time.Time{})
+	return &wsConn{conn: conn, reader: reader}, nil
+}
+
+// writeFrame writes a single masked frame, as required for clients.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+
+	length := len(payload)
+	switch {
+	case length < 126:
+		header = append(header, 0x80|byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 0x80|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, 0x80|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	mask := make([]byte, 4)
+	rand.Read(mask)
+	header = append(header, mask...)
+
+	masked := make([]byte, length)
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+
+	_, err := c.conn.Write(append(header, masked...))
+	return err
+}
+
+// readFrame reads a single frame and returns its fin bit, opcode and payload.
+func (c *wsConn) readFrame() (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := head[0]&0x80 != 0
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxWSMessageSize {
+		return false, 0, nil, errWSMessageTooLarge
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// readMessage reads the next text or binary message, reassembling fragments
+// and answering pings along the way. It returns io.EOF once the server closes.
+func (c *wsConn) readMessage(deadline time.Time) (string, error) {
+	c.conn.SetReadDeadline(deadline)
+
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return "", err
+		}
+
+		switch opcode {
+		case wsText, wsBinary, wsContinuation:
+			if len(message)+len(payload) > maxWSMessageSize {
+				return "", errWSMessageTooLarge
+			}
+			message = append(message, payload...)
+			if fin {
+				return string(message), nil
+			}
+		case wsPing:
+			c.writeFrame(wsPong, payload)
+		case wsClose:
+			return "", io.EOF
+		}
+	}
+}
+
+// close sends a close frame and closes the underlying connection.
+func (c *wsConn) close() {
+	c.conn.SetWriteDeadline(time.Now().Add(responseIdleTimeout))
+	c.writeFrame(wsClose, nil)
+	c.conn.Close()
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/st3v3nmw/lsfr/internal/attest"
+	"github.com/st3v3nmw/lsfr/internal/config"
+	"github.com/st3v3nmw/lsfr/internal/registry"
+	commands "github.com/urfave/cli/v3"
+)
+
+// certificatePath is where the completion certificate is saved, next to lsfr.yaml.
+const certificatePath = "lsfr-certificate.json"
+
+// Certificate records the completion of a challenge. It's informational only:
+// it's signed with a key kept in the user's config directory and the public
+// half is embedded, so the signature catches edits made after it was issued
+// but anyone can sign a certificate of their own.
+type Certificate struct {
+	Challenge string             `json:"challenge"`
+	Name      string             `json:"name"`
+	Stages    []CertificateStage `json:"stages"`
+	Issued    time.Time          `json:"issued"`
+	Version   string             `json:"lsfr_version"`
+
+	// PublicKey verifies Signature, both base64-encoded
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// CertificateStage is a completed stage. Stages completed before completion
+// times were recorded have none.
+type CertificateStage struct {
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Completed time.Time `json:"completed,omitzero"`
+	Bonus     bool      `json:"bonus,omitempty"`
+}
+
+// payload returns the bytes that are signed, the certificate without its signature.
+func (c Certificate) payload() ([]byte, error) {
+	c.Signature = ""
+	return json.Marshal(c)
+}
+
+// issueCertificate signs a certificate for the completed challenge and saves it.
+func issueCertificate(cfg *config.Config, challenge *registry.Challenge) error {
+	key, err := loadSigningKey()
+	if err != nil {
+		return err
+	}
+
+	cert, err := newCertificate(cfg, challenge, key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cert, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to serialize certificate: %w", err)
+	}
+
+	err = os.WriteFile(certificatePath, append(data, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to write certificate: %w", err)
+	}
+
+	return nil
+}
+
+// newCertificate signs a certificate listing the challenge's stages and the
+// bonus stages that were completed.
+func newCertificate(cfg *config.Config, challenge *registry.Challenge, key ed25519.PrivateKey) (*Certificate, error) {
+	cert := &Certificate{
+		Challenge: challenge.Key,
+		Name:      challenge.Name,
+		Issued:    time.Now().UTC().Truncate(time.Second),
+		Version:   attest.Version,
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}
+
+	stageKeys := slices.Clone(challenge.StageOrder)
+	for _, stageKey := range challenge.BonusOrder {
+		if isStageCompleted(stageKey, cfg.Stages.Completed) {
+			stageKeys = append(stageKeys, stageKey)
+		}
+	}
+
+	for _, stageKey := range stageKeys {
+		stage, err := challenge.GetStage(stageKey)
+		if err != nil {
+			return nil, err
+		}
+
+		cert.Stages = append(cert.Stages, CertificateStage{
+			Key:       stageKey,
+			Name:      stage.Name,
+			Completed: cfg.Stages.CompletedAt[stageKey],
+			Bonus:     stage.Bonus,
+		})
+	}
+
+	payload, err := cert.payload()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to serialize certificate: %w", err)
+	}
+	cert.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload))
+
+	return cert, nil
+}
+
+// verifyCertificate checks that the certificate's signature matches its contents.
+func verifyCertificate(cert *Certificate) error {
+	publicKey, err := base64.StdEncoding.DecodeString(cert.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("Certificate has an invalid public key.")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(cert.Signature)
+	if err != nil || cert.Signature == "" {
+		return fmt.Errorf("Certificate has no valid signature.")
+	}
+
+	payload, err := cert.payload()
+	if err != nil {
+		return fmt.Errorf("Failed to serialize certificate: %w", err)
+	}
+
+	if !ed25519.Verify(publicKey, payload, signature) {
+		return fmt.Errorf("Certificate signature doesn't match its contents, it was changed after it was issued.")
+	}
+
+	return nil
+}
+
+// signingKeyPath returns where the key certificates are signed with is kept.
+func signingKeyPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("Failed to find config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "lsfr", "signing-key.pem"), nil
+}
+
+// loadSigningKey reads the signing key, creating it the first time it's needed.
+func loadSigningKey() (ed25519.PrivateKey, error) {
+	path, err := signingKeyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return createSigningKey(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("Failed to parse signing key %s", path)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse signing key %s: %w", path, err)
+	}
+
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("Signing key %s isn't an Ed25519 key", path)
+	}
+
+	return key, nil
+}
+
+// createSigningKey generates a signing key and saves it to path, readable only by the user.
+func createSigningKey(path string) (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate signing key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to serialize signing key: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write signing key: %w", err)
+	}
+
+	return key, nil
+}
+
+// keyFingerprint returns a short identifier for a base64-encoded public key.
+func keyFingerprint(publicKey string) string {
+	raw, _ := base64.StdEncoding.DecodeString(publicKey)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ShowCertificate checks a completion certificate's signature and shows what
+// it records.
+func ShowCertificate(ctx context.Context, cmd *commands.Command) error {
+	path := certificatePath
+	if cmd.Args().Len() > 0 {
+		path = cmd.Args().First()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("No certificate found at %s.\nComplete every stage of a challenge, then run %s.", path, yellow("'lsfr next'"))
+	}
+
+	var cert Certificate
+	err = json.Unmarshal(data, &cert)
+	if err != nil {
+		return fmt.Errorf("Failed to parse certificate: %w", err)
+	}
+
+	err = verifyCertificate(&cert)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s (%s)\n\n", attest.Marks.Check, cert.Name, cert.Challenge)
+	for _, stage := range cert.Stages {
+		completed := ""
+		if !stage.Completed.IsZero() {
+			completed = fmt.Sprintf(" (completed %s)", stage.Completed.Local().Format(time.DateOnly))
+		}
+
+		bonus := ""
+		if stage.Bonus {
+			bonus = " (bonus)"
+		}
+
+		fmt.Printf("  %-18s - %s%s%s\n", stage.Key, stage.Name, bonus, completed)
+	}
+
+	fmt.Printf("\nIssued %s by lsfr %s\n", cert.Issued.Local().Format(time.DateTime), cert.Version)
+	fmt.Printf("Signed by key %s, the signature matches\n", keyFingerprint(cert.PublicKey))
+	fmt.Printf("Certificates are self-signed, so this is informational only.\n")
+
+	return nil
+}
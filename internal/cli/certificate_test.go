@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/st3v3nmw/lsfr/internal/attest"
+	"github.com/st3v3nmw/lsfr/internal/config"
+	"github.com/st3v3nmw/lsfr/internal/registry"
+)
+
+// certificateChallenge has two stages and two bonus stages.
+func certificateChallenge() *registry.Challenge {
+	challenge := &registry.Challenge{Key: "kv-store", Name: "Key-Value Store"}
+	for _, key := range []string{"http-api", "persistence"} {
+		challenge.AddStage(key, key, func() *attest.Suite { return attest.New() })
+	}
+	for _, key := range []string{"ttl", "compaction"} {
+		challenge.AddBonusStage(key, key, func() *attest.Suite { return attest.New() })
+	}
+
+	return challenge
+}
+
+// roundTrip signs a certificate and decodes it as ShowCertificate would.
+func roundTrip(t *testing.T, cfg *config.Config) *Certificate {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	cert, err := newCertificate(cfg, certificateChallenge(), key)
+	if err != nil {
+		t.Fatalf("failed to issue certificate: %v", err)
+	}
+
+	data, err := json.Marshal(cert)
+	if err != nil {
+		t.Fatalf("failed to serialize certificate: %v", err)
+	}
+
+	var decoded Certificate
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return &decoded
+}
+
+func TestCertificateRoundTrip(t *testing.T) {
+	completed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		Challenge: "kv-store",
+		Stages: config.Stages{
+			Completed:   []string{"http-api", "persistence", "compaction"},
+			CompletedAt: map[string]time.Time{"http-api": completed},
+		},
+	}
+
+	cert := roundTrip(t, cfg)
+	if err := verifyCertificate(cert); err != nil {
+		t.Fatalf("expected the certificate to verify: %v", err)
+	}
+
+	if cert.Version != attest.Version {
+		t.Errorf("expected version %q, got %q", attest.Version, cert.Version)
+	}
+
+	var keys []string
+	for _, stage := range cert.Stages {
+		keys = append(keys, stage.Key)
+	}
+	if got := strings.Join(keys, ","); got != "http-api,persistence,compaction" {
+		t.Errorf("expected the stages and completed bonus stages, got %s", got)
+	}
+
+	if !cert.Stages[0].Completed.Equal(completed) || !cert.Stages[1].Completed.IsZero() {
+		t.Errorf("expected completion times to be kept, got %+v", cert.Stages)
+	}
+
+	if cert.Stages[1].Bonus || !cert.Stages[2].Bonus {
+		t.Errorf("expected only compaction to be a bonus stage, got %+v", cert.Stages)
+	}
+}
+
+func TestCertificateTampered(t *testing.T) {
+	tests := []struct {
+		name     string
+		tamper   func(*Certificate)
+		expected string
+	}{
+		{
+			name:     "Stage Added",
+			tamper:   func(c *Certificate) { c.Stages = append(c.Stages, CertificateStage{Key: "ttl", Bonus: true}) },
+			expected: "doesn't match its contents",
+		},
+		{
+			name:     "Challenge Changed",
+			tamper:   func(c *Certificate) { c.Challenge = "raft" },
+			expected: "doesn't match its contents",
+		},
+		{
+			name:     "Issued Changed",
+			tamper:   func(c *Certificate) { c.Issued = c.Issued.Add(-time.Hour) },
+			expected: "doesn't match its contents",
+		},
+		{
+			name: "Key Replaced",
+			tamper: func(c *Certificate) {
+				other, _, _ := ed25519.GenerateKey(rand.Reader)
+				c.PublicKey = base64.StdEncoding.EncodeToString(other)
+			},
+			expected: "doesn't match its contents",
+		},
+		{
+			name:     "Signature Removed",
+			tamper:   func(c *Certificate) { c.Signature = "" },
+			expected: "no valid signature",
+		},
+		{
+			name:     "Invalid Key",
+			tamper:   func(c *Certificate) { c.PublicKey = "not a key" },
+			expected: "invalid public key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Stages: config.Stages{Completed: []string{"http-api", "persistence"}}}
+
+			cert := roundTrip(t, cfg)
+			tt.tamper(cert)
+
+			err := verifyCertificate(cert)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
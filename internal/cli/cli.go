@@ -3,11 +3,17 @@ package cli
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	_ "github.com/st3v3nmw/lsfr/challenges"
+	"github.com/st3v3nmw/lsfr/internal/attest"
 	"github.com/st3v3nmw/lsfr/internal/config"
 	"github.com/st3v3nmw/lsfr/internal/registry"
 	commands "github.com/urfave/cli/v3"
@@ -21,27 +27,13 @@ var (
 	yellow = color.New(color.FgYellow).SprintFunc()
 )
 
-// createChallengeFiles creates the initial project files for a new challenge.
-func createChallengeFiles(challenge *registry.Challenge, targetPath string) error {
+// createChallengeFiles creates the initial project files for a new challenge,
+// keeping its progress in storage.
+func createChallengeFiles(challenge *registry.Challenge, targetPath, storage string) error {
 	// run.sh
-	scriptPath := filepath.Join(targetPath, "run.sh")
-	scriptTemplate := `#!/bin/bash -e
-
-# This script builds and runs your implementation.
-# lsfr will execute this script to start your program.
-# "$@" passes command-line arguments from lsfr to your program, e.g.:
-#   --working-dir=<path>: Directory where your program should write files
-
-echo "Replace this line with the command that runs your implementation."
-# Examples:
-#   exec go run ./cmd/server "$@"
-#   exec python main.py "$@"
-#   exec ./my-program "$@"
-`
-
-	err := os.WriteFile(scriptPath, []byte(scriptTemplate), 0755)
+	err := writeRunScript(targetPath, "")
 	if err != nil {
-		return fmt.Errorf("Failed to create run.sh: %w", err)
+		return err
 	}
 
 	// README.md
@@ -54,6 +46,7 @@ echo "Replace this line with the command that runs your implementation."
 	// lsfr.yaml
 	cfg := &config.Config{
 		Challenge: challenge.Key,
+		Storage:   storage,
 		Stages: config.Stages{
 			Current:   challenge.StageOrder[0],
 			Completed: []string{},
@@ -76,6 +69,39 @@ echo "Replace this line with the command that runs your implementation."
 	return nil
 }
 
+// writeRunScript writes run.sh, running command if it's set and otherwise
+// asking for one.
+func writeRunScript(targetPath, command string) error {
+	run := `echo "Replace this line with the command that runs your implementation."
+# Examples:
+#   exec go run ./cmd/server "$@"
+#   exec python main.py "$@"
+#   exec ./my-program "$@"`
+	if command != "" {
+		run = command
+	}
+
+	scriptPath := filepath.Join(targetPath, "run.sh")
+	script := `#!/bin/bash -e
+
+# This script builds and runs your implementation.
+# lsfr will execute this script to start your program.
+# "$@" passes command-line arguments from lsfr to your program, e.g.:
+#   --port=<port>: Port your program should listen on
+#   --working-dir=<path>: Directory where your program should write files
+# Set "ports: env" in lsfr.yaml to get them as PORT and WORKING_DIR instead,
+# or "ports: file" to read them from ports.json in the working directory.
+
+` + run + "\n"
+
+	err := os.WriteFile(scriptPath, []byte(script), 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create run.sh: %w", err)
+	}
+
+	return nil
+}
+
 // InitChallenge initializes a challenge in the specified directory.
 func InitChallenge(ctx context.Context, cmd *commands.Command) error {
 	// Get Challenge
@@ -102,7 +128,12 @@ func InitChallenge(ctx context.Context, cmd *commands.Command) error {
 		targetPath = "."
 	}
 
-	err = createChallengeFiles(challenge, targetPath)
+	storage := ""
+	if cmd.Bool("state-dir") {
+		storage = config.StorageState
+	}
+
+	err = createChallengeFiles(challenge, targetPath, storage)
 	if err != nil {
 		return err
 	}
@@ -115,7 +146,11 @@ func InitChallenge(ctx context.Context, cmd *commands.Command) error {
 
 	fmt.Println("  run.sh       - Builds and runs your implementation")
 	fmt.Println("  README.md    - Challenge overview and requirements")
-	fmt.Println("  lsfr.yaml    - Tracks your progress")
+	if storage == config.StorageState {
+		fmt.Println("  lsfr.yaml    - Links to your progress, kept in your state directory")
+	} else {
+		fmt.Println("  lsfr.yaml    - Tracks your progress")
+	}
 	fmt.Printf("  .gitignore   - Ignores .lsfr/ working directory (server files and logs)\n\n")
 
 	firstStageKey := challenge.StageOrder[0]
@@ -138,22 +173,88 @@ func isStageCompleted(stageKey string, completedStages []string) bool {
 	return false
 }
 
+// findStage returns the stage, or nil if it doesn't exist.
+func findStage(challengeKey, stageKey string) *registry.Stage {
+	challenge, err := registry.GetChallenge(challengeKey)
+	if err != nil {
+		return nil
+	}
+
+	stage, err := challenge.GetStage(stageKey)
+	if err != nil {
+		return nil
+	}
+
+	return stage
+}
+
+// isBonusStage reports whether the stage is an optional bonus stage.
+func isBonusStage(challengeKey, stageKey string) bool {
+	stage := findStage(challengeKey, stageKey)
+	return stage != nil && stage.Bonus
+}
+
+// isExperimentalStage reports whether the stage is still under development.
+func isExperimentalStage(challengeKey, stageKey string) bool {
+	stage := findStage(challengeKey, stageKey)
+	return stage != nil && stage.Experimental
+}
+
 // validateEnvironment checks if run.sh exists and loads the config.
 func validateEnvironment() (*config.Config, error) {
 	if _, err := os.Stat("run.sh"); os.IsNotExist(err) {
 		return nil, fmt.Errorf("run.sh not found\nCreate an executable run.sh script that starts your implementation.")
 	}
 
+	return loadConfig()
+}
+
+// loadConfig loads the config, starting at the first stage if there's no
+// progress yet, e.g. in a fresh clone whose progress is in the state directory.
+func loadConfig() (*config.Config, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, err
 	}
 
+	if cfg.Stages.Current == "" {
+		challenge, err := registry.GetChallenge(cfg.Challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Stages.Current = challenge.StageOrder[0]
+	}
+
 	return cfg, nil
 }
 
+// runOptions controls how a stage's tests are run.
+type runOptions struct {
+	// processArgs are appended to the arguments of every process the stage starts
+	processArgs []string
+	// manualStart makes the user start processes themselves, e.g. under a debugger
+	manualStart bool
+	// experimental allows running stages that are still under development
+	experimental bool
+	// record saves the run's HTTP exchanges to a file, replay dry-runs against them
+	record string
+	replay string
+	// jitter delays each request by up to this long, drawn from seed
+	jitter time.Duration
+	seed   uint64
+	// oracle checks every HTTP operation against the challenge's model
+	oracle bool
+	// maxRPS caps requests per second, throttling only near the port limit if zero
+	maxRPS int
+	// ports is how processes are told their ports, from lsfr.yaml
+	ports string
+	// dir is the challenge directory, the current one if empty
+	dir string
+}
+
 // runStageTests runs tests for a specific stage and returns success/failure.
-func runStageTests(ctx context.Context, challengeKey, stageKey string) (bool, error) {
+func runStageTests(ctx context.Context, challengeKey, stageKey string, opts runOptions) (bool, error) {
 	challenge, err := registry.GetChallenge(challengeKey)
 	if err != nil {
 		return false, err
@@ -165,16 +266,92 @@ func runStageTests(ctx context.Context, challengeKey, stageKey string) (bool, er
 		for _, stage := range challenge.StageOrder {
 			msg += fmt.Sprintf("- %s\n", stage)
 		}
+		for _, stage := range challenge.BonusOrder {
+			msg += fmt.Sprintf("- %s (bonus)\n", stage)
+		}
+		if opts.experimental {
+			for _, stage := range challenge.ExperimentalOrder {
+				msg += fmt.Sprintf("- %s (experimental)\n", stage)
+			}
+		}
 
 		return false, fmt.Errorf("%w\n%s", err, msg)
 	}
 
-	suite := stage.Fn()
+	if stage.Experimental && !opts.experimental {
+		return false, fmt.Errorf("Stage %q is experimental and may change.\nRun %s to try it.",
+			stageKey, yellow(fmt.Sprintf("'lsfr test --experimental %s'", stageKey)))
+	}
+
+	portPassing, err := attest.ParsePortPassing(opts.ports)
+	if err != nil {
+		return false, fmt.Errorf("%w in lsfr.yaml", err)
+	}
+
+	suite := stage.Fn().WithStage(challengeKey, stageKey).WithProcessArgs(opts.processArgs...)
+	if opts.dir != "" {
+		dir, err := filepath.Abs(opts.dir)
+		if err != nil {
+			return false, err
+		}
+
+		suite.InDir(dir)
+	}
+	// Challenges may pick a convention themselves, which lsfr.yaml only overrides
+	if portPassing != attest.PortFlag {
+		suite.WithPortPassing(portPassing)
+	}
+	if opts.manualStart {
+		suite.ManualStart()
+	}
+
+	if opts.record != "" {
+		suite.Record(opts.record)
+	}
+
+	if opts.replay != "" {
+		suite.Replay(opts.replay)
+	}
+
+	if opts.jitter > 0 {
+		suite.Jitter(opts.jitter, opts.seed)
+	}
+
+	if opts.maxRPS != 0 {
+		suite.WithMaxRequestsPerSecond(opts.maxRPS)
+	}
+
+	if opts.oracle {
+		if challenge.Oracle == nil {
+			return false, fmt.Errorf("The %s challenge has no reference model to check against.", challenge.Name)
+		}
+
+		suite.Oracle(challenge.Oracle)
+	}
+
 	fmt.Printf("Testing %s: %s\n\n", stageKey, stage.Name)
 	passed := suite.Run(ctx)
 	return passed, nil
 }
 
+// splitPassthroughArgs splits command arguments into regular arguments and
+// those given after "--", which are passed through to run.sh.
+func splitPassthroughArgs(args []string) ([]string, []string) {
+	// The CLI parser drops "--" itself, so find it in the raw arguments
+	for i, arg := range os.Args {
+		if arg == "--" {
+			passthrough := len(os.Args) - i - 1
+			if passthrough > len(args) {
+				passthrough = len(args)
+			}
+
+			return args[:len(args)-passthrough], args[len(args)-passthrough:]
+		}
+	}
+
+	return args, nil
+}
+
 // TestStage runs tests for the current or specified stage.
 func TestStage(ctx context.Context, cmd *commands.Command) error {
 	cfg, err := validateEnvironment()
@@ -185,7 +362,8 @@ func TestStage(ctx context.Context, cmd *commands.Command) error {
 	var challengeKey string
 	var stageKey string
 
-	switch cmd.NArg() {
+	args, processArgs := splitPassthroughArgs(cmd.Args().Slice())
+	switch len(args) {
 	case 0:
 		// Use current stage from config
 		challengeKey = cfg.Challenge
@@ -193,14 +371,48 @@ func TestStage(ctx context.Context, cmd *commands.Command) error {
 	case 1:
 		// lsfr test <stage>
 		challengeKey = cfg.Challenge
-		stageKey = cmd.Args().Slice()[0]
+		stageKey = args[0]
 	default:
-		return fmt.Errorf("Too many arguments.\nUsage: lsfr test [stage]")
+		return fmt.Errorf("Too many arguments.\nUsage: lsfr test [stage] [-- <args for run.sh>]")
+	}
+
+	opts := runOptions{
+		processArgs:  processArgs,
+		manualStart:  cmd.Bool("manual-start"),
+		experimental: cmd.Bool("experimental"),
+		record:       cmd.String("record"),
+		replay:       cmd.String("replay"),
+		jitter:       cmd.Duration("jitter"),
+		seed:         cmd.Uint64("seed"),
+		oracle:       cmd.Bool("oracle"),
+		maxRPS:       int(cmd.Int("max-rps")),
+		ports:        cfg.Ports,
+	}
+
+	if opts.record != "" && opts.replay != "" {
+		return fmt.Errorf("--record and --replay can't be used together.")
+	}
+
+	passed, err := runStageTests(ctx, challengeKey, stageKey, opts)
+	if err != nil {
+		return err
 	}
 
-	passed, err := runStageTests(ctx, challengeKey, stageKey)
 	if passed {
-		fmt.Printf("\nRun %s to advance to the next stage.\n", yellow("'lsfr next'"))
+		if opts.replay != "" {
+			fmt.Printf("\nDry run passed: the stage's assertions can be satisfied.\n")
+		} else if isExperimentalStage(challengeKey, stageKey) {
+			fmt.Printf("\nExperimental stage passed. Run %s to continue with your current stage.\n", yellow("'lsfr test'"))
+		} else if isBonusStage(challengeKey, stageKey) {
+			err = completeBonusStage(cfg, stageKey)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\nBonus stage complete. Run %s to continue with your current stage.\n", yellow("'lsfr test'"))
+		} else {
+			fmt.Printf("\nRun %s to advance to the next stage.\n", yellow("'lsfr next'"))
+		}
 	} else {
 		guideURL := fmt.Sprintf("%s/%s/%s", DocsBaseURL, challengeKey, stageKey)
 		err = fmt.Errorf("\nRead the guide: \033]8;;%s\033\\%s/%s/%s\033]8;;\033\\\n", guideURL, DocsBaseURL, challengeKey, stageKey)
@@ -209,6 +421,77 @@ func TestStage(ctx context.Context, cmd *commands.Command) error {
 	return err
 }
 
+// completeBonusStage records a passed bonus stage, adding it to the completion
+// certificate if one was already issued.
+func completeBonusStage(cfg *config.Config, stageKey string) error {
+	if isStageCompleted(stageKey, cfg.Stages.Completed) {
+		return nil
+	}
+
+	cfg.Stages.Completed = append(cfg.Stages.Completed, stageKey)
+	if cfg.Stages.CompletedAt == nil {
+		cfg.Stages.CompletedAt = make(map[string]time.Time)
+	}
+	cfg.Stages.CompletedAt[stageKey] = time.Now().UTC().Truncate(time.Second)
+
+	err := config.Save(cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(certificatePath); err != nil {
+		return nil
+	}
+
+	challenge, err := registry.GetChallenge(cfg.Challenge)
+	if err != nil {
+		return err
+	}
+
+	return issueCertificate(cfg, challenge)
+}
+
+// VerifyStage re-runs a completed stage without changing the current stage.
+func VerifyStage(ctx context.Context, cmd *commands.Command) error {
+	cfg, err := validateEnvironment()
+	if err != nil {
+		return err
+	}
+
+	args, processArgs := splitPassthroughArgs(cmd.Args().Slice())
+	if len(args) != 1 {
+		return fmt.Errorf("Stage name is required.\nUsage: lsfr verify <stage> [-- <args for run.sh>]")
+	}
+
+	stageKey := args[0]
+	if !isStageCompleted(stageKey, cfg.Stages.Completed) {
+		return fmt.Errorf("Stage '%s' hasn't been completed yet.\nRun %s to test it.", stageKey, yellow(fmt.Sprintf("'lsfr test %s'", stageKey)))
+	}
+
+	passed, err := runStageTests(ctx, cfg.Challenge, stageKey, runOptions{processArgs: processArgs, ports: cfg.Ports})
+	if err != nil {
+		return err
+	}
+
+	if !passed {
+		guideURL := fmt.Sprintf("%s/%s/%s", DocsBaseURL, cfg.Challenge, stageKey)
+		return fmt.Errorf("\n%s no longer passes. Read the guide: \033]8;;%s\033\\%s/%s/%s\033]8;;\033\\\n", stageKey, guideURL, DocsBaseURL, cfg.Challenge, stageKey)
+	}
+
+	if cfg.Stages.Verified == nil {
+		cfg.Stages.Verified = make(map[string]time.Time)
+	}
+	cfg.Stages.Verified[stageKey] = time.Now().UTC().Truncate(time.Second)
+
+	err = config.Save(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s still passes. Run %s to continue with %s.\n", stageKey, yellow("'lsfr test'"), cfg.Stages.Current)
+	return nil
+}
+
 // NextStage advances to the next stage after verifying current stage is complete.
 func NextStage(ctx context.Context, cmd *commands.Command) error {
 	// Get Challenge
@@ -230,7 +513,7 @@ func NextStage(ctx context.Context, cmd *commands.Command) error {
 
 	isCurrentCompleted := isStageCompleted(cfg.Stages.Current, cfg.Stages.Completed)
 	if !isCurrentCompleted {
-		passed, err := runStageTests(ctx, cfg.Challenge, cfg.Stages.Current)
+		passed, err := runStageTests(ctx, cfg.Challenge, cfg.Stages.Current, runOptions{ports: cfg.Ports})
 		if err != nil {
 			return err
 		}
@@ -242,15 +525,37 @@ func NextStage(ctx context.Context, cmd *commands.Command) error {
 		}
 
 		cfg.Stages.Completed = append(cfg.Stages.Completed, cfg.Stages.Current)
+		if cfg.Stages.CompletedAt == nil {
+			cfg.Stages.CompletedAt = make(map[string]time.Time)
+		}
+		cfg.Stages.CompletedAt[cfg.Stages.Current] = time.Now().UTC().Truncate(time.Second)
 	}
 
 	// Check if already at final stage
 	if currentIndex == challenge.Len()-1 {
-		fmt.Printf("You've completed all stages for %s! 🎉\n\n", cfg.Challenge)
+		celebration := ""
+		if attest.Marks.Unicode {
+			celebration = " 🎉"
+		}
+
+		fmt.Printf("You've completed all stages for %s!%s\n\n", cfg.Challenge, celebration)
+
+		err = config.Save(cfg)
+		if err != nil {
+			return err
+		}
+
+		err = issueCertificate(cfg, challenge)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Saved your signed completion certificate to %s, check it with %s.\n\n",
+			certificatePath, yellow("'lsfr certificate'"))
+
 		fmt.Printf("If you're on GitHub, consider adding 'lsfr' and 'lsfr-<language>' (e.g., 'lsfr-go', 'lsfr-rust') as topics to your repository.\n\n")
 		fmt.Printf("Try another challenge at \033]8;;%s/\033\\%s\033]8;;\033\\\n", DocsBaseURL, DocsBaseURL)
 
-		return config.Save(cfg)
+		return nil
 	}
 
 	// Advance to next stage
@@ -277,7 +582,7 @@ func NextStage(ctx context.Context, cmd *commands.Command) error {
 // ShowStatus displays the current challenge progress and next steps.
 func ShowStatus(ctx context.Context, cmd *commands.Command) error {
 	// Summary
-	cfg, err := config.Load()
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
@@ -298,15 +603,33 @@ func ShowStatus(ctx context.Context, cmd *commands.Command) error {
 		}
 
 		isCompleted := isStageCompleted(stageKey, cfg.Stages.Completed)
-		if isCompleted {
-			fmt.Printf("✓ %-18s - %s\n", stageKey, stage.Name)
+		if verified, ok := cfg.Stages.Verified[stageKey]; ok && isCompleted {
+			fmt.Printf("%s %-18s - %s (verified %s)\n", attest.Marks.Check, stageKey, stage.Name, verified.Local().Format(time.DateOnly))
+		} else if isCompleted {
+			fmt.Printf("%s %-18s - %s\n", attest.Marks.Check, stageKey, stage.Name)
 		} else if stageKey == cfg.Stages.Current {
-			fmt.Printf("→ %-18s - %s\n", stageKey, stage.Name)
+			fmt.Printf("%s %-18s - %s\n", attest.Marks.Arrow, stageKey, stage.Name)
 		} else {
 			fmt.Printf("  %-18s - %s\n", stageKey, stage.Name)
 		}
 	}
 
+	if len(challenge.BonusOrder) > 0 {
+		fmt.Println("\nBonus:")
+		for _, stageKey := range challenge.BonusOrder {
+			stage, err := challenge.GetStage(stageKey)
+			if err != nil {
+				continue
+			}
+
+			if isStageCompleted(stageKey, cfg.Stages.Completed) {
+				fmt.Printf("%s %-18s - %s\n", attest.Marks.Check, stageKey, stage.Name)
+			} else {
+				fmt.Printf("  %-18s - %s\n", stageKey, stage.Name)
+			}
+		}
+	}
+
 	// Next steps
 	guideURL := fmt.Sprintf("%s/%s/%s", DocsBaseURL, cfg.Challenge, cfg.Stages.Current)
 	fmt.Printf("\nRead the guide: \033]8;;%s\033\\%s/%s/%s\033]8;;\033\\\n\n", guideURL, DocsBaseURL, cfg.Challenge, cfg.Stages.Current)
@@ -328,3 +651,127 @@ func ListChallenges(ctx context.Context, cmd *commands.Command) error {
 
 	return nil
 }
+
+// ListProcesses displays the processes harness runs left running, e.g. after
+// an interrupted run, and kills them with --kill.
+func ListProcesses(ctx context.Context, cmd *commands.Command) error {
+	workingDir := attest.DefaultConfig().WorkingDir
+
+	if cmd.Bool("kill") {
+		killed, err := attest.KillProcesses(workingDir)
+		if err != nil {
+			return fmt.Errorf("Failed to kill processes: %w", err)
+		}
+
+		for _, proc := range killed {
+			fmt.Printf("Killed %s (pid %d)\n", proc.Name, proc.PID)
+		}
+
+		if len(killed) == 0 {
+			fmt.Println("No processes running.")
+		}
+
+		return nil
+	}
+
+	running, err := attest.RunningProcesses(workingDir)
+	if err != nil {
+		return fmt.Errorf("Failed to read process state: %w", err)
+	}
+
+	if len(running) == 0 {
+		fmt.Println("No processes running.")
+		return nil
+	}
+
+	fmt.Printf("%-12s %-8s %-24s %-10s %s\n", "NAME", "PID", "ADDRESS", "UPTIME", "RUN")
+	for _, proc := range running {
+		addr := fmt.Sprintf("localhost:%d", proc.Port)
+		if proc.Socket != "" {
+			addr = "unix:" + filepath.Base(proc.Socket)
+		}
+
+		uptime := time.Since(proc.Started).Round(time.Second)
+		fmt.Printf("%-12s %-8d %-24s %-10s %s\n", proc.Name, proc.PID, addr, uptime, proc.RunDir)
+	}
+
+	fmt.Printf("\nRun %s to stop them.\n", yellow("'lsfr ps --kill'"))
+
+	return nil
+}
+
+// ExplainFailure expands on the first failure of the latest run with the full
+// HTTP exchange and what the processes logged, without re-running the tests.
+func ExplainFailure(ctx context.Context, cmd *commands.Command) error {
+	results, err := attest.LatestResults(attest.DefaultConfig().WorkingDir)
+	if err != nil {
+		return fmt.Errorf("No test results found.\nRun %s first.", yellow("'lsfr test'"))
+	}
+
+	test := results.FirstFailure()
+	if test == nil {
+		fmt.Printf("The latest run passed, there's nothing to explain.\n")
+		return nil
+	}
+
+	failure := test.Failure
+	fmt.Printf("%s %s\n", attest.Marks.Cross, test.Name)
+	fmt.Printf("\n%s\n", failure.Message)
+
+	if failure.Steps != "" {
+		fmt.Printf("\n  Previous steps: %s\n", failure.Steps)
+	}
+
+	if exchange := failure.Exchange; exchange != nil {
+		fmt.Printf("\nLast request (trace %s):\n\n", exchange.Trace)
+		fmt.Printf("  %s %s\n", exchange.Method, exchange.URL)
+		printHeaders(exchange.RequestHeader)
+		printBody(exchange.RequestBody)
+
+		if exchange.Error != "" {
+			fmt.Printf("\nNo response: %s\n", exchange.Error)
+		} else {
+			fmt.Printf("\nResponse:\n\n")
+			fmt.Printf("  %s %d %s\n", exchange.Proto, exchange.Status, http.StatusText(exchange.Status))
+			printHeaders(exchange.ResponseHeader)
+			printBody(exchange.ResponseBody)
+		}
+	}
+
+	names := slices.Sorted(maps.Keys(failure.Logs))
+	for _, name := range names {
+		fmt.Printf("\nLast lines of %s.log during the test:\n\n", name)
+		for _, line := range strings.Split(failure.Logs[name], "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	fmt.Printf("\nRun directory: %s\n", results.RunDir)
+	if results.Challenge != "" && results.Stage != "" {
+		guideURL := fmt.Sprintf("%s/%s/%s", DocsBaseURL, results.Challenge, results.Stage)
+		fmt.Printf("Read the guide: \033]8;;%s\033\\%s/%s/%s\033]8;;\033\\\n", guideURL, DocsBaseURL, results.Challenge, results.Stage)
+	}
+
+	return nil
+}
+
+// printHeaders prints HTTP headers sorted by name.
+func printHeaders(header http.Header) {
+	for _, name := range slices.Sorted(maps.Keys(header)) {
+		for _, value := range header[name] {
+			fmt.Printf("  %s: %s\n", name, value)
+		}
+	}
+}
+
+// printBody prints an HTTP body after a blank line, if there's one.
+func printBody(body string) {
+	if body == "" {
+		return
+	}
+
+	fmt.Println()
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+}
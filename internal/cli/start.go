@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/st3v3nmw/lsfr/internal/attest"
+	"github.com/st3v3nmw/lsfr/internal/registry"
+	commands "github.com/urfave/cli/v3"
+)
+
+// language is a language run.sh can be set up for.
+type language struct {
+	Name string
+	// Command runs the implementation, or is empty if the user writes it
+	Command string
+}
+
+var languages = []language{
+	{Name: "Go", Command: `exec go run ./cmd/server "$@"`},
+	{Name: "Python", Command: `exec python3 main.py "$@"`},
+	{Name: "Node.js", Command: `exec node main.js "$@"`},
+	{Name: "Rust", Command: `exec cargo run --release --quiet -- "$@"`},
+	{Name: "Other", Command: ""},
+}
+
+// prompter reads answers from stdin, giving up when the context is cancelled.
+type prompter struct {
+	lines chan string
+}
+
+func newPrompter() *prompter {
+	p := &prompter{lines: make(chan string)}
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			p.lines <- strings.TrimSpace(scanner.Text())
+		}
+		close(p.lines)
+	}()
+
+	return p
+}
+
+// ask prints question and returns the answer, or def if it's empty.
+func (p *prompter) ask(ctx context.Context, question, def string) (string, error) {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", question, def)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+
+	select {
+	case <-ctx.Done():
+		fmt.Println()
+		return "", ctx.Err()
+	case line, ok := <-p.lines:
+		if !ok {
+			return "", fmt.Errorf("No answer, stdin was closed.")
+		}
+
+		if line == "" {
+			return def, nil
+		}
+
+		return line, nil
+	}
+}
+
+// choose asks for one of options by number, re-asking until it gets one.
+func (p *prompter) choose(ctx context.Context, question string, options []string) (int, error) {
+	for i, option := range options {
+		fmt.Printf("  %d. %s\n", i+1, option)
+	}
+	fmt.Println()
+
+	for {
+		answer, err := p.ask(ctx, question, "1")
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+
+		fmt.Printf("Enter a number from 1 to %d.\n", len(options))
+	}
+}
+
+// StartChallenge walks a new user through choosing a challenge and a
+// language, creating its directory, getting run.sh to start a server, and
+// running the first stage.
+func StartChallenge(ctx context.Context, cmd *commands.Command) error {
+	p := newPrompter()
+
+	fmt.Printf("Welcome to lsfr! Let's set up your first challenge.\n\n")
+
+	// Challenge
+	all := registry.GetAllChallenges()
+	keys := slices.Sorted(maps.Keys(all))
+
+	var options []string
+	for _, key := range keys {
+		challenge := all[key]
+		options = append(options, fmt.Sprintf("%s - %s (%d stages)", key, challenge.Name, challenge.Len()))
+	}
+
+	fmt.Println("Challenges:")
+	i, err := p.choose(ctx, "Challenge", options)
+	if err != nil {
+		return err
+	}
+	challenge := all[keys[i]]
+
+	// Language
+	options = nil
+	for _, lang := range languages {
+		options = append(options, lang.Name)
+	}
+
+	fmt.Println("\nLanguages:")
+	i, err = p.choose(ctx, "Language", options)
+	if err != nil {
+		return err
+	}
+	lang := languages[i]
+
+	// Directory
+	fmt.Println()
+	targetPath, err := p.ask(ctx, "Directory", challenge.Key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(targetPath, "lsfr.yaml")); err == nil {
+		return fmt.Errorf("%s already has a challenge.\nRun %s there to continue it.", targetPath, yellow("'lsfr status'"))
+	}
+
+	err = os.MkdirAll(targetPath, 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory %s: %w", targetPath, err)
+	}
+
+	err = createChallengeFiles(challenge, targetPath, "")
+	if err != nil {
+		return err
+	}
+
+	err = writeRunScript(targetPath, lang.Command)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nCreated %s in ./%s, with run.sh, README.md, lsfr.yaml and .gitignore.\n\n", challenge.Name, targetPath)
+
+	// run.sh
+	firstStageKey := challenge.StageOrder[0]
+	guideURL := fmt.Sprintf("%s/%s/%s", DocsBaseURL, challenge.Key, firstStageKey)
+	if lang.Command == "" {
+		fmt.Printf("Edit run.sh to start your implementation, a server that listens on the port passed with --port.\n")
+	} else {
+		fmt.Printf("run.sh runs: %s\n\nWrite a server there that listens on the port passed with --port.\n", lang.Command)
+	}
+	fmt.Printf("Read the guide: \033]8;;%s\033\\%s\033]8;;\033\\\n\n", guideURL, guideURL)
+
+	for {
+		answer, err := p.ask(ctx, "Press Enter to check that run.sh starts it, or q to stop", "")
+		if err != nil {
+			return err
+		}
+
+		if answer == "q" {
+			fmt.Printf("\ncd %s and run %s when you're ready.\n", targetPath, yellow("'lsfr test'"))
+			return nil
+		}
+
+		err = checkRunScript(ctx, targetPath)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		fmt.Printf("\n%s %v\n\n", attest.Marks.Cross, err)
+	}
+
+	fmt.Printf("\n%s run.sh starts a server that accepts connections.\n\n", attest.Marks.Check)
+
+	// First stage
+	passed, err := runStageTests(ctx, challenge.Key, firstStageKey, runOptions{dir: targetPath})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if targetPath != "." {
+		fmt.Printf("From now on, work in ./%s. ", targetPath)
+	}
+	if passed {
+		fmt.Printf("Run %s to advance to the next stage.\n", yellow("'lsfr next'"))
+	} else {
+		fmt.Printf("Run %s after each change until %s passes.\n", yellow("'lsfr test'"), firstStageKey)
+	}
+
+	return nil
+}
+
+// checkRunScript starts run.sh in dir the way the tests do and checks that
+// it accepts connections on the port it's given.
+func checkRunScript(ctx context.Context, dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	defaults := attest.DefaultConfig()
+	return attest.CheckStart(ctx, &attest.Config{
+		Dir:        dir,
+		WorkingDir: filepath.Join(dir, defaults.WorkingDir),
+	})
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// helperEnv makes the test binary act as a server when run.sh starts it.
+const helperEnv = "LSFR_CLI_HELPER_PROCESS"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) == "1" {
+		serveHelper(os.Args[1:])
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// serveHelper accepts connections on the port passed with --port until
+// killed, once it finds the directory passed with --working-dir.
+func serveHelper(args []string) {
+	var port, workingDir string
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--port="); ok {
+			port = value
+		}
+		if value, ok := strings.CutPrefix(arg, "--working-dir="); ok {
+			workingDir = value
+		}
+	}
+
+	if _, err := os.Stat(workingDir); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:"+port)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}
+}
+
+func TestCheckRunScript(t *testing.T) {
+	t.Setenv(helperEnv, "1")
+
+	tests := []struct {
+		name     string
+		script   string
+		mode     os.FileMode
+		expected string
+	}{
+		{
+			name: "Accepts Connections",
+			// run.sh runs in the challenge directory
+			script: fmt.Sprintf("test -f run.sh || exit 4\nexec %q \"$@\"", os.Args[0]),
+			mode:   0755,
+		},
+		{
+			name:     "Exits Early",
+			script:   "echo 'cannot find main.go'\nexit 3",
+			mode:     0755,
+			expected: "exited with status 3 before accepting connections",
+		},
+		{
+			name:     "Not Executable",
+			script:   "exit 0",
+			mode:     0644,
+			expected: "chmod +x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Relative like the directory `lsfr start` creates
+			t.Chdir(t.TempDir())
+			dir := "challenge"
+
+			err := os.MkdirAll(dir, 0755)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\n"+tt.script+"\n"), tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkRunScript(context.Background(), dir)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("expected run.sh to pass the check, got: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected an error containing %q, got %v", tt.expected, err)
+			}
+
+			if tt.name == "Exits Early" && !strings.Contains(err.Error(), "cannot find main.go") {
+				t.Errorf("expected the error to show run.sh's output, got %v", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/goccy/go-yaml"
 )
@@ -13,15 +14,26 @@ const configPath = "lsfr.yaml"
 type Stages struct {
 	Current   string   `yaml:"current"`
 	Completed []string `yaml:"completed"`
+	// CompletedAt records when each stage was completed
+	CompletedAt map[string]time.Time `yaml:"completed_at,omitempty"`
+	// Verified records when each completed stage was last re-verified
+	Verified map[string]time.Time `yaml:"verified,omitempty"`
 }
 
 // Config represents the lsfr.yaml configuration file structure.
 type Config struct {
 	Challenge string `yaml:"challenge"`
-	Stages    Stages `yaml:"stages"`
+	// Storage is where progress is kept, StorageFile if it's empty
+	Storage string `yaml:"storage,omitempty"`
+	// ProgressID identifies the progress kept in the state directory
+	ProgressID string `yaml:"progress_id,omitempty"`
+	// Ports is how run.sh is told its ports: flag, env or file, flag if it's empty
+	Ports  string `yaml:"ports,omitempty"`
+	Stages Stages `yaml:"stages"`
 }
 
-// Load reads and parses the lsfr.yaml configuration file.
+// Load reads and parses the lsfr.yaml configuration file, then loads the
+// progress from its storage.
 func Load() (*Config, error) {
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("Not in a challenge directory\nRun this command from a directory created with 'lsfr init <challenge>'")
@@ -38,6 +50,26 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("Failed to parse config file: %w", err)
 	}
 
+	// Switching to the state directory by hand leaves no ID, and a new one
+	// on every run would never find the progress saved under the last
+	if cfg.Storage == StorageState && cfg.ProgressID == "" {
+		cfg.ProgressID = newProgressID()
+		err = writeFile(&cfg, configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	store, err := cfg.store(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.Load(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	if cfg.Stages.Completed == nil {
 		cfg.Stages.Completed = []string{}
 	}
@@ -50,9 +82,20 @@ func Save(cfg *Config) error {
 	return SaveTo(cfg, configPath)
 }
 
-// SaveTo writes the configuration to the specified path.
+// SaveTo writes the configuration to the specified path, and the progress to
+// its storage.
 func SaveTo(cfg *Config, path string) error {
-	bytes, err := yaml.Marshal(cfg)
+	store, err := cfg.store(path)
+	if err != nil {
+		return err
+	}
+
+	return store.Save(cfg)
+}
+
+// writeFile writes v as YAML to path.
+func writeFile(v any, path string) error {
+	bytes, err := yaml.Marshal(v)
 	if err != nil {
 		return fmt.Errorf("Failed to serialize config: %w", err)
 	}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Where progress can be kept.
+const (
+	// StorageFile keeps progress in lsfr.yaml, next to the code.
+	StorageFile = "file"
+	// StorageState keeps progress in the user's state directory, so it
+	// survives deleting the challenge directory and is shared by every
+	// clone of it on the machine.
+	StorageState = "state"
+)
+
+// Store keeps a challenge's progress.
+type Store interface {
+	// Load fills in the progress of cfg, whose lsfr.yaml has been read.
+	Load(cfg *Config) error
+	// Save writes cfg and its progress.
+	Save(cfg *Config) error
+}
+
+// store returns where cfg, read from or written to path, keeps its progress.
+func (c *Config) store(path string) (Store, error) {
+	switch c.Storage {
+	case "", StorageFile:
+		return &fileStore{path: path}, nil
+	case StorageState:
+		if c.ProgressID == "" {
+			c.ProgressID = newProgressID()
+		}
+
+		dir, err := stateDir()
+		if err != nil {
+			return nil, err
+		}
+
+		return &stateStore{
+			configPath:   path,
+			progressPath: filepath.Join(dir, fmt.Sprintf("%s-%s.yaml", c.Challenge, c.ProgressID)),
+		}, nil
+	default:
+		return nil, fmt.Errorf("Unknown storage %q in lsfr.yaml, expected %q or %q", c.Storage, StorageFile, StorageState)
+	}
+}
+
+// newProgressID returns a random ID for progress kept in the state directory.
+func newProgressID() string {
+	return rand.Text()[:16]
+}
+
+// fileStore keeps progress in lsfr.yaml.
+type fileStore struct {
+	path string
+}
+
+// Load does nothing as the progress was read with the rest of lsfr.yaml.
+func (s *fileStore) Load(cfg *Config) error {
+	return nil
+}
+
+func (s *fileStore) Save(cfg *Config) error {
+	return writeFile(cfg, s.path)
+}
+
+// stateStore keeps progress in the user's state directory, under an ID
+// recorded in lsfr.yaml so clones of the challenge directory share it.
+type stateStore struct {
+	configPath   string
+	progressPath string
+}
+
+// stateConfig is what lsfr.yaml holds when progress is kept elsewhere.
+type stateConfig struct {
+	Challenge  string `yaml:"challenge"`
+	Storage    string `yaml:"storage"`
+	ProgressID string `yaml:"progress_id"`
+	Ports      string `yaml:"ports,omitempty"`
+}
+
+// Load replaces the progress read from lsfr.yaml, if any, with the state
+// directory's. Progress in lsfr.yaml is kept until the first save, e.g.
+// after switching storage.
+func (s *stateStore) Load(cfg *Config) error {
+	bytes, err := os.ReadFile(s.progressPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to read progress: %w", err)
+	}
+
+	var stages Stages
+	err = yaml.Unmarshal(bytes, &stages)
+	if err != nil {
+		return fmt.Errorf("Failed to parse progress file %s: %w", s.progressPath, err)
+	}
+
+	cfg.Stages = stages
+	return nil
+}
+
+func (s *stateStore) Save(cfg *Config) error {
+	err := os.MkdirAll(filepath.Dir(s.progressPath), 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create %s: %w", filepath.Dir(s.progressPath), err)
+	}
+
+	err = writeFile(cfg.Stages, s.progressPath)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(stateConfig{
+		Challenge:  cfg.Challenge,
+		Storage:    cfg.Storage,
+		ProgressID: cfg.ProgressID,
+		Ports:      cfg.Ports,
+	}, s.configPath)
+}
+
+// stateDir returns where progress kept in the state directory is written,
+// following the XDG base directory specification.
+func stateDir() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("Failed to find state directory: %w", err)
+		}
+
+		dir = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(dir, "lsfr", "progress"), nil
+}
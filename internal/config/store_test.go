@@ -0,0 +1,200 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// progress is a config part way through a challenge.
+func progress(storage string) *Config {
+	completed := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	return &Config{
+		Challenge: "kv-store",
+		Storage:   storage,
+		Ports:     "env",
+		Stages: Stages{
+			Current:     "persistence",
+			Completed:   []string{"http-api"},
+			CompletedAt: map[string]time.Time{"http-api": completed},
+		},
+	}
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	saved := progress("")
+	err := Save(saved)
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("expected %+v, got %+v", saved, loaded)
+	}
+}
+
+func TestStateStoreRoundTrip(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateHome)
+
+	saved := progress(StorageState)
+	err := Save(saved)
+	if err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	if len(saved.ProgressID) != 16 {
+		t.Errorf("expected a progress ID to be assigned, got %q", saved.ProgressID)
+	}
+
+	progressPath := filepath.Join(stateHome, "lsfr", "progress", "kv-store-"+saved.ProgressID+".yaml")
+	if _, err := os.Stat(progressPath); err != nil {
+		t.Errorf("expected progress in the state directory: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", configPath, err)
+	}
+	if strings.Contains(string(data), "stages") {
+		t.Errorf("expected no progress in %s, got:\n%s", configPath, data)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("expected %+v, got %+v", saved, loaded)
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		stateHome string
+		expected  string
+	}{
+		{
+			name:      "XDG State Home",
+			stateHome: "/xdg/state",
+			expected:  "/xdg/state/lsfr/progress",
+		},
+		{
+			name:     "Default",
+			expected: "/home/user/.local/state/lsfr/progress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", tt.stateHome)
+			t.Setenv("HOME", "/home/user")
+
+			dir, err := stateDir()
+			if err != nil {
+				t.Fatalf("failed to find state directory: %v", err)
+			}
+
+			if dir != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, dir)
+			}
+		})
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+		stages   Stages
+	}{
+		{
+			name:     "No Config",
+			expected: "Not in a challenge directory",
+		},
+		{
+			name:   "No Progress File",
+			config: "challenge: kv-store\nstorage: state\nprogress_id: abc\n",
+			stages: Stages{Completed: []string{}},
+		},
+		{
+			name:   "Progress Kept Until First Save",
+			config: "challenge: kv-store\nstorage: state\nprogress_id: abc\nstages:\n  current: persistence\n  completed: [http-api]\n",
+			stages: Stages{Current: "persistence", Completed: []string{"http-api"}},
+		},
+		{
+			name:     "Unknown Storage",
+			config:   "challenge: kv-store\nstorage: cloud\n",
+			expected: `Unknown storage "cloud"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+			if tt.config != "" {
+				err := os.WriteFile(configPath, []byte(tt.config), 0644)
+				if err != nil {
+					t.Fatalf("failed to write %s: %v", configPath, err)
+				}
+			}
+
+			cfg, err := Load()
+			if tt.expected != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expected) {
+					t.Errorf("expected an error containing %q, got %v", tt.expected, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to load: %v", err)
+			}
+
+			if !reflect.DeepEqual(cfg.Stages, tt.stages) {
+				t.Errorf("expected %+v, got %+v", tt.stages, cfg.Stages)
+			}
+		})
+	}
+}
+
+func TestLoadAssignsProgressID(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	err := os.WriteFile(configPath, []byte("challenge: kv-store\nstorage: state\n"), 0644)
+	if err != nil {
+		t.Fatalf("failed to write %s: %v", configPath, err)
+	}
+
+	first, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	// Read-only commands, e.g. lsfr status, load without saving
+	second, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	if first.ProgressID == "" || second.ProgressID != first.ProgressID {
+		t.Errorf("expected the progress ID to be kept, got %q then %q", first.ProgressID, second.ProgressID)
+	}
+}
@@ -24,12 +24,19 @@ type Challenge struct {
 	Summary    string
 	Stages     map[string]*Stage
 	StageOrder []string
+	BonusOrder []string
+	// ExperimentalOrder lists stages still under development
+	ExperimentalOrder []string
+	// Oracle creates a known-good model of the system for oracle mode, if there's one
+	Oracle func() attest.Oracle
 }
 
 // Stage represents a single stage within a challenge.
 type Stage struct {
-	Name string
-	Fn   StageFunc
+	Name         string
+	Fn           StageFunc
+	Bonus        bool
+	Experimental bool
 }
 
 // StageFunc is a function that returns a test suite for a stage.
@@ -45,6 +52,27 @@ func (c *Challenge) AddStage(key, name string, fn StageFunc) {
 	c.StageOrder = append(c.StageOrder, key)
 }
 
+// AddBonusStage adds an optional stage that can be tested but doesn't gate progression.
+func (c *Challenge) AddBonusStage(key, name string, fn StageFunc) {
+	if c.Stages == nil {
+		c.Stages = make(map[string]*Stage)
+	}
+
+	c.Stages[key] = &Stage{Name: name, Fn: fn, Bonus: true}
+	c.BonusOrder = append(c.BonusOrder, key)
+}
+
+// AddExperimentalStage adds a stage that's still under development. It only
+// runs with `lsfr test --experimental <stage>` and never gates progression.
+func (c *Challenge) AddExperimentalStage(key, name string, fn StageFunc) {
+	if c.Stages == nil {
+		c.Stages = make(map[string]*Stage)
+	}
+
+	c.Stages[key] = &Stage{Name: name, Fn: fn, Experimental: true}
+	c.ExperimentalOrder = append(c.ExperimentalOrder, key)
+}
+
 // GetStage retrieves a stage by key.
 func (c *Challenge) GetStage(key string) (*Stage, error) {
 	stage, exists := c.Stages[key]
@@ -79,6 +107,14 @@ func (c *Challenge) README() string {
 		stages += fmt.Sprintf("%d. **[%s](%s)** - %s\n", i+1, key, stageURL, c.Stages[key].Name)
 	}
 
+	if len(c.BonusOrder) > 0 {
+		stages += "\nBonus stages (optional, run with `lsfr test <stage>`):\n\n"
+		for _, key := range c.BonusOrder {
+			stageURL := fmt.Sprintf("%s/%s/%s/", DocsBaseURL, c.Key, key)
+			stages += fmt.Sprintf("- **[%s](%s)** - %s\n", key, stageURL, c.Stages[key].Name)
+		}
+	}
+
 	return fmt.Sprintf(`# %s Challenge
 
 %s
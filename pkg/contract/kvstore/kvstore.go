@@ -0,0 +1,68 @@
+// Package kvstore runs the kv-store challenge's stages against a Go
+// implementation in-process, so they can run with go test, e.g. in CI:
+//
+//	func TestStages(t *testing.T) {
+//		kvstore.Test(t, func(args []string) (http.Handler, error) {
+//			return server.New(args)
+//		}, "http-api", "persistence")
+//	}
+package kvstore
+
+import (
+	"testing"
+
+	_ "github.com/st3v3nmw/lsfr/challenges/kvstore"
+	"github.com/st3v3nmw/lsfr/internal/attest"
+	"github.com/st3v3nmw/lsfr/internal/registry"
+)
+
+// challengeKey is the key the challenge is registered under.
+const challengeKey = "kv-store"
+
+// Server creates the implementation's HTTP handler from the arguments run.sh
+// would be passed, e.g. --port=<port> and --working-dir=<path>. The harness
+// listens on the port itself. Stopping a node closes its handler if it's an
+// io.Closer, killing it abandons the handler without closing it.
+type Server = attest.Server
+
+// Stages returns the keys of the challenge's stages in order, followed by
+// its bonus stages.
+func Stages() []string {
+	challenge := mustChallenge()
+	return append(append([]string{}, challenge.StageOrder...), challenge.BonusOrder...)
+}
+
+// Test runs each stage as a subtest against handlers from server, failing
+// the subtest if the stage doesn't pass.
+func Test(t *testing.T, server Server, stages ...string) {
+	t.Helper()
+
+	challenge := mustChallenge()
+	for _, key := range stages {
+		stage, err := challenge.GetStage(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run(key, func(t *testing.T) {
+			passed := stage.Fn().
+				WithConfig(&attest.Config{WorkingDir: t.TempDir()}).
+				WithStage(challengeKey, key).
+				InProcess(server).
+				Run(t.Context())
+			if !passed {
+				t.Errorf("%s: %s failed", key, stage.Name)
+			}
+		})
+	}
+}
+
+// mustChallenge returns the registered challenge.
+func mustChallenge() *registry.Challenge {
+	challenge, err := registry.GetChallenge(challengeKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return challenge
+}
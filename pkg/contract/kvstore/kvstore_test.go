@@ -0,0 +1,126 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// store is a minimal implementation of the challenge that saves its data
+// to the working directory when closed.
+type store struct {
+	mu   sync.Mutex
+	path string
+	data map[string]string
+}
+
+func newStore(args []string) (http.Handler, error) {
+	s := &store{data: make(map[string]string)}
+	for _, arg := range args {
+		if dir, ok := strings.CutPrefix(arg, "--working-dir="); ok {
+			s.path = filepath.Join(dir, "data.json")
+		}
+	}
+
+	saved, err := os.ReadFile(s.path)
+	if err == nil {
+		err = json.Unmarshal(saved, &s.data)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/clear" {
+		if r.Method != "DELETE" {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		clear(s.data)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/kv/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !slices.Contains([]string{"GET", "PUT", "DELETE"}, r.Method) {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if key == "" {
+		http.Error(w, "key cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		value, ok := s.data[key]
+		if !ok {
+			http.Error(w, "key not found", http.StatusNotFound)
+			return
+		}
+
+		io.WriteString(w, value)
+	case "PUT":
+		value, _ := io.ReadAll(r.Body)
+		if len(value) == 0 {
+			http.Error(w, "value cannot be empty", http.StatusBadRequest)
+			return
+		}
+
+		s.data[key] = string(value)
+	case "DELETE":
+		delete(s.data, key)
+	}
+}
+
+func (s *store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0644)
+}
+
+func TestStages(t *testing.T) {
+	stages := Stages()
+
+	if len(stages) < 2 || stages[0] != "http-api" || stages[1] != "persistence" {
+		t.Errorf("expected stages to start with http-api and persistence, got %v", stages)
+	}
+
+	challenge := mustChallenge()
+	for _, key := range stages {
+		if _, err := challenge.GetStage(key); err != nil {
+			t.Errorf("expected stage %s to exist: %v", key, err)
+		}
+	}
+
+	if unique := slices.Compact(slices.Sorted(slices.Values(stages))); len(unique) != len(stages) {
+		t.Errorf("expected no duplicate stages, got %v", stages)
+	}
+}
+
+func TestReferenceImplementation(t *testing.T) {
+	Test(t, newStore, "http-api", "persistence")
+}